@@ -7,31 +7,54 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
 
 const schemaVersion = "v1"
 
+// reSchemaDir matches the per-schema directories NewStore creates, so
+// migration never touches unrelated files in a user-chosen cache dir.
+var reSchemaDir = regexp.MustCompile(`^v[0-9]+$`)
+
 type Store struct {
-	dir     string
-	ttl     time.Duration
-	enabled bool
-	now     func() time.Time
+	dir       string
+	ttl       time.Duration
+	enabled   bool
+	namespace string
+	// pathTTLs overrides ttl for URL paths under a prefix, longest first.
+	pathTTLs []pathTTL
+	now      func() time.Time
+}
+
+type pathTTL struct {
+	prefix string
+	ttl    time.Duration
 }
 
 type entry struct {
-	Schema      string `json:"schema"`
-	KeyHash     string `json:"key_hash"`
-	Method      string `json:"method"`
-	URL         string `json:"url"`
-	CreatedAt   string `json:"created_at"`
-	ExpiresAt   string `json:"expires_at"`
-	Status      int    `json:"status"`
-	ContentType string `json:"content_type,omitempty"`
-	Body        []byte `json:"body"`
+	Schema       string `json:"schema"`
+	KeyHash      string `json:"key_hash"`
+	Method       string `json:"method"`
+	URL          string `json:"url"`
+	CreatedAt    string `json:"created_at"`
+	ExpiresAt    string `json:"expires_at"`
+	Status       int    `json:"status"`
+	ContentType  string `json:"content_type,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	Body         []byte `json:"body"`
+}
+
+// Meta holds the response headers stored alongside a cached body.
+type Meta struct {
+	ContentType  string
+	LastModified string
 }
 
 type meta struct {
@@ -68,50 +91,172 @@ func NewStore(dir string, ttl time.Duration, enabled bool) (*Store, error) {
 	if err := os.WriteFile(metaPath, b, 0o644); err != nil {
 		return nil, err
 	}
+	removeStaleSchemas(dir)
 
 	return s, nil
 }
 
+// removeStaleSchemas deletes the directories of older schema versions,
+// whose entries the current version can never read. Newer versions are left
+// for the binary that wrote them, so an older tfdc sharing the cache does
+// not wipe it. Failures are ignored: stale entries only waste disk.
+func removeStaleSchemas(dir string) {
+	current, ok := schemaNumber(schemaVersion)
+	if !ok {
+		return
+	}
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, de := range dirEntries {
+		n, ok := schemaNumber(de.Name())
+		if !de.IsDir() || !ok || n >= current {
+			continue
+		}
+		_ = os.RemoveAll(filepath.Join(dir, de.Name()))
+	}
+}
+
+// schemaNumber parses a schema directory name such as "v1".
+func schemaNumber(name string) (int, bool) {
+	if !reSchemaDir.MatchString(name) {
+		return 0, false
+	}
+	n, err := strconv.Atoi(name[1:])
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// SetNamespace mixes ns into every cache key so separate environments can
+// share a cache directory without reading each other's entries. The empty
+// namespace keeps the original keys.
+func (s *Store) SetNamespace(ns string) {
+	s.namespace = strings.TrimSpace(ns)
+}
+
+// SetPathTTLs gives entries whose URL path starts with one of the prefixes
+// their own TTL, e.g. a long one for version listings and a short one for
+// search. The longest matching prefix wins; other entries keep the store's
+// TTL. Prefixes must start with "/" and TTLs must be positive.
+func (s *Store) SetPathTTLs(ttls map[string]time.Duration) error {
+	overrides := make([]pathTTL, 0, len(ttls))
+	for prefix, ttl := range ttls {
+		if !strings.HasPrefix(prefix, "/") {
+			return fmt.Errorf("cache ttl path prefix %q must start with /", prefix)
+		}
+		if ttl <= 0 {
+			return fmt.Errorf("cache ttl for %s must be positive", prefix)
+		}
+		overrides = append(overrides, pathTTL{prefix: prefix, ttl: ttl})
+	}
+	sort.Slice(overrides, func(i, j int) bool {
+		return len(overrides[i].prefix) > len(overrides[j].prefix)
+	})
+	s.pathTTLs = overrides
+	return nil
+}
+
+// LoadPathTTLs reads a JSON object mapping URL path prefixes to durations
+// such as "168h", for SetPathTTLs.
+func LoadPathTTLs(path string) (map[string]time.Duration, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]string
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, fmt.Errorf("invalid cache ttl file %s: %v", path, err)
+	}
+	ttls := make(map[string]time.Duration, len(raw))
+	for prefix, v := range raw {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cache ttl for %s in %s: %v", prefix, path, err)
+		}
+		ttls[prefix] = d
+	}
+	return ttls, nil
+}
+
+// ttlFor returns the TTL for an entry stored under rawURL.
+func (s *Store) ttlFor(rawURL string) time.Duration {
+	if len(s.pathTTLs) == 0 {
+		return s.ttl
+	}
+	path := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		path = u.Path
+	}
+	for _, o := range s.pathTTLs {
+		if strings.HasPrefix(path, o.prefix) {
+			return o.ttl
+		}
+	}
+	return s.ttl
+}
+
 func (s *Store) Get(method, rawURL string) ([]byte, bool, error) {
+	b, _, ok, err := s.GetWithContentType(method, rawURL)
+	return b, ok, err
+}
+
+// GetWithContentType is like Get but also returns the Content-Type recorded
+// when the entry was stored.
+func (s *Store) GetWithContentType(method, rawURL string) ([]byte, string, bool, error) {
+	b, m, ok, err := s.GetWithMeta(method, rawURL)
+	return b, m.ContentType, ok, err
+}
+
+// GetWithMeta is like Get but also returns the headers recorded when the
+// entry was stored.
+func (s *Store) GetWithMeta(method, rawURL string) ([]byte, Meta, bool, error) {
 	if !s.enabled {
-		return nil, false, nil
+		return nil, Meta{}, false, nil
 	}
 	path, keyHash := s.entryPath(method, rawURL)
 
 	b, err := os.ReadFile(path)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
-			return nil, false, nil
+			return nil, Meta{}, false, nil
 		}
-		return nil, false, err
+		return nil, Meta{}, false, err
 	}
 
 	var e entry
 	if err := json.Unmarshal(b, &e); err != nil {
 		_ = os.Remove(path)
-		return nil, false, nil
+		return nil, Meta{}, false, nil
 	}
 
 	if e.Schema != schemaVersion || e.KeyHash != keyHash {
 		_ = os.Remove(path)
-		return nil, false, nil
+		return nil, Meta{}, false, nil
 	}
 
 	expiresAt, err := time.Parse(time.RFC3339Nano, e.ExpiresAt)
 	if err != nil {
 		_ = os.Remove(path)
-		return nil, false, nil
+		return nil, Meta{}, false, nil
 	}
 
 	if s.now().After(expiresAt) {
 		_ = os.Remove(path)
-		return nil, false, nil
+		return nil, Meta{}, false, nil
 	}
 
-	return e.Body, true, nil
+	return e.Body, Meta{ContentType: e.ContentType, LastModified: e.LastModified}, true, nil
 }
 
 func (s *Store) Set(method, rawURL string, status int, contentType string, body []byte) error {
+	return s.SetWithMeta(method, rawURL, status, Meta{ContentType: contentType}, body)
+}
+
+// SetWithMeta is like Set but records every header in m with the entry.
+func (s *Store) SetWithMeta(method, rawURL string, status int, m Meta, body []byte) error {
 	if !s.enabled {
 		return nil
 	}
@@ -122,15 +267,16 @@ func (s *Store) Set(method, rawURL string, status int, contentType string, body
 
 	now := s.now().UTC()
 	e := entry{
-		Schema:      schemaVersion,
-		KeyHash:     keyHash,
-		Method:      strings.ToUpper(method),
-		URL:         rawURL,
-		CreatedAt:   now.Format(time.RFC3339Nano),
-		ExpiresAt:   now.Add(s.ttl).Format(time.RFC3339Nano),
-		Status:      status,
-		ContentType: contentType,
-		Body:        body,
+		Schema:       schemaVersion,
+		KeyHash:      keyHash,
+		Method:       strings.ToUpper(method),
+		URL:          rawURL,
+		CreatedAt:    now.Format(time.RFC3339Nano),
+		ExpiresAt:    now.Add(s.ttlFor(rawURL)).Format(time.RFC3339Nano),
+		Status:       status,
+		ContentType:  m.ContentType,
+		LastModified: m.LastModified,
+		Body:         body,
 	}
 
 	b, err := json.Marshal(e)
@@ -146,7 +292,11 @@ func (s *Store) Set(method, rawURL string, status int, contentType string, body
 }
 
 func (s *Store) entryPath(method, rawURL string) (string, string) {
-	h := sha256.Sum256([]byte(strings.ToUpper(method) + " " + rawURL))
+	key := strings.ToUpper(method) + " " + rawURL
+	if s.namespace != "" {
+		key = s.namespace + "\n" + key
+	}
+	h := sha256.Sum256([]byte(key))
 	keyHash := hex.EncodeToString(h[:])
 	prefix := keyHash[:2]
 	return filepath.Join(s.dir, schemaVersion, "entries", prefix, keyHash+".json"), keyHash
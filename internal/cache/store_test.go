@@ -85,3 +85,142 @@ func TestStoreHitMissTTLAndNoCache(t *testing.T) {
 		}
 	})
 }
+
+func TestNewStoreRemovesOlderSchemaVersions(t *testing.T) {
+	dir := t.TempDir()
+	for _, p := range []string{
+		filepath.Join(dir, "v0", "entries", "ab", "old.json"),
+		filepath.Join(dir, "v1", "entries", "cd", "current.json"),
+		filepath.Join(dir, "v2", "entries", "ef", "newer.json"),
+		filepath.Join(dir, "notes", "keep.txt"),
+	} {
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("{}"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := NewStore(dir, time.Hour, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "v0")); !os.IsNotExist(err) {
+		t.Fatalf("expected v0 to be removed, stat err=%v", err)
+	}
+	for _, p := range []string{
+		filepath.Join(dir, "v1", "entries", "cd", "current.json"),
+		filepath.Join(dir, "v1", "meta.json"),
+		filepath.Join(dir, "v2", "entries", "ef", "newer.json"),
+		filepath.Join(dir, "notes", "keep.txt"),
+	} {
+		if _, err := os.Stat(p); err != nil {
+			t.Fatalf("expected %s to be preserved: %v", p, err)
+		}
+	}
+}
+
+func TestStoreNamespaceIsolatesEntries(t *testing.T) {
+	dir := t.TempDir()
+	const url = "https://example.com/v2/provider-docs/1"
+
+	paths := make(map[string]string)
+	for _, ns := range []string{"", "staging", "prod"} {
+		store, err := NewStore(dir, time.Hour, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		store.SetNamespace(ns)
+		if err := store.Set("GET", url, 200, "application/json", []byte(ns)); err != nil {
+			t.Fatal(err)
+		}
+		path, _ := store.entryPath("GET", url)
+		for other, otherPath := range paths {
+			if path == otherPath {
+				t.Fatalf("namespaces %q and %q share cache file %s", ns, other, path)
+			}
+		}
+		paths[ns] = path
+	}
+
+	// The default namespace keeps the original METHOD+URL key.
+	plain, err := NewStore(dir, time.Hour, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, ok, err := plain.Get("GET", url)
+	if err != nil || !ok || string(b) != "" {
+		t.Fatalf("expected default-namespace entry, got %q ok=%v err=%v", b, ok, err)
+	}
+
+	staging, err := NewStore(dir, time.Hour, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	staging.SetNamespace("staging")
+	b, ok, err = staging.Get("GET", url)
+	if err != nil || !ok || string(b) != "staging" {
+		t.Fatalf("expected staging entry, got %q ok=%v err=%v", b, ok, err)
+	}
+}
+
+func TestStorePathTTLsExpireOnTheirOwnSchedule(t *testing.T) {
+	store, err := NewStore(t.TempDir(), time.Hour, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetPathTTLs(map[string]time.Duration{
+		"/v1/providers":           7 * 24 * time.Hour,
+		"/v1/providers/acme/fast": time.Minute,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2026, 2, 12, 10, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	urls := map[string]string{
+		"versions": "https://example.com/v1/providers/hashicorp/aws/versions",
+		"fast":     "https://example.com/v1/providers/acme/fast/versions",
+		"default":  "https://example.com/v2/provider-docs?filter[category]=guides",
+	}
+	for _, u := range urls {
+		if err := store.Set("GET", u, 200, "application/json", []byte(`{}`)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tc := range []struct {
+		after time.Duration
+		hits  map[string]bool
+	}{
+		{after: 30 * time.Second, hits: map[string]bool{"versions": true, "fast": true, "default": true}},
+		{after: 2 * time.Minute, hits: map[string]bool{"versions": true, "fast": false, "default": true}},
+		{after: 2 * time.Hour, hits: map[string]bool{"versions": true, "fast": false, "default": false}},
+		{after: 8 * 24 * time.Hour, hits: map[string]bool{"versions": false, "fast": false, "default": false}},
+	} {
+		store.now = func() time.Time { return now.Add(tc.after) }
+		for name, want := range tc.hits {
+			_, ok, err := store.Get("GET", urls[name])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != want {
+				t.Fatalf("after %s: %s hit=%v, want %v", tc.after, name, ok, want)
+			}
+		}
+	}
+}
+
+func TestStoreSetPathTTLsValidates(t *testing.T) {
+	store, err := NewStore(t.TempDir(), time.Hour, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetPathTTLs(map[string]time.Duration{"v1/providers": time.Hour}); err == nil {
+		t.Fatal("expected error for a prefix without a leading slash")
+	}
+	if err := store.SetPathTTLs(map[string]time.Duration{"/v1": 0}); err == nil {
+		t.Fatal("expected error for a non-positive ttl")
+	}
+}
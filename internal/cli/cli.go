@@ -2,12 +2,15 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -25,14 +28,38 @@ import (
 type globalFlags struct {
 	chdir       string
 	timeout     time.Duration
+	deadline    time.Duration
 	retry       int
 	registryURL string
+	mirrorURL   string
 	insecure    bool
-	userAgent   string
-	debug       bool
-	cacheDir    string
-	cacheTTL    time.Duration
-	noCache     bool
+	tlsPin      string
+	// minTLS is the -min-tls-version flag; minTLSVersion its parsed
+	// crypto/tls constant.
+	minTLS        string
+	minTLSVersion uint16
+	userAgent     string
+	debug         bool
+	cacheDir      string
+	cacheTTL      time.Duration
+	noCache       bool
+	refresh       bool
+	maxIdle       int
+	noKeepAlive   bool
+	perHost       int
+	cacheNS       string
+	jitterSeed    int64
+	noRedirects   bool
+	// retrySummary prints retryStats to stderr when the command ends.
+	retrySummary bool
+	retryStats   *registry.RetryStats
+	// explain prints each registry request to explainOut (stderr).
+	explain      bool
+	explainOut   io.Writer
+	cacheTTLFile string
+	// cacheTTLs are per-path-prefix overrides of cacheTTL, loaded from
+	// cacheTTLFile.
+	cacheTTLs map[string]time.Duration
 }
 
 type CacheInitError struct {
@@ -62,7 +89,27 @@ func Execute(args []string, stdout, stderr io.Writer) int {
 		return 1
 	}
 
+	chdir, err := validateChdir(g.chdir)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return 1
+	}
+	g.chdir = chdir
+
+	if g.explain {
+		g.explainOut = stderr
+	}
+	if g.debug || g.retrySummary {
+		g.retryStats = registry.NewRetryStats()
+		defer printRetrySummary(g, stderr)
+	}
+
 	ctx := context.Background()
+	if g.deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, g.deadline)
+		defer cancel()
+	}
 	group, cmd := rest[0], rest[1]
 	subArgs := rest[2:]
 
@@ -75,6 +122,8 @@ func Execute(args []string, stdout, stderr io.Writer) int {
 		return runPolicy(ctx, g, cmd, subArgs, stdout, stderr)
 	case "guide":
 		return runGuide(ctx, g, cmd, subArgs, stdout, stderr)
+	case "schema":
+		return runSchema(cmd, subArgs, stdout, stderr)
 	default:
 		_, _ = fmt.Fprintf(stderr, "unsupported command group: %s\n", group)
 		printUsage(stderr)
@@ -82,6 +131,15 @@ func Execute(args []string, stdout, stderr io.Writer) int {
 	}
 }
 
+// printRetrySummary reports the retries made during the command. Under
+// -debug alone it stays quiet when nothing was retried.
+func printRetrySummary(g globalFlags, stderr io.Writer) {
+	if !g.retrySummary && g.retryStats.Total() == 0 {
+		return
+	}
+	_, _ = fmt.Fprint(stderr, "retry summary: "+g.retryStats.Summary())
+}
+
 // handleSubcmdResult maps the error returned by a subcommand to an exit code.
 // flag.ErrHelp means help was already printed to stdout; exit 0.
 func handleSubcmdResult(err error, stderr io.Writer) int {
@@ -96,46 +154,145 @@ func handleSubcmdResult(err error, stderr io.Writer) int {
 	return code
 }
 
+// runSchema dispatches the schema subcommands.
+func runSchema(cmd string, subArgs []string, stdout, stderr io.Writer) int {
+	switch cmd {
+	case "--help", "-h":
+		_, _ = fmt.Fprintln(stdout, "usage: tfdc schema <command>\n\ncommands:\n  manifest  print the JSON Schema for provider export _manifest.json")
+		return 0
+	case "manifest":
+		return handleSubcmdResult(runSchemaManifest(subArgs, stdout), stderr)
+	default:
+		_, _ = fmt.Fprintf(stderr, "unsupported schema command: %s\n", cmd)
+		return 1
+	}
+}
+
+func runSchemaManifest(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("schema manifest", flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &provider.ValidationError{Message: err.Error()}
+	}
+	if extra := fs.Args(); len(extra) > 0 {
+		return &provider.ValidationError{Message: fmt.Sprintf("unexpected positional arguments: %s", strings.Join(extra, ", "))}
+	}
+
+	b, err := provider.ManifestSchema()
+	if err != nil {
+		return err
+	}
+	_, err = stdout.Write(b)
+	return err
+}
+
+// addFormatFlags registers -format and its -json shorthand on fs.
+func addFormatFlags(fs *flag.FlagSet, format *string) *bool {
+	fs.StringVar(format, "format", "text", "output format: text|json|markdown, or ndjson for searches")
+	return fs.Bool("json", false, "shorthand for -format json")
+}
+
+// addTimeoutFlag registers a command-level -timeout that overrides the
+// global one for the client this command builds.
+func addTimeoutFlag(fs *flag.FlagSet, g *globalFlags) {
+	fs.DurationVar(&g.timeout, "timeout", g.timeout, "per-request HTTP timeout for this command, overriding the global -timeout (0 = no timeout)")
+}
+
+// addBase64ContentFlag registers -base64-content on a get/detail command.
+func addBase64ContentFlag(fs *flag.FlagSet) *bool {
+	return fs.Bool("base64-content", false, "base64-encode content in -format json output")
+}
+
+// resolveDetailOptions validates -base64-content against the effective
+// output format.
+func resolveDetailOptions(format string, base64Content bool) (output.DetailOptions, error) {
+	if base64Content && format != "json" {
+		return output.DetailOptions{}, &provider.ValidationError{Message: "-base64-content requires -format json"}
+	}
+	return output.DetailOptions{Base64Content: base64Content}, nil
+}
+
+// resolveFormat returns the effective output format once fs is parsed. -json
+// wins over the default but conflicts with an explicit non-json -format.
+func resolveFormat(fs *flag.FlagSet, format string, asJSON bool) (string, error) {
+	if !asJSON {
+		return format, nil
+	}
+	explicit := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "format" {
+			explicit = true
+		}
+	})
+	if explicit && format != "json" {
+		return "", &provider.ValidationError{Message: fmt.Sprintf("-json conflicts with -format %s", format)}
+	}
+	return "json", nil
+}
+
 func runProvider(ctx context.Context, g globalFlags, cmd string, subArgs []string, stdout, stderr io.Writer) int {
 	switch cmd {
 	case "--help", "-h":
-		_, _ = fmt.Fprintln(stdout, "usage: tfdc [global flags] provider <command> [flags]\n\ncommands:\n  search   search provider documentation\n  get      fetch a provider doc by ID\n  export   export provider docs to files")
+		_, _ = fmt.Fprintln(stdout, "usage: tfdc [global flags] provider <command> [flags]\n\ncommands:\n  search   search provider documentation\n  get      fetch a provider doc by ID\n  list     list providers in a namespace\n  export   export provider docs to files\n  verify   check exported files against their manifest\n  validate check every lockfile provider resolves in the registry")
 		return 0
 	case "export":
+		started := time.Now()
 		summaries, runErr := runProviderExport(ctx, g, subArgs, stdout, stderr)
+		elapsed := time.Since(started)
 		if runErr != nil {
 			if errors.Is(runErr, flag.ErrHelp) {
 				return 0
 			}
+			// A partial export still reports what was written.
+			printSummaries(summaries, elapsed, stderr)
 			code := mapErrorToExitCode(runErr)
 			_, _ = fmt.Fprintln(stderr, runErr)
 			return code
 		}
-		printSummaries(summaries, stderr)
+		printSummaries(summaries, elapsed, stderr)
 		return 0
 	case "search":
 		return handleSubcmdResult(runProviderSearch(ctx, g, subArgs, stdout, stderr), stderr)
 	case "get":
 		return handleSubcmdResult(runProviderGet(ctx, g, subArgs, stdout, stderr), stderr)
+	case "list":
+		return handleSubcmdResult(runProviderList(ctx, g, subArgs, stdout, stderr), stderr)
+	case "verify":
+		return handleSubcmdResult(runProviderVerify(subArgs, stdout), stderr)
+	case "validate":
+		return handleSubcmdResult(runProviderValidate(ctx, g, subArgs, stdout), stderr)
 	default:
 		_, _ = fmt.Fprintf(stderr, "unsupported provider command: %s\n", cmd)
 		return 1
 	}
 }
 
-func runProviderSearch(ctx context.Context, g globalFlags, args []string, stdout, _ io.Writer) error {
-	var name, namespace, service, typ, version, format string
-	var limit int
+func runProviderSearch(ctx context.Context, g globalFlags, args []string, stdout, stderr io.Writer) error {
+	var name, namespace, service, typ, version, format, api, tier, idFormat string
+	var limit, pageSize int
+	var excludeDeprecated, preserveCase, allTypes, includeLinks bool
 
 	fs := flag.NewFlagSet("provider search", flag.ContinueOnError)
 	fs.SetOutput(stdout)
-	fs.StringVar(&name, "name", "", "provider name")
+	addTimeoutFlag(fs, &g)
+	fs.StringVar(&name, "name", "", "provider name, or a comma-separated list to search several")
 	fs.StringVar(&namespace, "namespace", "hashicorp", "provider namespace")
 	fs.StringVar(&service, "service", "", "slug-like search token")
-	fs.StringVar(&typ, "type", "", "doc type: resources|data-sources|...")
+	fs.StringVar(&typ, "type", "", "doc type: resources|data-sources|... or all")
+	fs.BoolVar(&allTypes, "all-types", false, "search every doc type (same as -type all)")
 	fs.StringVar(&version, "version", "latest", "provider version or latest")
-	fs.IntVar(&limit, "limit", 20, "max results")
-	fs.StringVar(&format, "format", "text", "output format: text|json|markdown")
+	fs.IntVar(&limit, "limit", 20, "max results (0 = no limit)")
+	asJSON := addFormatFlags(fs, &format)
+	fs.StringVar(&api, "api", "auto", "doc listing API: auto|v1|v2")
+	fs.BoolVar(&excludeDeprecated, "exclude-deprecated", false, "skip docs the registry marks as deprecated")
+	fs.IntVar(&pageSize, "page-size", 0, fmt.Sprintf("v2 listing page size, max %d (0 = registry default)", provider.MaxPageSize))
+	fs.StringVar(&tier, "tier", "", "only search a provider of this tier: official|partner|community")
+	fs.BoolVar(&preserveCase, "preserve-case", false, "keep -namespace/-name case in registry requests instead of lowercasing")
+	fs.BoolVar(&includeLinks, "include-links", false, "add a link column with each doc's links.self URL")
+	fs.StringVar(&idFormat, "id-format", "bare", "provider_doc_id form: bare (123) or qualified (hashicorp/aws/6.31.0#123)")
 
 	if err := fs.Parse(args); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
@@ -146,28 +303,66 @@ func runProviderSearch(ctx context.Context, g globalFlags, args []string, stdout
 	if extra := fs.Args(); len(extra) > 0 {
 		return &provider.ValidationError{Message: fmt.Sprintf("unexpected positional arguments: %s", strings.Join(extra, ", "))}
 	}
+	format, err := resolveFormat(fs, format, *asJSON)
+	if err != nil {
+		return err
+	}
+	if allTypes {
+		if typ != "" && !strings.EqualFold(typ, provider.AllSearchTypes) {
+			return &provider.ValidationError{Message: "-all-types cannot be combined with -type"}
+		}
+		typ = provider.AllSearchTypes
+	}
+
+	switch idFormat = strings.ToLower(strings.TrimSpace(idFormat)); idFormat {
+	case "bare", "qualified":
+	default:
+		return &provider.ValidationError{Message: fmt.Sprintf("unsupported -id-format: %s (valid: bare, qualified)", idFormat)}
+	}
+	names := splitProviderNames(name)
+	if len(names) > 1 && !strings.EqualFold(strings.TrimSpace(version), "latest") {
+		return &provider.ValidationError{Message: "-version must be latest when -name lists several providers"}
+	}
 
 	client, err := buildRegistryClient(g)
 	if err != nil {
 		return err
 	}
 
-	results, err := provider.SearchDocs(ctx, client, provider.SearchOptions{
-		Name:      name,
-		Namespace: namespace,
-		Service:   service,
-		Type:      typ,
-		Version:   version,
-		Limit:     limit,
-	})
+	opts := provider.SearchOptions{
+		Name:              name,
+		Namespace:         namespace,
+		Service:           service,
+		Type:              typ,
+		Version:           version,
+		Limit:             limit,
+		API:               api,
+		ExcludeDeprecated: excludeDeprecated,
+		PageSize:          pageSize,
+		Tier:              tier,
+		PreserveCase:      preserveCase,
+	}
+	var page provider.SearchPage
+	if len(names) > 1 {
+		page, err = provider.SearchProvidersPage(ctx, client, opts, names)
+	} else {
+		if len(names) == 1 {
+			opts.Name = names[0]
+		}
+		page, err = provider.SearchDocsPage(ctx, client, opts)
+	}
 	if err != nil {
 		return err
 	}
 
-	items := make([]map[string]any, len(results))
-	for i, r := range results {
+	items := make([]map[string]any, len(page.Results))
+	for i, r := range page.Results {
+		docID := r.ProviderDocID
+		if idFormat == "qualified" {
+			docID = r.QualifiedID()
+		}
 		items[i] = map[string]any{
-			"provider_doc_id": r.ProviderDocID,
+			"provider_doc_id": docID,
 			"title":           r.Title,
 			"category":        r.Category,
 			"description":     r.Slug,
@@ -177,16 +372,218 @@ func runProviderSearch(ctx context.Context, g globalFlags, args []string, stdout
 		}
 	}
 	columns := []string{"provider_doc_id", "title", "category", "description", "provider", "namespace", "version"}
+	if includeLinks {
+		for i, r := range page.Results {
+			items[i]["link"] = r.Link
+		}
+		columns = append(columns, "link")
+	}
+	if err := output.WriteSearchWithMeta(stdout, format, items, len(items), columns, output.SearchMeta{HasMore: &page.HasMore, PagesFetched: &page.PagesFetched, ByProvider: page.ByProvider}); err != nil {
+		return err
+	}
+	if page.ByProvider != nil && !isJSONFormat(format) {
+		counts := make([]string, len(names))
+		for i, n := range names {
+			counts[i] = fmt.Sprintf("%s %d", n, page.ByProvider[n])
+		}
+		_, _ = fmt.Fprintf(stderr, "matches by provider: %s\n", strings.Join(counts, ", "))
+	}
+	if page.HasMore && !isJSONFormat(format) {
+		_, _ = fmt.Fprintf(stderr, "note: showing %d of many results; raise -limit to see more\n", len(items))
+	}
+	noteEmptyResults(stderr, format, len(items))
+	return nil
+}
+
+// splitProviderNames splits a comma-separated -name into trimmed, unique
+// provider names, keeping their order.
+func splitProviderNames(raw string) []string {
+	var names []string
+	seen := make(map[string]struct{})
+	for _, n := range strings.Split(raw, ",") {
+		n = strings.TrimSpace(n)
+		if _, dup := seen[n]; n == "" || dup {
+			continue
+		}
+		seen[n] = struct{}{}
+		names = append(names, n)
+	}
+	return names
+}
+
+// isJSONFormat reports whether format is machine-readable JSON, where
+// stderr notes for human readers are left out.
+func isJSONFormat(format string) bool {
+	return format == "json" || format == "ndjson"
+}
+
+// noteEmptyResults tells a human reader on stderr that a search matched
+// nothing, since text and markdown output is then just a header row. JSON
+// output is left to speak for itself.
+func noteEmptyResults(stderr io.Writer, format string, n int) {
+	if n == 0 && !isJSONFormat(format) {
+		_, _ = fmt.Fprintln(stderr, "no results found")
+	}
+}
+
+func runProviderList(ctx context.Context, g globalFlags, args []string, stdout, _ io.Writer) error {
+	var namespace, format, tier string
+	var limit int
+
+	fs := flag.NewFlagSet("provider list", flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	addTimeoutFlag(fs, &g)
+	fs.StringVar(&namespace, "namespace", "", "provider namespace")
+	fs.IntVar(&limit, "limit", 20, "max results")
+	fs.StringVar(&tier, "tier", "", "only list providers of this tier: official|partner|community")
+	asJSON := addFormatFlags(fs, &format)
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &provider.ValidationError{Message: err.Error()}
+	}
+	if extra := fs.Args(); len(extra) > 0 {
+		return &provider.ValidationError{Message: fmt.Sprintf("unexpected positional arguments: %s", strings.Join(extra, ", "))}
+	}
+	format, err := resolveFormat(fs, format, *asJSON)
+	if err != nil {
+		return err
+	}
+
+	client, err := buildRegistryClient(g)
+	if err != nil {
+		return err
+	}
+
+	results, err := provider.ListProviders(ctx, client, provider.ListOptions{
+		Namespace: namespace,
+		Limit:     limit,
+		Tier:      tier,
+	})
+	if err != nil {
+		return err
+	}
+
+	items := make([]map[string]any, len(results))
+	for i, r := range results {
+		items[i] = map[string]any{
+			"id":          r.ID,
+			"name":        r.Name,
+			"namespace":   r.Namespace,
+			"tier":        r.Tier,
+			"description": r.Description,
+			"downloads":   r.Downloads,
+		}
+	}
+	columns := []string{"id", "name", "namespace", "tier", "description", "downloads"}
 	return output.WriteSearch(stdout, format, items, len(items), columns)
 }
 
+func runProviderVerify(args []string, stdout io.Writer) error {
+	var manifestPath, outDir, namespace, name, version string
+
+	fs := flag.NewFlagSet("provider verify", flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	fs.StringVar(&manifestPath, "manifest", "", "path to the _manifest.json to verify")
+	fs.StringVar(&outDir, "out-dir", "", "export root the manifest paths are relative to (default: derived from -manifest)")
+	fs.StringVar(&namespace, "namespace", "", "expected provider namespace")
+	fs.StringVar(&name, "name", "", "expected provider name")
+	fs.StringVar(&version, "version", "", "expected provider version")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &provider.ValidationError{Message: err.Error()}
+	}
+	if extra := fs.Args(); len(extra) > 0 {
+		return &provider.ValidationError{Message: fmt.Sprintf("unexpected positional arguments: %s", strings.Join(extra, ", "))}
+	}
+
+	result, err := provider.VerifyManifest(provider.VerifyOptions{
+		ManifestPath: manifestPath,
+		OutDir:       outDir,
+		Namespace:    namespace,
+		Name:         name,
+		Version:      version,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(stdout, "verified %d files against %s\n", result.Checked, result.Manifest)
+	return err
+}
+
+// runProviderValidate parses the lockfile under -chdir and resolves each
+// provider's version ID, without exporting any docs.
+func runProviderValidate(ctx context.Context, g globalFlags, args []string, stdout io.Writer) error {
+	var chdir string
+
+	fs := flag.NewFlagSet("provider validate", flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	addTimeoutFlag(fs, &g)
+	fs.StringVar(&chdir, "chdir", g.chdir, "directory containing .terraform.lock.hcl (default: the global -chdir)")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &provider.ValidationError{Message: err.Error()}
+	}
+	if extra := fs.Args(); len(extra) > 0 {
+		return &provider.ValidationError{Message: fmt.Sprintf("unexpected positional arguments: %s", strings.Join(extra, ", "))}
+	}
+	if strings.TrimSpace(chdir) == "" {
+		return &provider.ValidationError{Message: "-chdir is required"}
+	}
+	chdir, err := validateChdir(chdir)
+	if err != nil {
+		return &provider.ValidationError{Message: err.Error()}
+	}
+
+	lockfilePath := resolveLockfilePath(chdir)
+	locks, err := lockfile.ParseFile(lockfilePath)
+	if err != nil {
+		return err
+	}
+	if len(locks) == 0 {
+		return &provider.NotFoundError{Message: fmt.Sprintf("no providers found in lockfile %s", lockfilePath)}
+	}
+
+	client, err := buildRegistryClient(g)
+	if err != nil {
+		return err
+	}
+	checks, validateErr := provider.ValidateLocks(ctx, client, locks)
+	for _, check := range checks {
+		if check.Err != nil {
+			_, err = fmt.Fprintf(stdout, "failed %s/%s@%s: %v\n", check.Namespace, check.Name, check.Version, check.Err)
+		} else {
+			_, err = fmt.Fprintf(stdout, "ok     %s/%s@%s provider_version_id=%s\n", check.Namespace, check.Name, check.Version, check.ProviderVersionID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return validateErr
+}
+
 func runProviderGet(ctx context.Context, g globalFlags, args []string, stdout, _ io.Writer) error {
-	var docID, format string
+	var docID, format, contentType, docIDs, docIDsFile string
+	var raw bool
 
 	fs := flag.NewFlagSet("provider get", flag.ContinueOnError)
 	fs.SetOutput(stdout)
-	fs.StringVar(&docID, "doc-id", "", "numeric provider doc ID")
-	fs.StringVar(&format, "format", "text", "output format: text|json|markdown")
+	addTimeoutFlag(fs, &g)
+	fs.StringVar(&docID, "doc-id", "", "numeric provider doc ID, or qualified as namespace/name/version#id")
+	fs.StringVar(&docIDs, "doc-ids", "", "comma-separated provider doc IDs to fetch as one batch")
+	fs.StringVar(&docIDsFile, "doc-ids-file", "", "file of provider doc IDs (one per line, # comments) to fetch as one batch")
+	asJSON := addFormatFlags(fs, &format)
+	base64Content := addBase64ContentFlag(fs)
+	fs.BoolVar(&raw, "raw", false, "emit the raw API document instead of the markdown content")
+	fs.StringVar(&contentType, "content-type", "", "override the content_type reported in -format json output")
 
 	if err := fs.Parse(args); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
@@ -197,18 +594,63 @@ func runProviderGet(ctx context.Context, g globalFlags, args []string, stdout, _
 	if extra := fs.Args(); len(extra) > 0 {
 		return &provider.ValidationError{Message: fmt.Sprintf("unexpected positional arguments: %s", strings.Join(extra, ", "))}
 	}
+	format, err := resolveFormat(fs, format, *asJSON)
+	if err != nil {
+		return err
+	}
+	detailOpts, err := resolveDetailOptions(format, *base64Content)
+	if err != nil {
+		return err
+	}
+
+	listed, err := collectDocIDs(docIDs, docIDsFile)
+	if err != nil {
+		return err
+	}
+	var batchIDs []string
+	for _, id := range listed {
+		if id = strings.TrimSpace(id); id != "" {
+			batchIDs = append(batchIDs, id)
+		}
+	}
+	if len(batchIDs) > 0 && strings.TrimSpace(docID) != "" {
+		return &provider.ValidationError{Message: "-doc-id cannot be combined with -doc-ids or -doc-ids-file"}
+	}
 
 	client, err := buildRegistryClient(g)
 	if err != nil {
 		return err
 	}
 
-	result, err := provider.GetDoc(ctx, client, docID)
+	get := provider.GetDoc
+	if raw {
+		get = provider.GetDocRaw
+	}
+	contentType = strings.TrimSpace(contentType)
+	if len(batchIDs) > 0 {
+		results := make([]output.DetailResult, 0, len(batchIDs))
+		for _, id := range batchIDs {
+			result, err := get(ctx, client, id)
+			if err != nil {
+				return err
+			}
+			if contentType != "" {
+				result.ContentType = contentType
+			}
+			results = append(results, output.NewDetailResult(result.ID, result.Content, result.ContentType, detailOpts))
+		}
+		return output.WriteDetailBatch(stdout, format, results)
+	}
+
+	result, err := get(ctx, client, docID)
 	if err != nil {
 		return err
 	}
+	if contentType != "" {
+		result.ContentType = contentType
+	}
 
-	return output.WriteDetail(stdout, format, result.ID, result.Content, result.ContentType)
+	return output.WriteDetailWithOptions(stdout, format, result.ID, result.Content, result.ContentType, detailOpts)
 }
 
 func runModule(ctx context.Context, g globalFlags, cmd string, subArgs []string, stdout, stderr io.Writer) int {
@@ -226,16 +668,19 @@ func runModule(ctx context.Context, g globalFlags, cmd string, subArgs []string,
 	}
 }
 
-func runModuleSearch(ctx context.Context, g globalFlags, args []string, stdout, _ io.Writer) error {
+func runModuleSearch(ctx context.Context, g globalFlags, args []string, stdout, stderr io.Writer) error {
 	var query, format string
 	var offset, limit int
+	var resolveLatest bool
 
 	fs := flag.NewFlagSet("module search", flag.ContinueOnError)
 	fs.SetOutput(stdout)
+	addTimeoutFlag(fs, &g)
 	fs.StringVar(&query, "query", "", "search query")
 	fs.IntVar(&offset, "offset", 0, "result offset")
 	fs.IntVar(&limit, "limit", 20, "max results")
-	fs.StringVar(&format, "format", "text", "output format: text|json|markdown")
+	fs.BoolVar(&resolveLatest, "resolve-latest", false, "collapse results to one row per module at its highest version")
+	asJSON := addFormatFlags(fs, &format)
 
 	if err := fs.Parse(args); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
@@ -246,6 +691,10 @@ func runModuleSearch(ctx context.Context, g globalFlags, args []string, stdout,
 	if extra := fs.Args(); len(extra) > 0 {
 		return &provider.ValidationError{Message: fmt.Sprintf("unexpected positional arguments: %s", strings.Join(extra, ", "))}
 	}
+	format, err := resolveFormat(fs, format, *asJSON)
+	if err != nil {
+		return err
+	}
 
 	client, err := buildRegistryClient(g)
 	if err != nil {
@@ -253,9 +702,10 @@ func runModuleSearch(ctx context.Context, g globalFlags, args []string, stdout,
 	}
 
 	results, total, err := module.SearchModules(ctx, client, module.SearchOptions{
-		Query:  query,
-		Offset: offset,
-		Limit:  limit,
+		Query:         query,
+		Offset:        offset,
+		Limit:         limit,
+		ResolveLatest: resolveLatest,
 	})
 	if err != nil {
 		return wrapModuleError(err)
@@ -273,16 +723,28 @@ func runModuleSearch(ctx context.Context, g globalFlags, args []string, stdout,
 		}
 	}
 	columns := []string{"module_id", "name", "description", "downloads", "verified", "published_at"}
-	return output.WriteSearch(stdout, format, items, total, columns)
+	if err := output.WriteSearch(stdout, format, items, total, columns); err != nil {
+		return err
+	}
+	noteEmptyResults(stderr, format, len(items))
+	return nil
 }
 
 func runModuleGet(ctx context.Context, g globalFlags, args []string, stdout, _ io.Writer) error {
-	var id, format string
+	var id, format, example string
+	var full, listExamples, inputs, outputs bool
 
 	fs := flag.NewFlagSet("module get", flag.ContinueOnError)
 	fs.SetOutput(stdout)
+	addTimeoutFlag(fs, &g)
 	fs.StringVar(&id, "id", "", "module ID (namespace/name/provider/version)")
-	fs.StringVar(&format, "format", "text", "output format: text|json|markdown")
+	asJSON := addFormatFlags(fs, &format)
+	base64Content := addBase64ContentFlag(fs)
+	fs.BoolVar(&full, "full", false, "include the raw registry JSON instead of only the readme")
+	fs.BoolVar(&listExamples, "examples", false, "list the module's examples")
+	fs.StringVar(&example, "example", "", "print the readme of the named example")
+	fs.BoolVar(&inputs, "inputs", false, "list the root module's input variables")
+	fs.BoolVar(&outputs, "outputs", false, "list the root module's outputs")
 
 	if err := fs.Parse(args); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
@@ -293,6 +755,18 @@ func runModuleGet(ctx context.Context, g globalFlags, args []string, stdout, _ i
 	if extra := fs.Args(); len(extra) > 0 {
 		return &provider.ValidationError{Message: fmt.Sprintf("unexpected positional arguments: %s", strings.Join(extra, ", "))}
 	}
+	format, err := resolveFormat(fs, format, *asJSON)
+	if err != nil {
+		return err
+	}
+	detailOpts, err := resolveDetailOptions(format, *base64Content)
+	if err != nil {
+		return err
+	}
+	example = strings.TrimSpace(example)
+	if boolCount(full, listExamples, example != "", inputs, outputs) > 1 {
+		return &provider.ValidationError{Message: "-full, -examples, -example, -inputs and -outputs are mutually exclusive"}
+	}
 
 	client, err := buildRegistryClient(g)
 	if err != nil {
@@ -304,7 +778,78 @@ func runModuleGet(ctx context.Context, g globalFlags, args []string, stdout, _ i
 		return wrapModuleError(err)
 	}
 
-	return output.WriteDetail(stdout, format, result.ID, result.Content, "text/markdown")
+	if listExamples {
+		items := make([]map[string]any, len(result.Examples))
+		for i, ex := range result.Examples {
+			items[i] = map[string]any{"name": ex.Name, "path": ex.Path, "source": ex.Source}
+		}
+		return output.WriteSearch(stdout, format, items, len(items), []string{"name", "path", "source"})
+	}
+	if inputs {
+		items := moduleInputItems(result.Metadata.Inputs, format)
+		return output.WriteSearch(stdout, format, items, len(items), []string{"name", "type", "default", "required"})
+	}
+	if outputs {
+		items := make([]map[string]any, len(result.Metadata.Outputs))
+		for i, o := range result.Metadata.Outputs {
+			items[i] = map[string]any{"name": o.Name, "description": o.Description}
+		}
+		return output.WriteSearch(stdout, format, items, len(items), []string{"name", "description"})
+	}
+	if example != "" {
+		ex, err := result.Example(example)
+		if err != nil {
+			return wrapModuleError(err)
+		}
+		if format == "json" {
+			return output.WriteJSON(stdout, ex)
+		}
+		return output.WriteDetailWithOptions(stdout, format, ex.Source, ex.Readme, "text/markdown", detailOpts)
+	}
+
+	if format == "json" {
+		if full {
+			return output.WriteJSON(stdout, struct {
+				module.Metadata
+				Raw json.RawMessage `json:"raw"`
+			}{result.Metadata, result.Raw})
+		}
+		return output.WriteJSON(stdout, result.Metadata)
+	}
+	if full {
+		return output.WriteDetailWithOptions(stdout, format, result.ID, string(result.Raw), "application/json", detailOpts)
+	}
+	return output.WriteDetailWithOptions(stdout, format, result.ID, result.Content, "text/markdown", detailOpts)
+}
+
+// moduleInputItems renders module inputs as search items. JSON keeps each
+// default as the registry sent it; table formats print non-string defaults
+// as compact JSON so lists and maps stay readable.
+func moduleInputItems(inputs []module.Input, format string) []map[string]any {
+	items := make([]map[string]any, len(inputs))
+	for i, in := range inputs {
+		var def any = in.Default
+		if !isJSONFormat(format) {
+			def = formatInputDefault(in.Default)
+		}
+		items[i] = map[string]any{"name": in.Name, "type": in.Type, "default": def, "required": in.Required}
+	}
+	return items
+}
+
+func formatInputDefault(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(b)
+	}
 }
 
 // wrapModuleError converts module package errors to provider package errors
@@ -314,9 +859,24 @@ func wrapModuleError(err error) error {
 	if errors.As(err, &mvErr) {
 		return &provider.ValidationError{Message: mvErr.Message}
 	}
+	var mnfErr *module.NotFoundError
+	if errors.As(err, &mnfErr) {
+		return &provider.NotFoundError{Message: mnfErr.Message}
+	}
 	return err
 }
 
+// boolCount returns how many of flags are set.
+func boolCount(flags ...bool) int {
+	n := 0
+	for _, f := range flags {
+		if f {
+			n++
+		}
+	}
+	return n
+}
+
 func runPolicy(ctx context.Context, g globalFlags, cmd string, subArgs []string, stdout, stderr io.Writer) int {
 	switch cmd {
 	case "--help", "-h":
@@ -332,13 +892,14 @@ func runPolicy(ctx context.Context, g globalFlags, cmd string, subArgs []string,
 	}
 }
 
-func runPolicySearch(ctx context.Context, g globalFlags, args []string, stdout, _ io.Writer) error {
+func runPolicySearch(ctx context.Context, g globalFlags, args []string, stdout, stderr io.Writer) error {
 	var query, format string
 
 	fs := flag.NewFlagSet("policy search", flag.ContinueOnError)
 	fs.SetOutput(stdout)
+	addTimeoutFlag(fs, &g)
 	fs.StringVar(&query, "query", "", "search query")
-	fs.StringVar(&format, "format", "text", "output format: text|json|markdown")
+	asJSON := addFormatFlags(fs, &format)
 
 	if err := fs.Parse(args); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
@@ -349,19 +910,23 @@ func runPolicySearch(ctx context.Context, g globalFlags, args []string, stdout,
 	if extra := fs.Args(); len(extra) > 0 {
 		return &provider.ValidationError{Message: fmt.Sprintf("unexpected positional arguments: %s", strings.Join(extra, ", "))}
 	}
+	format, err := resolveFormat(fs, format, *asJSON)
+	if err != nil {
+		return err
+	}
 
 	client, err := buildRegistryClient(g)
 	if err != nil {
 		return err
 	}
 
-	results, total, err := policy.SearchPolicies(ctx, client, query)
+	page, err := policy.SearchPoliciesPage(ctx, client, query)
 	if err != nil {
 		return wrapPolicyError(err)
 	}
 
-	items := make([]map[string]any, len(results))
-	for i, r := range results {
+	items := make([]map[string]any, len(page.Results))
+	for i, r := range page.Results {
 		items[i] = map[string]any{
 			"terraform_policy_id": r.TerraformPolicyID,
 			"name":                r.Name,
@@ -370,16 +935,25 @@ func runPolicySearch(ctx context.Context, g globalFlags, args []string, stdout,
 		}
 	}
 	columns := []string{"terraform_policy_id", "name", "title", "downloads"}
-	return output.WriteSearch(stdout, format, items, total, columns)
+	meta := output.SearchMeta{PagesFetched: &page.PagesFetched}
+	if err := output.WriteSearchWithMeta(stdout, format, items, page.Total, columns, meta); err != nil {
+		return err
+	}
+	noteEmptyResults(stderr, format, len(items))
+	return nil
 }
 
 func runPolicyGet(ctx context.Context, g globalFlags, args []string, stdout, _ io.Writer) error {
 	var id, format string
+	var raw bool
 
 	fs := flag.NewFlagSet("policy get", flag.ContinueOnError)
 	fs.SetOutput(stdout)
+	addTimeoutFlag(fs, &g)
 	fs.StringVar(&id, "id", "", "policy ID (policies/namespace/name/version)")
-	fs.StringVar(&format, "format", "text", "output format: text|json|markdown")
+	asJSON := addFormatFlags(fs, &format)
+	base64Content := addBase64ContentFlag(fs)
+	fs.BoolVar(&raw, "raw", false, "emit the raw registry JSON instead of the readme")
 
 	if err := fs.Parse(args); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
@@ -390,6 +964,14 @@ func runPolicyGet(ctx context.Context, g globalFlags, args []string, stdout, _ i
 	if extra := fs.Args(); len(extra) > 0 {
 		return &provider.ValidationError{Message: fmt.Sprintf("unexpected positional arguments: %s", strings.Join(extra, ", "))}
 	}
+	format, err := resolveFormat(fs, format, *asJSON)
+	if err != nil {
+		return err
+	}
+	detailOpts, err := resolveDetailOptions(format, *base64Content)
+	if err != nil {
+		return err
+	}
 
 	client, err := buildRegistryClient(g)
 	if err != nil {
@@ -401,7 +983,10 @@ func runPolicyGet(ctx context.Context, g globalFlags, args []string, stdout, _ i
 		return wrapPolicyError(err)
 	}
 
-	return output.WriteDetail(stdout, format, result.ID, result.Content, "text/markdown")
+	if raw {
+		return output.WriteDetailWithOptions(stdout, format, result.ID, string(result.Raw), "application/json", detailOpts)
+	}
+	return output.WriteDetailWithOptions(stdout, format, result.ID, result.Content, "text/markdown", detailOpts)
 }
 
 // wrapPolicyError converts policy package errors to provider package errors.
@@ -416,12 +1001,14 @@ func wrapPolicyError(err error) error {
 func runGuide(ctx context.Context, g globalFlags, cmd string, subArgs []string, stdout, stderr io.Writer) int {
 	switch cmd {
 	case "--help", "-h":
-		_, _ = fmt.Fprintln(stdout, "usage: tfdc [global flags] guide <command> [flags]\n\ncommands:\n  style       fetch the Terraform style guide\n  module-dev  fetch the module development guide")
+		_, _ = fmt.Fprintln(stdout, "usage: tfdc [global flags] guide <command> [flags]\n\ncommands:\n  style       fetch the Terraform style guide\n  module-dev  fetch the module development guide\n  search      search guide content for a phrase")
 		return 0
 	case "style":
 		return handleSubcmdResult(runGuideStyle(ctx, g, subArgs, stdout, stderr), stderr)
 	case "module-dev":
 		return handleSubcmdResult(runGuideModuleDev(ctx, g, subArgs, stdout, stderr), stderr)
+	case "search":
+		return handleSubcmdResult(runGuideSearch(ctx, g, subArgs, stdout, stderr), stderr)
 	default:
 		_, _ = fmt.Fprintf(stderr, "unsupported guide command: %s\n", cmd)
 		return 1
@@ -433,7 +1020,11 @@ func runGuideStyle(ctx context.Context, g globalFlags, args []string, stdout, _
 
 	fs := flag.NewFlagSet("guide style", flag.ContinueOnError)
 	fs.SetOutput(stdout)
-	fs.StringVar(&format, "format", "text", "output format: text|json|markdown")
+	addTimeoutFlag(fs, &g)
+	asJSON := addFormatFlags(fs, &format)
+	base64Content := addBase64ContentFlag(fs)
+	textOpts := addGuideTextFlags(fs)
+	locale := addLocaleFlag(fs)
 
 	if err := fs.Parse(args); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
@@ -444,18 +1035,33 @@ func runGuideStyle(ctx context.Context, g globalFlags, args []string, stdout, _
 	if extra := fs.Args(); len(extra) > 0 {
 		return &provider.ValidationError{Message: fmt.Sprintf("unexpected positional arguments: %s", strings.Join(extra, ", "))}
 	}
+	format, err := resolveFormat(fs, format, *asJSON)
+	if err != nil {
+		return err
+	}
+	detailOpts, err := resolveDetailOptions(format, *base64Content)
+	if err != nil {
+		return err
+	}
+
+	if *locale, err = guide.NormalizeLocale(*locale); err != nil {
+		return wrapGuideError(err)
+	}
 
 	client, err := buildRegistryClient(g)
 	if err != nil {
 		return err
 	}
 
-	content, err := guide.FetchStyleGuide(ctx, client)
+	content, err := guide.FetchStyleGuideForLocale(ctx, client, *locale)
 	if err != nil {
 		return err
 	}
+	if content, err = guide.ApplyTextOptions(content, *textOpts); err != nil {
+		return wrapGuideError(err)
+	}
 
-	return output.WriteDetail(stdout, format, "style-guide", content, "text/markdown")
+	return output.WriteDetailWithOptions(stdout, format, "style-guide", content, "text/markdown", detailOpts)
 }
 
 func runGuideModuleDev(ctx context.Context, g globalFlags, args []string, stdout, _ io.Writer) error {
@@ -463,8 +1069,12 @@ func runGuideModuleDev(ctx context.Context, g globalFlags, args []string, stdout
 
 	fs := flag.NewFlagSet("guide module-dev", flag.ContinueOnError)
 	fs.SetOutput(stdout)
+	addTimeoutFlag(fs, &g)
 	fs.StringVar(&section, "section", "all", "section: all|index|composition|structure|providers|publish|refactoring")
-	fs.StringVar(&format, "format", "text", "output format: text|json|markdown")
+	asJSON := addFormatFlags(fs, &format)
+	base64Content := addBase64ContentFlag(fs)
+	textOpts := addGuideTextFlags(fs)
+	locale := addLocaleFlag(fs)
 
 	if err := fs.Parse(args); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
@@ -475,22 +1085,105 @@ func runGuideModuleDev(ctx context.Context, g globalFlags, args []string, stdout
 	if extra := fs.Args(); len(extra) > 0 {
 		return &provider.ValidationError{Message: fmt.Sprintf("unexpected positional arguments: %s", strings.Join(extra, ", "))}
 	}
+	format, err := resolveFormat(fs, format, *asJSON)
+	if err != nil {
+		return err
+	}
+	detailOpts, err := resolveDetailOptions(format, *base64Content)
+	if err != nil {
+		return err
+	}
+
+	if *locale, err = guide.NormalizeLocale(*locale); err != nil {
+		return wrapGuideError(err)
+	}
 
 	client, err := buildRegistryClient(g)
 	if err != nil {
 		return err
 	}
 
-	content, err := guide.FetchModuleDevGuide(ctx, client, section)
+	content, err := guide.FetchModuleDevGuideForLocale(ctx, client, section, *locale)
 	if err != nil {
 		return wrapGuideError(err)
 	}
+	if content, err = guide.ApplyTextOptions(content, *textOpts); err != nil {
+		return wrapGuideError(err)
+	}
 
 	id := "module-dev"
 	if section != "all" && section != "" {
 		id = "module-dev/" + section
 	}
-	return output.WriteDetail(stdout, format, id, content, "text/markdown")
+	return output.WriteDetailWithOptions(stdout, format, id, content, "text/markdown", detailOpts)
+}
+
+func runGuideSearch(ctx context.Context, g globalFlags, args []string, stdout, _ io.Writer) error {
+	var query, format string
+	var includeStyle bool
+
+	fs := flag.NewFlagSet("guide search", flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	addTimeoutFlag(fs, &g)
+	fs.StringVar(&query, "query", "", "case-insensitive phrase to find in guide content")
+	fs.BoolVar(&includeStyle, "style", false, "also search the style guide")
+	locale := addLocaleFlag(fs)
+	asJSON := addFormatFlags(fs, &format)
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &provider.ValidationError{Message: err.Error()}
+	}
+	if extra := fs.Args(); len(extra) > 0 {
+		return &provider.ValidationError{Message: fmt.Sprintf("unexpected positional arguments: %s", strings.Join(extra, ", "))}
+	}
+	format, err := resolveFormat(fs, format, *asJSON)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(query) == "" {
+		return &provider.ValidationError{Message: "-query is required"}
+	}
+
+	if *locale, err = guide.NormalizeLocale(*locale); err != nil {
+		return wrapGuideError(err)
+	}
+
+	client, err := buildRegistryClient(g)
+	if err != nil {
+		return err
+	}
+
+	matches, err := guide.Search(ctx, client, guide.SearchOptions{Query: query, IncludeStyle: includeStyle, Locale: *locale})
+	if err != nil {
+		return wrapGuideError(err)
+	}
+
+	items := make([]map[string]any, 0, len(matches))
+	for _, m := range matches {
+		items = append(items, map[string]any{
+			"guide":   m.Guide,
+			"section": m.Section,
+			"matches": m.Matches,
+			"snippet": m.Snippet,
+		})
+	}
+	return output.WriteSearch(stdout, format, items, len(items), []string{"guide", "section", "matches", "snippet"})
+}
+
+// addGuideTextFlags registers -strip-bom and -eol for guide output.
+func addGuideTextFlags(fs *flag.FlagSet) *guide.TextOptions {
+	opts := &guide.TextOptions{}
+	fs.BoolVar(&opts.StripBOM, "strip-bom", false, "remove a leading UTF-8 byte order mark")
+	fs.StringVar(&opts.EOL, "eol", "keep", "line endings: keep|lf|crlf|native")
+	return opts
+}
+
+// addLocaleFlag registers -locale on the guide commands.
+func addLocaleFlag(fs *flag.FlagSet) *string {
+	return fs.String("locale", "", "BCP-47 language tag of localized guides, e.g. ja or pt-BR (default: en)")
 }
 
 // wrapGuideError converts guide package errors to provider package errors.
@@ -508,15 +1201,29 @@ func parseGlobalFlags(args []string) (globalFlags, []string, error) {
 	fs.SetOutput(io.Discard)
 
 	fs.StringVar(&g.chdir, "chdir", "", "switch to a different working directory before executing")
-	fs.DurationVar(&g.timeout, "timeout", 10*time.Second, "HTTP timeout")
+	fs.DurationVar(&g.timeout, "timeout", 10*time.Second, "per-request HTTP timeout (0 = no timeout)")
+	fs.DurationVar(&g.deadline, "deadline", 0, "overall deadline for the command (0 = none)")
 	fs.IntVar(&g.retry, "retry", 3, "retry count")
 	fs.StringVar(&g.registryURL, "registry-url", "https://registry.terraform.io", "registry base URL")
+	fs.StringVar(&g.mirrorURL, "registry-mirror", "", "fallback registry base URL tried when -registry-url fails")
 	fs.BoolVar(&g.insecure, "insecure", false, "skip TLS verification")
+	fs.StringVar(&g.tlsPin, "tls-pin", "", "hex sha256 of the registry certificate's public key; other keys are refused")
+	fs.StringVar(&g.minTLS, "min-tls-version", "", "lowest TLS version to negotiate: 1.2|1.3 (default: Go's default)")
 	fs.StringVar(&g.userAgent, "user-agent", "tfdc/dev", "custom User-Agent")
 	fs.BoolVar(&g.debug, "debug", false, "enable debug log")
 	fs.StringVar(&g.cacheDir, "cache-dir", "~/.cache/tfdc", "cache directory")
 	fs.DurationVar(&g.cacheTTL, "cache-ttl", 24*time.Hour, "cache TTL")
+	fs.StringVar(&g.cacheTTLFile, "cache-ttl-file", "", "JSON file mapping URL path prefixes to cache TTLs that override -cache-ttl")
 	fs.BoolVar(&g.noCache, "no-cache", false, "disable cache")
+	fs.BoolVar(&g.refresh, "refresh", false, "ignore cached responses but store the fresh ones")
+	fs.IntVar(&g.maxIdle, "max-idle-conns", registry.DefaultMaxIdleConnsPerHost, "max idle HTTP connections per host")
+	fs.BoolVar(&g.noKeepAlive, "disable-keepalives", false, "disable HTTP keep-alive connection reuse")
+	fs.IntVar(&g.perHost, "per-host-concurrency", 0, "max concurrent requests per host (0 = unlimited)")
+	fs.StringVar(&g.cacheNS, "cache-namespace", "", "isolate cache entries under this namespace")
+	fs.Int64Var(&g.jitterSeed, "retry-jitter-seed", 0, "seed for retry backoff jitter (0 = random)")
+	fs.BoolVar(&g.noRedirects, "no-redirects", false, "report HTTP redirects as errors instead of following them")
+	fs.BoolVar(&g.retrySummary, "retry-summary", false, "print retry counts per URL to stderr when the command ends")
+	fs.BoolVar(&g.explain, "explain", false, "print each registry URL to stderr as the command requests it")
 
 	if err := fs.Parse(args); err != nil {
 		return g, nil, err
@@ -525,6 +1232,29 @@ func parseGlobalFlags(args []string) (globalFlags, []string, error) {
 	if g.retry < 0 {
 		return g, nil, fmt.Errorf("-retry must be >= 0")
 	}
+	if g.timeout < 0 {
+		return g, nil, fmt.Errorf("-timeout must be >= 0")
+	}
+	if g.deadline < 0 {
+		return g, nil, fmt.Errorf("-deadline must be >= 0")
+	}
+	if g.maxIdle < 0 {
+		return g, nil, fmt.Errorf("-max-idle-conns must be >= 0")
+	}
+	if g.perHost < 0 {
+		return g, nil, fmt.Errorf("-per-host-concurrency must be >= 0")
+	}
+	if g.insecure && strings.TrimSpace(g.tlsPin) != "" {
+		return g, nil, fmt.Errorf("-tls-pin cannot be combined with -insecure")
+	}
+	if g.refresh && g.noCache {
+		return g, nil, fmt.Errorf("-refresh cannot be combined with -no-cache")
+	}
+	minTLSVersion, err := registry.ParseTLSVersion(g.minTLS)
+	if err != nil {
+		return g, nil, fmt.Errorf("-min-tls-version: %v", err)
+	}
+	g.minTLSVersion = minTLSVersion
 
 	if !g.noCache {
 		if g.cacheTTL <= 0 {
@@ -538,12 +1268,18 @@ func parseGlobalFlags(args []string) (globalFlags, []string, error) {
 			return g, nil, fmt.Errorf("-cache-dir must not be empty")
 		}
 		g.cacheDir = expanded
+
+		if g.cacheTTLFile != "" {
+			if g.cacheTTLs, err = cache.LoadPathTTLs(g.cacheTTLFile); err != nil {
+				return g, nil, fmt.Errorf("-cache-ttl-file: %v", err)
+			}
+		}
 	}
 
 	return g, fs.Args(), nil
 }
 
-func runProviderExport(ctx context.Context, g globalFlags, args []string, stdout, stderr io.Writer) ([]provider.ExportSummary, error) {
+func runProviderExport(ctx context.Context, g globalFlags, args []string, stdout, stderr io.Writer) (result []provider.ExportSummary, runErr error) {
 	var namespace string
 	var name string
 	var version string
@@ -552,17 +1288,95 @@ func runProviderExport(ctx context.Context, g globalFlags, args []string, stdout
 	var categories string
 	var pathTemplate string
 	var clean bool
+	var lineEndings string
+	var prefix string
+	var hardlink bool
+	var excludeDeprecated bool
+	var retryOnEmpty, detailRetries, concurrency int
+	var summaryFile string
+	var verbose bool
+	var jsonStyle string
+	var docIDs string
+	var docIDsFile string
+	var keepGoing bool
+	var pageSize int
+	var sinceModified, incremental bool
+	var progressMode string
+	var allowFile, denyFile string
+	var reportFile string
+	var trimWhitespace bool
+	var resolveOnly bool
+	var categoriesManifest string
+	var plan bool
+	var overviewReadme bool
+	var preserveCase bool
+	var errorOnEmpty bool
+	var hashAlgo string
+	var sitemapBase string
+	var validateTemplate bool
+	var runLog string
+	var stripBOM bool
+	var strictLockfile bool
+	var preserveMtime bool
+	var maxFileSize string
+	var allowSymlinkRoot bool
+	var newOnly string
+	var filenamePrefix, filenameSuffix string
+	var noEmptyManifest, compactManifest, skipForbidden bool
 
 	fs := flag.NewFlagSet("provider export", flag.ContinueOnError)
 	fs.SetOutput(stdout)
+	addTimeoutFlag(fs, &g)
 	fs.StringVar(&namespace, "namespace", "hashicorp", "provider namespace")
 	fs.StringVar(&name, "name", "", "provider name")
-	fs.StringVar(&version, "version", "", "provider version")
+	fs.StringVar(&version, "version", "", "provider version, or latest (default: the latest published version)")
 	fs.StringVar(&format, "format", "markdown", "persist format: markdown|json")
-	fs.StringVar(&outDir, "out-dir", "", "output directory")
+	fs.StringVar(&outDir, "out-dir", "", "output directory, or - to stream a tar archive to stdout")
 	fs.StringVar(&categories, "categories", "all", "categories list or all")
 	fs.StringVar(&pathTemplate, "path-template", provider.DefaultPathTemplate, "output path template")
 	fs.BoolVar(&clean, "clean", false, "remove existing provider/version subtree before export")
+	fs.BoolVar(&allowSymlinkRoot, "allow-symlink-root", false, "allow -out-dir itself to be a symlink; symlinks under it are still rejected")
+	fs.StringVar(&filenamePrefix, "filename-prefix", "", "text added before each doc's slug in {slug}, e.g. tf- for tf-aws_s3_bucket.md")
+	fs.StringVar(&filenameSuffix, "filename-suffix", "", "text added after each doc's slug in {slug}, before the extension")
+	fs.BoolVar(&noEmptyManifest, "no-empty-manifest", false, "do not write _manifest.json when no docs were exported")
+	fs.BoolVar(&compactManifest, "compact-manifest", false, "write _manifest.json as single-line JSON instead of indented")
+	fs.BoolVar(&skipForbidden, "skip-forbidden", false, "warn and skip categories whose listing returns 403 instead of failing")
+	fs.StringVar(&lineEndings, "line-endings", "lf", "line endings for markdown output: lf|crlf|native")
+	fs.StringVar(&prefix, "prefix", "", "extra path nested under -out-dir before the template layout")
+	fs.BoolVar(&hardlink, "hardlink-identical", false, "hardlink byte-identical docs across exported versions")
+	fs.BoolVar(&excludeDeprecated, "exclude-deprecated", false, "skip docs the registry marks as deprecated")
+	fs.IntVar(&retryOnEmpty, "retry-on-empty", 0, "retry an empty first listing page up to N times with backoff")
+	fs.IntVar(&detailRetries, "detail-retries", 0, "retry a failed doc detail fetch up to N more times with backoff, on top of -retry")
+	fs.IntVar(&concurrency, "concurrency", provider.DefaultExportConcurrency, "doc details fetched at once per provider (>= 1)")
+	fs.StringVar(&summaryFile, "summary-file", "", "also write the export summary as JSON to this path")
+	fs.StringVar(&runLog, "run-log", "", "append one JSON line per exported provider with the run's outcome to this file")
+	fs.BoolVar(&verbose, "verbose", false, "log every written file to stderr")
+	fs.StringVar(&jsonStyle, "json-style", "pretty", "JSON doc rendering for -format json: raw|pretty|canonical")
+	fs.StringVar(&docIDs, "doc-ids", "", "comma-separated provider doc IDs to export instead of listing categories")
+	fs.StringVar(&docIDsFile, "doc-ids-file", "", "file of provider doc IDs (one per line, # comments) to export")
+	fs.BoolVar(&strictLockfile, "strict-lockfile", false, "in lockfile mode, fail when a provider address appears more than once")
+	fs.BoolVar(&keepGoing, "keep-going", false, "in lockfile mode, continue with remaining providers after a failure")
+	fs.IntVar(&pageSize, "page-size", 0, fmt.Sprintf("docs listing page size, max %d (0 = registry default)", provider.MaxPageSize))
+	fs.StringVar(&maxFileSize, "max-file-size", "", "refuse to write docs larger than this size, e.g. 5MB (default: unlimited)")
+	fs.BoolVar(&preserveMtime, "preserve-mtime", false, "set each written doc's modification time to the registry's Last-Modified")
+	fs.BoolVar(&sinceModified, "since-modified", false, "record Last-Modified per doc and skip docs unchanged since the previous export")
+	fs.BoolVar(&incremental, "incremental", false, "leave files whose content matches the previous manifest untouched instead of rewriting them")
+	fs.StringVar(&progressMode, "progress", "auto", "progress display on stderr: auto|always|never")
+	fs.StringVar(&newOnly, "new-only", "", "export only docs missing from this previous _manifest.json and report them")
+	fs.StringVar(&allowFile, "allow-file", "", "file of exact doc slugs to export (one per line, # comments); others are skipped")
+	fs.StringVar(&denyFile, "deny-file", "", "file of exact doc slugs never to export (one per line, # comments)")
+	fs.BoolVar(&stripBOM, "strip-bom", false, "remove a leading UTF-8 byte order mark from markdown docs")
+	fs.BoolVar(&trimWhitespace, "trim-trailing-whitespace", false, "strip trailing whitespace per line and end markdown files with one newline")
+	fs.StringVar(&reportFile, "report", "", "also write a CSV of exported docs (category, slug, path, size, sha256) to this path")
+	fs.StringVar(&categoriesManifest, "categories-from-manifest", "", "export the categories listed in this previous _manifest.json instead of -categories")
+	fs.BoolVar(&preserveCase, "preserve-case", false, "keep -namespace/-name case in registry requests instead of lowercasing")
+	fs.StringVar(&hashAlgo, "hash-algo", "sha256", "digest recorded per doc in the manifest: sha256|sha512")
+	fs.StringVar(&sitemapBase, "sitemap", "", "also write sitemap.xml next to the manifest with exported docs as URLs under this base URL")
+	fs.BoolVar(&errorOnEmpty, "error-on-empty-content", false, "fail when a doc's content is empty instead of writing an empty file")
+	fs.BoolVar(&overviewReadme, "include-overview-readme", false, "also write the provider overview doc as README.md at the provider/version root")
+	fs.BoolVar(&plan, "plan", false, "print the files that would be added, modified or removed on disk without writing anything")
+	fs.BoolVar(&validateTemplate, "validate-template", false, "check -path-template offline with sample docs and exit without exporting")
+	fs.BoolVar(&resolveOnly, "resolve-only", false, "print the resolved provider-version ID and exit without listing or exporting docs")
 
 	if err := fs.Parse(args); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
@@ -573,50 +1387,400 @@ func runProviderExport(ctx context.Context, g globalFlags, args []string, stdout
 	if extra := fs.Args(); len(extra) > 0 {
 		return nil, &provider.ValidationError{Message: fmt.Sprintf("unexpected positional arguments: %s", strings.Join(extra, ", "))}
 	}
+	if concurrency < 1 {
+		return nil, &provider.ValidationError{Message: "-concurrency must be >= 1"}
+	}
 
 	resolvedLockfile := resolveLockfilePath(g.chdir)
+	if outDir != "-" {
+		outDir = resolveChdirPath(g.chdir, outDir)
+	}
+	for _, path := range []*string{&summaryFile, &reportFile, &runLog, &docIDsFile, &allowFile, &denyFile, &categoriesManifest, &newOnly} {
+		*path = resolveChdirPath(g.chdir, *path)
+	}
 
-	spinner := progress.New(stderr)
-	defer spinner.Stop()
+	if resolveOnly {
+		if resolvedLockfile != "" {
+			return nil, &provider.ValidationError{Message: "-resolve-only cannot be used in lockfile mode"}
+		}
+		client, err := buildRegistryClient(g)
+		if err != nil {
+			return nil, err
+		}
+		res, err := provider.ResolveVersion(ctx, client, namespace, name, version, preserveCase)
+		if err != nil {
+			return nil, err
+		}
+		_, err = fmt.Fprintf(stdout, "%s/%s@%s provider_version_id=%s\n", res.Namespace, res.Name, res.Version, res.ProviderVersionID)
+		return nil, err
+	}
 
-	if resolvedLockfile != "" {
-		return runLockfileExport(ctx, g, resolvedLockfile, name, version, stderr, spinner, provider.ExportOptions{
+	if validateTemplate {
+		if resolvedLockfile != "" {
+			return nil, &provider.ValidationError{Message: "-validate-template cannot be used in lockfile mode"}
+		}
+		sample, err := provider.CheckPathTemplate(provider.ExportOptions{
+			Namespace:    namespace,
+			Name:         name,
+			Version:      version,
 			Format:       strings.ToLower(format),
 			OutDir:       outDir,
-			Categories:   []string{categories},
 			PathTemplate: pathTemplate,
-			Clean:        clean,
+			Prefix:       prefix,
+			PreserveCase: preserveCase,
 		})
+		if err != nil {
+			return nil, err
+		}
+		_, err = fmt.Fprintf(stdout, "path template OK: sample doc path %s\n", sample)
+		return nil, err
 	}
 
-	// Legacy mode: -name and -version required.
-	opts := provider.ExportOptions{
-		Namespace:    namespace,
-		Name:         name,
-		Version:      version,
-		Format:       strings.ToLower(format),
-		OutDir:       outDir,
-		Categories:   []string{categories},
-		PathTemplate: pathTemplate,
-		Clean:        clean,
+	if strings.TrimSpace(runLog) != "" {
+		defer func() {
+			entries := runLogEntries(time.Now(), name, version, result, runErr)
+			if logErr := provider.AppendRunLog(runLog, entries); logErr != nil && runErr == nil {
+				runErr = logErr
+			}
+		}()
 	}
-	if err := provider.PreflightExportOptions(&opts); err != nil {
+
+	mode, err := progress.ParseMode(progressMode)
+	if err != nil {
+		return nil, &provider.ValidationError{Message: err.Error()}
+	}
+	spinner := progress.NewWithMode(stderr, mode)
+	defer spinner.Stop()
+
+	baseOpts := provider.ExportOptions{
+		Format:            strings.ToLower(format),
+		OutDir:            outDir,
+		Categories:        []string{categories},
+		PathTemplate:      pathTemplate,
+		Clean:             clean,
+		LineEndings:       lineEndings,
+		Prefix:            prefix,
+		HardlinkIdentical: hardlink,
+		ExcludeDeprecated: excludeDeprecated,
+		RetryOnEmpty:      retryOnEmpty,
+		JSONStyle:         jsonStyle,
+		PageSize:          pageSize,
+		SinceModified:     sinceModified,
+	}
+	baseOpts.TrimTrailingWhitespace = trimWhitespace
+	baseOpts.StripBOM = stripBOM
+	baseOpts.PreserveMtime = preserveMtime
+	baseOpts.AllowSymlinkRoot = allowSymlinkRoot
+	baseOpts.FilenamePrefix = filenamePrefix
+	baseOpts.FilenameSuffix = filenameSuffix
+	baseOpts.NoEmptyManifest = noEmptyManifest
+	baseOpts.CompactManifest = compactManifest
+	baseOpts.Incremental = incremental
+	baseOpts.DetailRetries = detailRetries
+	baseOpts.Concurrency = concurrency
+	baseOpts.SkipForbidden = skipForbidden
+	baseOpts.OnWarning = func(msg string) { spinner.Log("warning: " + msg) }
+	baseOpts.NewOnlyManifest = strings.TrimSpace(newOnly)
+	if baseOpts.MaxFileSize, err = parseByteSize("-max-file-size", maxFileSize); err != nil {
 		return nil, err
 	}
+	baseOpts.Plan = plan
+	baseOpts.IncludeOverviewReadme = overviewReadme
+	baseOpts.PreserveCase = preserveCase
+	baseOpts.ErrorOnEmptyContent = errorOnEmpty
+	baseOpts.HashAlgorithm = hashAlgo
+	baseOpts.SitemapBase = sitemapBase
+	if verbose {
+		baseOpts.OnFileWritten = func(relPath string) { spinner.Log("wrote " + relPath) }
+	}
+	if hardlink {
+		// One index for the whole run so identical docs are shared across
+		// every provider/version exported by this command.
+		baseOpts.LinkIndex = provider.NewLinkIndex()
+	}
+
+	if strings.TrimSpace(categoriesManifest) != "" {
+		explicitCategories := false
+		fs.Visit(func(f *flag.Flag) {
+			if f.Name == "categories" {
+				explicitCategories = true
+			}
+		})
+		if explicitCategories {
+			return nil, &provider.ValidationError{Message: "-categories-from-manifest cannot be combined with -categories"}
+		}
+		if baseOpts.Categories, err = provider.CategoriesFromManifest(categoriesManifest); err != nil {
+			return nil, err
+		}
+	}
 
-	client, err := buildRegistryClient(g)
+	explicitIDs, err := collectDocIDs(docIDs, docIDsFile)
 	if err != nil {
 		return nil, err
 	}
+	baseOpts.DocIDs = explicitIDs
+	if baseOpts.AllowSlugs, err = readSlugFile("-allow-file", allowFile); err != nil {
+		return nil, err
+	}
+	if baseOpts.DenySlugs, err = readSlugFile("-deny-file", denyFile); err != nil {
+		return nil, err
+	}
 
-	spinner.Start(fmt.Sprintf("Exporting %s/%s@%s", namespace, name, version))
-	opts.OnProgress = func(msg string) { spinner.Update(msg) }
+	// -out-dir - exports into a scratch directory and streams it to stdout
+	// as a tar archive once every provider is done.
+	streamTar := strings.TrimSpace(outDir) == "-"
+	if plan && streamTar {
+		return nil, &provider.ValidationError{Message: "-plan cannot be used with -out-dir -"}
+	}
+	if plan && strings.TrimSpace(reportFile) != "" {
+		return nil, &provider.ValidationError{Message: "-plan cannot be combined with -report"}
+	}
+	if streamTar {
+		tmpDir, err := os.MkdirTemp("", "tfdc-export-")
+		if err != nil {
+			return nil, &provider.WriteError{Path: os.TempDir(), Err: err}
+		}
+		defer func() { _ = os.RemoveAll(tmpDir) }()
+		baseOpts.OutDir = tmpDir
+	}
 
-	summary, err := provider.ExportDocs(ctx, client, opts)
+	var summaries []provider.ExportSummary
+	if resolvedLockfile != "" {
+		if len(explicitIDs) > 0 {
+			return nil, &provider.ValidationError{Message: "-doc-ids and -doc-ids-file cannot be used in lockfile mode"}
+		}
+		if baseOpts.NewOnlyManifest != "" {
+			return nil, &provider.ValidationError{Message: "-new-only cannot be used in lockfile mode"}
+		}
+		summaries, err = runLockfileExport(ctx, g, resolvedLockfile, name, version, keepGoing, strictLockfile, stderr, spinner, baseOpts)
+	} else {
+		// Legacy mode: -name and -version required.
+		opts := baseOpts
+		opts.Namespace = namespace
+		opts.Name = name
+		opts.Version = version
+		if err := provider.PreflightExportOptions(&opts); err != nil {
+			return nil, err
+		}
+
+		client, err := buildRegistryClient(g)
+		if err != nil {
+			return nil, err
+		}
+
+		spinner.Start(fmt.Sprintf("Exporting %s/%s@%s", namespace, name, version))
+		opts.OnProgress = func(msg string) { spinner.Update(msg) }
+
+		summary, exportErr := provider.ExportDocs(ctx, client, opts)
+		if exportErr != nil {
+			// Docs refused by -max-file-size still leave the rest written.
+			if summary != nil {
+				summaries = []provider.ExportSummary{*summary}
+			}
+			return summaries, exportErr
+		}
+		summaries = []provider.ExportSummary{*summary}
+	}
+	var partialErr *provider.PartialError
+	if err != nil && !errors.As(err, &partialErr) {
+		return nil, err
+	}
+
+	if plan {
+		if planErr := writeExportPlans(stdout, summaries); planErr != nil {
+			return summaries, planErr
+		}
+	}
+
+	// Written before streaming so file sizes come from the scratch tree.
+	if strings.TrimSpace(reportFile) != "" {
+		if reportErr := provider.WriteReportCSV(reportFile, summaries); reportErr != nil {
+			return summaries, reportErr
+		}
+	}
+
+	if streamTar {
+		if tarErr := provider.WriteTarArchive(stdout, baseOpts.OutDir); tarErr != nil {
+			return summaries, tarErr
+		}
+		for i := range summaries {
+			summaries[i] = streamedSummary(summaries[i], baseOpts.OutDir)
+		}
+	}
+	if writeErr := writeSummaryFile(summaryFile, summaries); writeErr != nil {
+		return summaries, writeErr
+	}
+	return summaries, err
+}
+
+// writeExportPlans prints each summary's plan as one "+ added", "~ modified"
+// or "- removed" line per file.
+func writeExportPlans(w io.Writer, summaries []provider.ExportSummary) error {
+	for _, s := range summaries {
+		if s.Plan == nil {
+			continue
+		}
+		for _, group := range []struct {
+			mark  string
+			paths []string
+		}{{"+", s.Plan.Added}, {"~", s.Plan.Modified}, {"-", s.Plan.Removed}} {
+			for _, path := range group.paths {
+				if _, err := fmt.Fprintf(w, "%s %s\n", group.mark, path); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// streamedSummary rewrites paths under the scratch directory of a streamed
+// export so they name entries inside the tar archive instead.
+func streamedSummary(s provider.ExportSummary, scratchDir string) provider.ExportSummary {
+	s.OutDir = "-"
+	if rel, err := filepath.Rel(scratchDir, s.Manifest); err == nil {
+		s.Manifest = filepath.ToSlash(rel)
+	}
+	return s
+}
+
+// collectDocIDs merges -doc-ids and the contents of -doc-ids-file. IDs are
+// validated by the provider package.
+func collectDocIDs(list, file string) ([]string, error) {
+	var ids []string
+	if strings.TrimSpace(list) != "" {
+		ids = append(ids, strings.Split(list, ",")...)
+	}
+	if strings.TrimSpace(file) != "" {
+		lines, err := readListFile("-doc-ids-file", file)
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range lines {
+			ids = append(ids, strings.Split(line, ",")...)
+		}
+		if len(ids) == 0 {
+			return nil, &provider.ValidationError{Message: fmt.Sprintf("-doc-ids-file %s contains no doc IDs", file)}
+		}
+	}
+	return ids, nil
+}
+
+// byteSizeUnits maps the accepted size suffixes to their multipliers.
+var byteSizeUnits = map[string]int64{
+	"": 1, "b": 1,
+	"k": 1 << 10, "kb": 1 << 10, "kib": 1 << 10,
+	"m": 1 << 20, "mb": 1 << 20, "mib": 1 << 20,
+	"g": 1 << 30, "gb": 1 << 30, "gib": 1 << 30,
+}
+
+// parseByteSize parses sizes like 512, 64KB or 5MB (binary multiples,
+// case-insensitive). Empty means 0, unlimited.
+func parseByteSize(flagName, s string) (int64, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return 0, nil
+	}
+	digits := strings.TrimRightFunc(s, func(r rune) bool { return r < '0' || r > '9' })
+	mult, ok := byteSizeUnits[strings.TrimSpace(s[len(digits):])]
+	n, err := strconv.ParseInt(digits, 10, 64)
+	if !ok || err != nil || n > math.MaxInt64/mult {
+		return 0, &provider.ValidationError{Message: fmt.Sprintf("invalid %s %q: use a byte count with an optional KB, MB or GB suffix", flagName, s)}
+	}
+	return n * mult, nil
+}
+
+// readListFile returns the trimmed, non-empty lines of a list file,
+// skipping # comments. flagName names the flag in errors.
+func readListFile(flagName, path string) ([]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &provider.ValidationError{Message: fmt.Sprintf("failed to read %s: %v", flagName, err)}
+	}
+	var lines []string
+	for _, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+// readSlugFile reads an -allow-file or -deny-file. An empty path yields no
+// slugs; a file without any slugs is rejected so an allowlist cannot
+// silently export nothing.
+func readSlugFile(flagName, path string) ([]string, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, nil
+	}
+	slugs, err := readListFile(flagName, path)
 	if err != nil {
 		return nil, err
 	}
-	return []provider.ExportSummary{*summary}, nil
+	if len(slugs) == 0 {
+		return nil, &provider.ValidationError{Message: fmt.Sprintf("%s %s lists no slugs", flagName, path)}
+	}
+	return slugs, nil
+}
+
+// runLogEntries builds the -run-log lines for one run: one per summary, or
+// a single line naming the requested provider when nothing was exported.
+func runLogEntries(now time.Time, name, version string, summaries []provider.ExportSummary, runErr error) []provider.RunLogEntry {
+	base := provider.RunLogEntry{Timestamp: now.UTC().Format(time.RFC3339)}
+	if runErr != nil {
+		base.ExitStatus = mapErrorToExitCode(runErr)
+		base.Error = runErr.Error()
+	}
+	if len(summaries) == 0 {
+		base.Provider, base.Version = name, version
+		return []provider.RunLogEntry{base}
+	}
+	entries := make([]provider.RunLogEntry, len(summaries))
+	for i, s := range summaries {
+		e := base
+		e.Provider, e.Version, e.Written, e.Manifest = s.Provider, s.Version, s.Written, s.Manifest
+		entries[i] = e
+	}
+	return entries
+}
+
+// writeSummaryFile persists summaries when -summary-file is set.
+func writeSummaryFile(path string, summaries []provider.ExportSummary) error {
+	if strings.TrimSpace(path) == "" {
+		return nil
+	}
+	return provider.WriteSummaryFile(path, summaries)
+}
+
+// validateChdir checks that a non-empty -chdir names an existing directory
+// and returns it as an absolute path, so a typo fails loudly instead of
+// reading as a project without a lockfile.
+func validateChdir(chdir string) (string, error) {
+	if strings.TrimSpace(chdir) == "" {
+		return "", nil
+	}
+	info, err := os.Stat(chdir)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("-chdir %s: directory does not exist", chdir)
+	}
+	if err != nil {
+		return "", fmt.Errorf("-chdir %s: %w", chdir, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("-chdir %s: not a directory", chdir)
+	}
+	return filepath.Abs(chdir)
+}
+
+// resolveChdirPath resolves a relative path against -chdir, as terraform
+// does. Absolute and empty paths, or an unset chdir, leave path unchanged.
+func resolveChdirPath(chdir, path string) string {
+	if strings.TrimSpace(chdir) == "" || strings.TrimSpace(path) == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(chdir, path)
 }
 
 func resolveLockfilePath(chdir string) string {
@@ -626,7 +1790,7 @@ func resolveLockfilePath(chdir string) string {
 	return ""
 }
 
-func runLockfileExport(ctx context.Context, g globalFlags, lockfilePath, nameFilter, versionFlag string, stderr io.Writer, spinner *progress.Spinner, baseOpts provider.ExportOptions) ([]provider.ExportSummary, error) {
+func runLockfileExport(ctx context.Context, g globalFlags, lockfilePath, nameFilter, versionFlag string, keepGoing, strict bool, stderr io.Writer, spinner *progress.Spinner, baseOpts provider.ExportOptions) ([]provider.ExportSummary, error) {
 	if strings.TrimSpace(versionFlag) != "" {
 		_, _ = fmt.Fprintln(stderr, "warning: -version is ignored when using -chdir")
 	}
@@ -635,17 +1799,24 @@ func runLockfileExport(ctx context.Context, g globalFlags, lockfilePath, nameFil
 	if err != nil {
 		return nil, err
 	}
+	if strict {
+		if err := lockfile.CheckDuplicates(lockfilePath, locks); err != nil {
+			return nil, &provider.ValidationError{Message: err.Error()}
+		}
+	}
 
 	if strings.TrimSpace(nameFilter) != "" {
-		filtered := make([]lockfile.ProviderLock, 0, 1)
-		for _, lock := range locks {
-			if strings.EqualFold(lock.Name, nameFilter) {
-				filtered = append(filtered, lock)
-			}
-		}
+		filtered := filterLocksByName(locks, nameFilter)
 		if len(filtered) == 0 {
 			return nil, &provider.NotFoundError{Message: fmt.Sprintf("provider %q not found in lockfile %s", nameFilter, lockfilePath)}
 		}
+		if len(filtered) > 1 {
+			matched := make([]string, len(filtered))
+			for i, lock := range filtered {
+				matched[i] = lock.Namespace + "/" + lock.Name
+			}
+			_, _ = fmt.Fprintf(stderr, "note: -name %q matched %d providers; exporting all: %s\n", nameFilter, len(filtered), strings.Join(matched, ", "))
+		}
 		locks = filtered
 	}
 
@@ -672,6 +1843,7 @@ func runLockfileExport(ctx context.Context, g globalFlags, lockfilePath, nameFil
 	spinner.Start(fmt.Sprintf("Exporting %d providers from lockfile", len(locks)))
 
 	summaries := make([]provider.ExportSummary, 0, len(locks))
+	var failures []error
 	for i, lock := range locks {
 		opts := baseOpts
 		opts.Namespace = lock.Namespace
@@ -684,37 +1856,116 @@ func runLockfileExport(ctx context.Context, g globalFlags, lockfilePath, nameFil
 
 		summary, exportErr := provider.ExportDocs(ctx, client, opts)
 		if exportErr != nil {
-			return nil, exportErr
+			if !keepGoing {
+				return nil, exportErr
+			}
+			failures = append(failures, fmt.Errorf("%s/%s@%s: %w", lock.Namespace, lock.Name, lock.Version, exportErr))
+			continue
 		}
 		summaries = append(summaries, *summary)
 	}
 
-	return summaries, nil
+	switch {
+	case len(failures) == 0:
+		return summaries, nil
+	case len(summaries) == 0:
+		// Nothing succeeded: report the first failure with its own exit code.
+		return nil, failures[0]
+	default:
+		return summaries, &provider.PartialError{Succeeded: len(summaries), Failed: len(failures), Errors: failures}
+	}
+}
+
+// filterLocksByName returns the locks whose provider name matches name,
+// compared in the same lowercase form validateExportOptions normalizes to.
+// A name published by several namespaces matches every one of them.
+func filterLocksByName(locks []lockfile.ProviderLock, name string) []lockfile.ProviderLock {
+	name = strings.ToLower(strings.TrimSpace(name))
+	filtered := make([]lockfile.ProviderLock, 0, 1)
+	for _, lock := range locks {
+		if strings.ToLower(lock.Name) == name {
+			filtered = append(filtered, lock)
+		}
+	}
+	return filtered
 }
 
 func buildRegistryClient(g globalFlags) (*registry.Client, error) {
+	if g.timeout < 0 {
+		return nil, &provider.ValidationError{Message: "-timeout must be >= 0"}
+	}
 	cacheStore, err := cache.NewStore(g.cacheDir, g.cacheTTL, !g.noCache)
 	if err != nil {
 		return nil, &CacheInitError{Path: g.cacheDir, Err: err}
 	}
+	cacheStore.SetNamespace(g.cacheNS)
+	if err := cacheStore.SetPathTTLs(g.cacheTTLs); err != nil {
+		return nil, &provider.ValidationError{Message: fmt.Sprintf("-cache-ttl-file: %v", err)}
+	}
 
 	return registry.NewClient(registry.Config{
-		BaseURL:   g.registryURL,
-		Timeout:   g.timeout,
-		Retry:     g.retry,
-		Insecure:  g.insecure,
-		UserAgent: g.userAgent,
-		Debug:     g.debug,
+		BaseURL:             g.registryURL,
+		MirrorURL:           g.mirrorURL,
+		Timeout:             g.timeout,
+		Retry:               g.retry,
+		Insecure:            g.insecure,
+		TLSPin:              g.tlsPin,
+		MinTLSVersion:       g.minTLSVersion,
+		Refresh:             g.refresh,
+		UserAgent:           g.userAgent,
+		Debug:               g.debug,
+		MaxIdleConnsPerHost: g.maxIdle,
+		DisableKeepAlives:   g.noKeepAlive,
+		PerHostConcurrency:  g.perHost,
+		JitterSeed:          g.jitterSeed,
+		NoRedirects:         g.noRedirects,
+		RetryStats:          g.retryStats,
+		Explain:             g.explainOut,
 	}, cacheStore)
 }
 
-func printSummaries(summaries []provider.ExportSummary, w io.Writer) {
+// printSummaries prints one line pair per exported provider and, when
+// there is more than one, an aggregate line with the command's wall-clock
+// duration.
+func printSummaries(summaries []provider.ExportSummary, elapsed time.Duration, w io.Writer) {
+	totalWritten, totalUnchanged := 0, 0
 	for _, s := range summaries {
-		_, _ = fmt.Fprintf(w, "exported %d docs for %s@%s\nmanifest: %s\n", s.Written, s.Provider, s.Version, s.Manifest)
+		if s.Plan != nil {
+			_, _ = fmt.Fprintf(w, "plan for %s@%s: %d to add, %d to change, %d to remove (%d unchanged)\n", s.Provider, s.Version, len(s.Plan.Added), len(s.Plan.Modified), len(s.Plan.Removed), len(s.Plan.Unchanged))
+			continue
+		}
+		totalWritten += s.Written
+		// Docs skipped by -incremental are unchanged too.
+		totalUnchanged += s.Unchanged + s.Skipped
+		manifest := s.Manifest
+		if manifest == "" {
+			manifest = "not written (no docs exported)"
+		}
+		_, _ = fmt.Fprintf(w, "exported %d docs%s for %s@%s\nmanifest: %s\n", s.Written, unchangedNote(s.Unchanged+s.Skipped), s.Provider, s.Version, manifest)
+		for _, slug := range s.NewSlugs {
+			_, _ = fmt.Fprintf(w, "new: %s\n", slug)
+		}
+	}
+	if len(summaries) > 1 {
+		_, _ = fmt.Fprintf(w, "total: exported %d docs%s across %d providers in %s\n", totalWritten, unchangedNote(totalUnchanged), len(summaries), elapsed.Round(time.Millisecond))
 	}
 }
 
+func unchangedNote(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%d unchanged)", n)
+}
+
 func mapErrorToExitCode(err error) int {
+	// Checked first: PartialError unwraps to the underlying failures, which
+	// would otherwise classify the run by its first error.
+	var partialErr *provider.PartialError
+	if errors.As(err, &partialErr) {
+		return 5
+	}
+
 	var vErr *provider.ValidationError
 	if errors.As(err, &vErr) {
 		return 1
@@ -730,6 +1981,17 @@ func mapErrorToExitCode(err error) int {
 		return 2
 	}
 
+	var verifyErr *provider.VerifyError
+	if errors.As(err, &verifyErr) {
+		return 7
+	}
+
+	// Checked before APIError, which it unwraps to.
+	var unavailableErr *registry.ServiceUnavailableError
+	if errors.As(err, &unavailableErr) {
+		return 6
+	}
+
 	var apiErr *registry.APIError
 	if errors.As(err, &apiErr) {
 		if apiErr.StatusCode == 404 {
@@ -760,32 +2022,61 @@ func printUsage(w io.Writer) {
 	_, _ = fmt.Fprintln(w, `usage: tfdc [global flags] <group> <command> [flags]
 
 commands:
-  provider  search | get | export
+  provider  search | get | list | export | verify | validate
   module    search | get
   policy    search | get
-  guide     style | module-dev
+  guide     style | module-dev | search
+  schema    manifest
 
 global flags:
   -chdir string
         switch to a different working directory before executing
   -timeout duration
-        HTTP timeout (default 10s)
+        per-request HTTP timeout, 0 disables it (default 10s)
+  -deadline duration
+        overall deadline for the command, 0 disables it
   -retry int
         retry count (default 3)
   -registry-url string
         registry base URL (default "https://registry.terraform.io")
+  -registry-mirror string
+        fallback registry base URL tried when -registry-url fails
   -insecure
         skip TLS verification
+  -tls-pin string
+        hex sha256 of the registry certificate's public key; other keys are refused
+  -min-tls-version string
+        lowest TLS version to negotiate: 1.2|1.3 (default: Go's default)
   -user-agent string
         custom User-Agent (default "tfdc/dev")
   -debug
         enable debug log
+  -explain
+        print each registry URL to stderr as the command requests it
   -cache-dir string
         cache directory (default "~/.cache/tfdc")
   -cache-ttl duration
         cache TTL (default 24h0m0s)
+  -cache-ttl-file string
+        JSON file mapping URL path prefixes to cache TTLs that override -cache-ttl
   -no-cache
-        disable cache`)
+        disable cache
+  -refresh
+        ignore cached responses but store the fresh ones
+  -max-idle-conns int
+        max idle HTTP connections per host (default 16)
+  -disable-keepalives
+        disable HTTP keep-alive connection reuse
+  -per-host-concurrency int
+        max concurrent requests per host (0 = unlimited)
+  -cache-namespace string
+        isolate cache entries under this namespace
+  -retry-jitter-seed int
+        seed for retry backoff jitter (0 = random)
+  -retry-summary
+        print retry counts per URL to stderr when the command ends
+  -no-redirects
+        report HTTP redirects as errors instead of following them`)
 }
 
 func expandHomeDir(path string) (string, error) {
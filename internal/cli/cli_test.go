@@ -1,12 +1,26 @@
 package cli
 
 import (
+	"archive/tar"
 	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/mkusaka/tfdc/internal/lockfile"
+	"github.com/mkusaka/tfdc/internal/output"
+	"github.com/mkusaka/tfdc/internal/provider"
+	"github.com/mkusaka/tfdc/internal/registry"
 )
 
 func TestParseGlobalFlags_NoCacheSkipsCachePathExpansion(t *testing.T) {
@@ -32,6 +46,28 @@ func TestParseGlobalFlags_CacheEnabledExpandsCachePath(t *testing.T) {
 	}
 }
 
+func TestParseGlobalFlags_CacheTTLFile(t *testing.T) {
+	dir := t.TempDir()
+	ttlFile := filepath.Join(dir, "ttl.json")
+	if err := os.WriteFile(ttlFile, []byte(`{"/v1/providers": "168h", "/v1/modules/search": "5m"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	g, _, err := parseGlobalFlags([]string{"-cache-dir", dir, "-cache-ttl-file", ttlFile, "provider", "export"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g.cacheTTLs["/v1/providers"] != 168*time.Hour || g.cacheTTLs["/v1/modules/search"] != 5*time.Minute {
+		t.Fatalf("unexpected ttl overrides: %v", g.cacheTTLs)
+	}
+
+	if err := os.WriteFile(ttlFile, []byte(`{"/v1/providers": "a week"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := parseGlobalFlags([]string{"-cache-dir", dir, "-cache-ttl-file", ttlFile, "provider", "export"}); err == nil {
+		t.Fatal("expected error for an unparseable duration")
+	}
+}
+
 func TestParseGlobalFlags_RejectsEmptyCacheDirWhenCacheEnabled(t *testing.T) {
 	_, _, err := parseGlobalFlags([]string{"-cache-dir", "", "provider", "export"})
 	if err == nil {
@@ -82,6 +118,25 @@ func TestExecute_ProviderExportExtraArgsReturnsExitCode1(t *testing.T) {
 	}
 }
 
+func TestExecute_ProviderExportConcurrencyMustBePositive(t *testing.T) {
+	for _, value := range []string{"0", "-2"} {
+		var errOut bytes.Buffer
+		code := Execute([]string{
+			"provider", "export",
+			"-name", "aws",
+			"-version", "6.31.0",
+			"-out-dir", t.TempDir(),
+			"-concurrency", value,
+		}, io.Discard, &errOut)
+		if code != 1 {
+			t.Fatalf("-concurrency %s: expected exit code 1, got %d; stderr=%s", value, code, errOut.String())
+		}
+		if !strings.Contains(errOut.String(), "-concurrency must be >= 1") {
+			t.Fatalf("-concurrency %s: unexpected stderr: %s", value, errOut.String())
+		}
+	}
+}
+
 func TestExecute_InvalidRegistryURLReturnsExitCode1(t *testing.T) {
 	var errOut bytes.Buffer
 	code := Execute([]string{
@@ -171,6 +226,33 @@ func TestParseGlobalFlags_ChdirIsParsed(t *testing.T) {
 	}
 }
 
+func TestParseGlobalFlags_TLSPinExcludesInsecure(t *testing.T) {
+	pin := strings.Repeat("ab", 32)
+	g, _, err := parseGlobalFlags([]string{"-tls-pin", pin, "provider", "search"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g.tlsPin != pin {
+		t.Fatalf("expected tlsPin=%s, got %q", pin, g.tlsPin)
+	}
+	if _, _, err := parseGlobalFlags([]string{"-tls-pin", pin, "-insecure", "provider", "search"}); err == nil || !strings.Contains(err.Error(), "-tls-pin cannot be combined with -insecure") {
+		t.Fatalf("expected -tls-pin/-insecure conflict, got %v", err)
+	}
+}
+
+func TestParseGlobalFlags_RefreshExcludesNoCache(t *testing.T) {
+	g, _, err := parseGlobalFlags([]string{"-refresh", "provider", "search"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !g.refresh {
+		t.Fatalf("expected refresh to be set")
+	}
+	if _, _, err := parseGlobalFlags([]string{"-refresh", "-no-cache", "provider", "search"}); err == nil || !strings.Contains(err.Error(), "-refresh cannot be combined with -no-cache") {
+		t.Fatalf("expected -refresh/-no-cache conflict, got %v", err)
+	}
+}
+
 func TestResolveLockfilePath_ChdirAutoDetect(t *testing.T) {
 	got := resolveLockfilePath("/my/project")
 	want := filepath.Join("/my/project", ".terraform.lock.hcl")
@@ -189,7 +271,7 @@ func TestResolveLockfilePath_NoChdirReturnsEmpty(t *testing.T) {
 func TestExecute_LockfileNotFoundReturnsError(t *testing.T) {
 	var errOut bytes.Buffer
 	code := Execute([]string{
-		"-chdir", "/nonexistent",
+		"-chdir", t.TempDir(),
 		"provider", "export",
 		"-out-dir", t.TempDir(),
 	}, io.Discard, &errOut)
@@ -201,6 +283,52 @@ func TestExecute_LockfileNotFoundReturnsError(t *testing.T) {
 	}
 }
 
+func TestExecute_ChdirMustBeAnExistingDirectory(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "main.tf")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	for path, want := range map[string]string{
+		filepath.Join(t.TempDir(), "typo"): "directory does not exist",
+		file:                               "not a directory",
+	} {
+		var errOut bytes.Buffer
+		code := Execute([]string{
+			"-chdir", path,
+			"provider", "export",
+			"-out-dir", t.TempDir(),
+		}, io.Discard, &errOut)
+		if code != 1 {
+			t.Fatalf("%s: expected exit code 1, got %d", path, code)
+		}
+		if !strings.Contains(errOut.String(), "-chdir "+path+": "+want) {
+			t.Fatalf("%s: expected %q in stderr, got: %s", path, want, errOut.String())
+		}
+	}
+}
+
+func TestValidateChdir_ResolvesLockfileFromRelativePath(t *testing.T) {
+	projDir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	if err := os.Chdir(filepath.Dir(projDir)); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+	got, err := validateChdir(filepath.Base(projDir))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != projDir {
+		t.Fatalf("expected %q, got %q", projDir, got)
+	}
+	if lock := resolveLockfilePath(got); lock != filepath.Join(projDir, ".terraform.lock.hcl") {
+		t.Fatalf("unexpected lockfile path %q", lock)
+	}
+}
+
 func TestExecute_ChdirAutoDetectsLockfile(t *testing.T) {
 	projDir := t.TempDir()
 	lockContent := `
@@ -227,6 +355,69 @@ provider "registry.terraform.io/hashicorp/null" {
 	}
 }
 
+func TestExecute_ChdirResolvesRelativeOutDir(t *testing.T) {
+	projDir := t.TempDir()
+	lockContent := `
+provider "registry.terraform.io/hashicorp/aws" {
+  version = "6.31.0"
+}
+`
+	if err := os.WriteFile(filepath.Join(projDir, ".terraform.lock.hcl"), []byte(lockContent), 0o644); err != nil {
+		t.Fatalf("failed to write lockfile: %v", err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/v2/providers/hashicorp/aws"):
+			_, _ = w.Write([]byte(`{"included":[{"type":"provider-versions","id":"70800","attributes":{"version":"6.31.0"}}]}`))
+		case r.URL.Path == "/v2/provider-docs" && r.URL.Query().Get("page[number]") == "1":
+			_, _ = w.Write([]byte(`{"data":[{"id":"1","type":"provider-docs","attributes":{"category":"guides","slug":"tag-policy-compliance","title":"Tag Policy Compliance"}}]}`))
+		case r.URL.Path == "/v2/provider-docs/1":
+			_, _ = w.Write([]byte(`{"data":{"id":"1","type":"provider-docs","attributes":{"category":"guides","slug":"tag-policy-compliance","title":"Tag Policy Compliance","content":"# Tag Policy"}}}`))
+		default:
+			_, _ = w.Write([]byte(`{"data":[]}`))
+		}
+	}))
+	defer srv.Close()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	var errOut bytes.Buffer
+	code := Execute([]string{
+		"-chdir", projDir,
+		"-registry-url", srv.URL,
+		"-no-cache",
+		"provider", "export",
+		"-categories", "guides",
+		"-out-dir", "docs",
+		"-summary-file", "summary.json",
+	}, io.Discard, &errOut)
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d; stderr=%s", code, errOut.String())
+	}
+	docPath := filepath.Join("docs", "terraform", "hashicorp", "aws", "6.31.0", "docs", "guides", "tag-policy-compliance.md")
+	if _, err := os.Stat(filepath.Join(projDir, docPath)); err != nil {
+		t.Fatalf("expected doc under the -chdir directory: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(projDir, "summary.json")); err != nil {
+		t.Fatalf("expected -summary-file under the -chdir directory: %v", err)
+	}
+	if _, err := os.Stat("summary.json"); !os.IsNotExist(err) {
+		t.Fatalf("expected no summary relative to the process working directory, got %v", err)
+	}
+	if _, err := os.Stat("docs"); !os.IsNotExist(err) {
+		t.Fatalf("expected nothing written relative to the process working directory, got %v", err)
+	}
+}
+
 func TestExecute_LockfileWithNameFilter_NotFound(t *testing.T) {
 	projDir := t.TempDir()
 	lockContent := `
@@ -253,6 +444,37 @@ provider "registry.terraform.io/hashicorp/aws" {
 	}
 }
 
+func TestExecute_StrictLockfileRejectsDuplicateProvider(t *testing.T) {
+	projDir := t.TempDir()
+	lockContent := `
+provider "registry.terraform.io/hashicorp/aws" {
+  version = "5.31.0"
+}
+
+provider "registry.terraform.io/hashicorp/aws" {
+  version = "6.0.0"
+}
+`
+	if err := os.WriteFile(filepath.Join(projDir, ".terraform.lock.hcl"), []byte(lockContent), 0o644); err != nil {
+		t.Fatalf("failed to write lockfile: %v", err)
+	}
+
+	var errOut bytes.Buffer
+	code := Execute([]string{
+		"-chdir", projDir,
+		"-registry-url", "http://127.0.0.1:1",
+		"provider", "export",
+		"-out-dir", t.TempDir(),
+		"-strict-lockfile",
+	}, io.Discard, &errOut)
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d; stderr=%s", code, errOut.String())
+	}
+	if !strings.Contains(errOut.String(), "duplicate provider entries: registry.terraform.io/hashicorp/aws (versions 5.31.0, 6.0.0)") {
+		t.Fatalf("expected duplicate provider error, got: %s", errOut.String())
+	}
+}
+
 func TestExecute_ChdirVersionWarning(t *testing.T) {
 	projDir := t.TempDir()
 	lockContent := `
@@ -359,6 +581,72 @@ func TestExecute_PolicySearchExtraArgsReturnsExitCode1(t *testing.T) {
 	}
 }
 
+func TestExecute_EmptySearchNotesNoResultsOnStderrForTextOnly(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"modules":[],"data":[],"docs":[]}`))
+	}))
+	defer srv.Close()
+
+	searches := map[string][]string{
+		"provider": {"provider", "search", "-name", "aws", "-service", "nothing", "-type", "resources", "-version", "6.31.0"},
+		"module":   {"module", "search", "-query", "nothing"},
+		"policy":   {"policy", "search", "-query", "nothing"},
+	}
+	for name, args := range searches {
+		for _, format := range []string{"text", "markdown", "json"} {
+			var out, errOut bytes.Buffer
+			code := Execute(append([]string{"-registry-url", srv.URL, "-no-cache"}, append(args, "-format", format)...), &out, &errOut)
+			if code != 0 {
+				t.Fatalf("%s %s: expected exit code 0, got %d; stderr=%s", name, format, code, errOut.String())
+			}
+			noted := strings.Contains(errOut.String(), "no results found")
+			if want := format != "json"; noted != want {
+				t.Fatalf("%s %s: expected note=%v, stderr=%q", name, format, want, errOut.String())
+			}
+			if strings.Contains(out.String(), "no results found") {
+				t.Fatalf("%s %s: note must not go to stdout: %q", name, format, out.String())
+			}
+			if format == "json" && !strings.Contains(out.String(), `"items": []`) {
+				t.Fatalf("%s json: expected empty items, got %s", name, out.String())
+			}
+		}
+	}
+}
+
+func TestExecute_PolicyGetRawEmitsRegistryJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"id":"policies/hashicorp/CIS-Policy-Set-for-AWS-Terraform/1.0.1","type":"policy-library","attributes":{"readme":"# CIS"}}}`))
+	}))
+	defer srv.Close()
+
+	run := func(extra ...string) string {
+		var out, errOut bytes.Buffer
+		args := append([]string{"-registry-url", srv.URL, "-no-cache", "policy", "get", "-id", "policies/hashicorp/CIS-Policy-Set-for-AWS-Terraform/1.0.1"}, extra...)
+		if code := Execute(args, &out, &errOut); code != 0 {
+			t.Fatalf("%v: expected exit code 0, got %d; stderr=%s", extra, code, errOut.String())
+		}
+		return out.String()
+	}
+
+	if got := run(); got != "# CIS" {
+		t.Fatalf("expected readme by default, got %q", got)
+	}
+	raw := run("-raw")
+	if !strings.Contains(raw, `"type":"policy-library"`) || !strings.Contains(raw, `"id":"policies/hashicorp/CIS-Policy-Set-for-AWS-Terraform/1.0.1"`) {
+		t.Fatalf("expected raw registry JSON, got %q", raw)
+	}
+
+	var wrapped output.DetailResult
+	if err := json.Unmarshal([]byte(run("-raw", "-json")), &wrapped); err != nil {
+		t.Fatal(err)
+	}
+	if wrapped.ContentType != "application/json" || !strings.Contains(wrapped.Content, `"policy-library"`) {
+		t.Fatalf("expected raw JSON as content, got %+v", wrapped)
+	}
+}
+
 func TestExecute_GuideStyleExtraArgsReturnsExitCode1(t *testing.T) {
 	var errOut bytes.Buffer
 	code := Execute([]string{
@@ -373,6 +661,19 @@ func TestExecute_GuideStyleExtraArgsReturnsExitCode1(t *testing.T) {
 	}
 }
 
+func TestExecute_GuideRejectsMalformedLocale(t *testing.T) {
+	for _, cmd := range [][]string{{"guide", "style"}, {"guide", "module-dev"}, {"guide", "search", "-query", "x"}} {
+		var errOut bytes.Buffer
+		code := Execute(append([]string{"-no-cache"}, append(cmd, "-locale", "ja!")...), io.Discard, &errOut)
+		if code != 1 {
+			t.Fatalf("%v: expected exit code 1, got %d; stderr=%s", cmd, code, errOut.String())
+		}
+		if !strings.Contains(errOut.String(), "invalid -locale") {
+			t.Fatalf("%v: unexpected stderr: %s", cmd, errOut.String())
+		}
+	}
+}
+
 func TestExecute_UnsupportedSubcommandReturnsExitCode1(t *testing.T) {
 	tests := []struct {
 		name string
@@ -394,3 +695,911 @@ func TestExecute_UnsupportedSubcommandReturnsExitCode1(t *testing.T) {
 		})
 	}
 }
+
+func TestExecute_ProviderGetRawReportsJSONContentType(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/provider-docs/42" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"id":"42","attributes":{"content":"# hello"}}}`))
+	}))
+	defer srv.Close()
+
+	var out, errOut bytes.Buffer
+	code := Execute([]string{
+		"-registry-url", srv.URL,
+		"-no-cache",
+		"provider", "get",
+		"-doc-id", "42",
+		"-raw",
+		"-format", "json",
+	}, &out, &errOut)
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, errOut.String())
+	}
+
+	var got output.DetailResult
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("decode detail envelope: %v\n%s", err, out.String())
+	}
+	if got.ID != "42" {
+		t.Fatalf("unexpected id: %q", got.ID)
+	}
+	if got.ContentType != "application/json" {
+		t.Fatalf("expected content_type application/json, got %q", got.ContentType)
+	}
+	if !strings.Contains(got.Content, `"attributes"`) {
+		t.Fatalf("expected raw API document as content, got %q", got.Content)
+	}
+}
+
+func TestExecute_ProviderGetDocIDsWritesJSONArray(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/v2/provider-docs/")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"data":{"id":%q,"attributes":{"content":"# doc %s"}}}`, id, id)
+	}))
+	defer srv.Close()
+
+	var out, errOut bytes.Buffer
+	code := Execute([]string{"-registry-url", srv.URL, "-no-cache", "provider", "get", "-doc-ids", "41, 42", "-json"}, &out, &errOut)
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, errOut.String())
+	}
+	var got []output.DetailResult
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("expected one JSON array: %v\n%s", err, out.String())
+	}
+	if len(got) != 2 || got[0].ID != "41" || got[1].Content != "# doc 42" {
+		t.Fatalf("unexpected batch: %+v", got)
+	}
+
+	code = Execute([]string{"-registry-url", srv.URL, "-no-cache", "provider", "get", "-doc-id", "1", "-doc-ids", "2"}, io.Discard, &errOut)
+	if code != 1 {
+		t.Fatalf("expected exit code 1 for -doc-id with -doc-ids, got %d", code)
+	}
+}
+
+func TestExecute_ProviderGetBase64ContentRequiresJSON(t *testing.T) {
+	var errOut bytes.Buffer
+	code := Execute([]string{"provider", "get", "-doc-id", "42", "-base64-content"}, io.Discard, &errOut)
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+	if !strings.Contains(errOut.String(), "-base64-content requires -format json") {
+		t.Fatalf("unexpected stderr: %s", errOut.String())
+	}
+}
+
+func TestFilterLocksByName_CaseInsensitive(t *testing.T) {
+	locks := []lockfile.ProviderLock{
+		{Namespace: "hashicorp", Name: "aws", Version: "5.31.0"},
+		{Namespace: "hashicorp", Name: "null", Version: "3.2.0"},
+	}
+	got := filterLocksByName(locks, " AWS ")
+	if len(got) != 1 || got[0].Name != "aws" {
+		t.Fatalf("expected AWS to match aws, got %+v", got)
+	}
+}
+
+func TestExecute_LockfileNameFilterMatchesAcrossNamespaces(t *testing.T) {
+	projDir := t.TempDir()
+	lockContent := `
+provider "registry.terraform.io/hashicorp/vault" {
+  version = "4.0.0"
+}
+provider "registry.terraform.io/Acme/Vault" {
+  version = "1.2.3"
+}
+`
+	if err := os.WriteFile(filepath.Join(projDir, ".terraform.lock.hcl"), []byte(lockContent), 0o644); err != nil {
+		t.Fatalf("failed to write lockfile: %v", err)
+	}
+
+	var mu sync.Mutex
+	var exported []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasPrefix(r.URL.Path, "/v2/providers/") {
+			parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v2/providers/"), "/")
+			mu.Lock()
+			exported = append(exported, strings.Join(parts, "/"))
+			mu.Unlock()
+			version := "4.0.0"
+			if parts[0] == "acme" {
+				version = "1.2.3"
+			}
+			_, _ = fmt.Fprintf(w, `{"included":[{"type":"provider-versions","id":"1","attributes":{"version":%q}}]}`, version)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	defer srv.Close()
+
+	var errOut bytes.Buffer
+	code := Execute([]string{
+		"-chdir", projDir,
+		"-registry-url", srv.URL,
+		"-no-cache",
+		"provider", "export",
+		"-name", "vault",
+		"-categories", "guides",
+		"-out-dir", t.TempDir(),
+	}, io.Discard, &errOut)
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d; stderr=%s", code, errOut.String())
+	}
+	if !strings.Contains(errOut.String(), `-name "vault" matched 2 providers; exporting all: hashicorp/vault, acme/vault`) {
+		t.Fatalf("expected multi-match report, got: %s", errOut.String())
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if got := strings.Join(exported, ","); got != "hashicorp/vault,acme/vault" {
+		t.Fatalf("expected both namespaces to be exported, got %s", got)
+	}
+}
+
+func TestCollectDocIDs_MergesListAndFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ids.txt")
+	if err := os.WriteFile(path, []byte("# from search\n300\n\n400,500\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := collectDocIDs("100,200", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(got, ",") != "100,200,300,400,500" {
+		t.Fatalf("unexpected doc IDs: %v", got)
+	}
+}
+
+func TestReadSlugFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "allow.txt")
+	if err := os.WriteFile(path, []byte("# approved\naws_instance\n  aws_s3_bucket  \n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readSlugFile("-allow-file", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(got, ",") != "aws_instance,aws_s3_bucket" {
+		t.Fatalf("unexpected slugs: %v", got)
+	}
+
+	commentsOnly := filepath.Join(dir, "empty.txt")
+	if err := os.WriteFile(commentsOnly, []byte("# nothing approved yet\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{filepath.Join(dir, "missing.txt"), commentsOnly} {
+		var vErr *provider.ValidationError
+		if _, err := readSlugFile("-allow-file", p); !errors.As(err, &vErr) {
+			t.Fatalf("%s: expected validation error, got %v", p, err)
+		}
+	}
+}
+
+func TestExecute_LockfileKeepGoingPartialFailureReturnsExitCode5(t *testing.T) {
+	projDir := t.TempDir()
+	lockContent := `
+provider "registry.terraform.io/hashicorp/aws" {
+  version = "5.31.0"
+}
+provider "registry.terraform.io/hashicorp/missing" {
+  version = "1.0.0"
+}
+`
+	if err := os.WriteFile(filepath.Join(projDir, ".terraform.lock.hcl"), []byte(lockContent), 0o644); err != nil {
+		t.Fatalf("failed to write lockfile: %v", err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/v2/providers/hashicorp/aws"):
+			_, _ = w.Write([]byte(`{"included":[{"type":"provider-versions","id":"1","attributes":{"version":"5.31.0"}}]}`))
+		case strings.HasPrefix(r.URL.Path, "/v2/providers/"):
+			http.NotFound(w, r)
+		default:
+			_, _ = w.Write([]byte(`{"data":[]}`))
+		}
+	}))
+	defer srv.Close()
+
+	args := func(extra ...string) []string {
+		return append([]string{
+			"-chdir", projDir,
+			"-registry-url", srv.URL,
+			"-no-cache",
+			"-retry", "0",
+			"provider", "export",
+			"-categories", "guides",
+			"-out-dir", t.TempDir(),
+		}, extra...)
+	}
+
+	var errOut bytes.Buffer
+	code := Execute(args("-keep-going"), io.Discard, &errOut)
+	if code != 5 {
+		t.Fatalf("expected exit code 5, got %d; stderr=%s", code, errOut.String())
+	}
+	if !strings.Contains(errOut.String(), "partial export: 1 of 2 providers failed") {
+		t.Fatalf("expected failure count in message, got: %s", errOut.String())
+	}
+	if !strings.Contains(errOut.String(), "hashicorp/missing@1.0.0") {
+		t.Fatalf("expected failed provider to be named, got: %s", errOut.String())
+	}
+	if !strings.Contains(errOut.String(), "exported 0 docs for aws@5.31.0") {
+		t.Fatalf("expected summary for the succeeded provider, got: %s", errOut.String())
+	}
+
+	// Without -keep-going the first failure aborts with its own exit code.
+	errOut.Reset()
+	if code := Execute(args(), io.Discard, &errOut); code != 2 {
+		t.Fatalf("expected exit code 2 without -keep-going, got %d; stderr=%s", code, errOut.String())
+	}
+}
+
+func TestExecute_ProviderValidateReportsUnresolvedProviders(t *testing.T) {
+	projDir := t.TempDir()
+	lockContent := `
+provider "registry.terraform.io/hashicorp/aws" {
+  version = "5.31.0"
+}
+provider "registry.terraform.io/hashicorp/google" {
+  version = "9.9.9"
+}
+`
+	if err := os.WriteFile(filepath.Join(projDir, ".terraform.lock.hcl"), []byte(lockContent), 0o644); err != nil {
+		t.Fatalf("failed to write lockfile: %v", err)
+	}
+
+	var docRequests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/v2/providers/hashicorp/aws"):
+			_, _ = w.Write([]byte(`{"included":[{"type":"provider-versions","id":"1","attributes":{"version":"5.31.0"}}]}`))
+		case strings.HasPrefix(r.URL.Path, "/v2/providers/hashicorp/google"):
+			_, _ = w.Write([]byte(`{"included":[{"type":"provider-versions","id":"2","attributes":{"version":"6.0.0"}}]}`))
+		default:
+			docRequests.Add(1)
+			_, _ = w.Write([]byte(`{"data":[]}`))
+		}
+	}))
+	defer srv.Close()
+
+	var out, errOut bytes.Buffer
+	code := Execute([]string{
+		"-registry-url", srv.URL,
+		"-no-cache",
+		"provider", "validate",
+		"-chdir", projDir,
+	}, &out, &errOut)
+	if code != 2 {
+		t.Fatalf("expected exit code 2, got %d; stderr=%s", code, errOut.String())
+	}
+	if !strings.Contains(out.String(), "ok     hashicorp/aws@5.31.0 provider_version_id=1") {
+		t.Fatalf("expected aws to be reported as resolved, got: %s", out.String())
+	}
+	if !strings.Contains(out.String(), "failed hashicorp/google@9.9.9: provider version not found") {
+		t.Fatalf("expected google to be reported as failed, got: %s", out.String())
+	}
+	if !strings.Contains(errOut.String(), "1 of 2 providers did not resolve") {
+		t.Fatalf("expected failure count in stderr, got: %s", errOut.String())
+	}
+	if n := docRequests.Load(); n != 0 {
+		t.Fatalf("expected no doc requests, got %d", n)
+	}
+}
+
+func TestExecute_RetrySummaryReportsRetriesPerURL(t *testing.T) {
+	var failed atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failed.CompareAndSwap(false, true) {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	defer srv.Close()
+
+	var errOut bytes.Buffer
+	code := Execute([]string{"-registry-url", srv.URL, "-no-cache", "-retry-summary", "policy", "search", "-query", "cis"}, io.Discard, &errOut)
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d; stderr=%s", code, errOut.String())
+	}
+	if !strings.Contains(errOut.String(), "retry summary: 1 retry across 1 URL\n") {
+		t.Fatalf("expected retry summary on stderr, got: %s", errOut.String())
+	}
+	if !strings.Contains(errOut.String(), "  1 "+srv.URL+"/v2/policies?") {
+		t.Fatalf("expected the retried URL to be listed, got: %s", errOut.String())
+	}
+}
+
+func TestExecute_ExplainListsSearchRequests(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/providers/hashicorp/aws":
+			_, _ = w.Write([]byte(`{"version":"6.31.0"}`))
+		case "/v1/providers/hashicorp/aws/6.31.0":
+			_, _ = w.Write([]byte(`{"docs":[{"id":"1","title":"aws_instance","category":"resources","slug":"instance","language":"hcl"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	var out, errOut bytes.Buffer
+	code := Execute([]string{"-registry-url", srv.URL, "-no-cache", "-explain",
+		"provider", "search", "-name", "aws", "-service", "instance", "-type", "resources"}, &out, &errOut)
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, errOut.String())
+	}
+	want := "GET " + srv.URL + "/v1/providers/hashicorp/aws\n" +
+		"GET " + srv.URL + "/v1/providers/hashicorp/aws/6.31.0\n"
+	if errOut.String() != want {
+		t.Fatalf("unexpected explained requests:\n%s\nwant:\n%s", errOut.String(), want)
+	}
+	if !strings.Contains(out.String(), "instance") {
+		t.Fatalf("expected search results on stdout, got: %s", out.String())
+	}
+}
+
+func TestExecute_ProviderSearchAcrossProvidersReportsBreakdown(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/providers/hashicorp/aws", "/v1/providers/hashicorp/google":
+			_, _ = w.Write([]byte(`{"version":"1.0.0"}`))
+		case "/v1/providers/hashicorp/aws/1.0.0":
+			_, _ = w.Write([]byte(`{"docs":[` +
+				`{"id":"1","title":"aws_instance","category":"resources","slug":"instance","language":"hcl"},` +
+				`{"id":"2","title":"aws_instance_profile","category":"resources","slug":"instance_profile","language":"hcl"}]}`))
+		case "/v1/providers/hashicorp/google/1.0.0":
+			_, _ = w.Write([]byte(`{"docs":[{"id":"3","title":"google_compute_instance","category":"resources","slug":"compute_instance","language":"hcl"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	args := []string{"-registry-url", srv.URL, "-no-cache",
+		"provider", "search", "-name", "aws, google", "-service", "instance", "-type", "resources"}
+	var out, errOut bytes.Buffer
+	if code := Execute(append(args, "-format", "json"), &out, &errOut); code != 0 {
+		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, errOut.String())
+	}
+	var result output.SearchResult
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if result.Total != 3 || result.ByProvider["aws"] != 2 || result.ByProvider["google"] != 1 {
+		t.Fatalf("expected 3 merged results split aws=2 google=1, got total=%d by_provider=%v", result.Total, result.ByProvider)
+	}
+
+	out.Reset()
+	errOut.Reset()
+	if code := Execute(args, &out, &errOut); code != 0 {
+		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, errOut.String())
+	}
+	if !strings.Contains(errOut.String(), "matches by provider: aws 2, google 1\n") {
+		t.Fatalf("expected the breakdown on stderr, got: %s", errOut.String())
+	}
+}
+
+func TestExecute_ProviderSearchIDFormat(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/providers/hashicorp/aws":
+			_, _ = w.Write([]byte(`{"version":"6.31.0"}`))
+		case "/v1/providers/hashicorp/aws/6.31.0":
+			_, _ = w.Write([]byte(`{"docs":[{"id":"123","title":"aws_instance","category":"resources","slug":"instance","language":"hcl"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	for format, want := range map[string]string{"": "123", "qualified": "hashicorp/aws/6.31.0#123"} {
+		args := []string{"-registry-url", srv.URL, "-no-cache",
+			"provider", "search", "-name", "aws", "-service", "instance", "-type", "resources", "-format", "json"}
+		if format != "" {
+			args = append(args, "-id-format", format)
+		}
+		var out, errOut bytes.Buffer
+		if code := Execute(args, &out, &errOut); code != 0 {
+			t.Fatalf("%q: expected exit code 0, got %d (stderr: %s)", format, code, errOut.String())
+		}
+		var result output.SearchResult
+		if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+			t.Fatalf("%q: invalid json: %v", format, err)
+		}
+		if len(result.Items) != 1 || result.Items[0]["provider_doc_id"] != want {
+			t.Fatalf("%q: expected provider_doc_id %q, got %v", format, want, result.Items)
+		}
+	}
+
+	var errOut bytes.Buffer
+	args := []string{"provider", "search", "-name", "aws", "-service", "instance", "-type", "resources", "-id-format", "full"}
+	if code := Execute(args, io.Discard, &errOut); code != 1 {
+		t.Fatalf("expected exit code 1 for an unknown -id-format, got %d", code)
+	}
+}
+
+func TestExecute_SubcommandTimeoutOverridesGlobal(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	defer srv.Close()
+
+	run := func(globalTimeout, cmdTimeout string) int {
+		var errOut bytes.Buffer
+		args := []string{"-registry-url", srv.URL, "-no-cache", "-retry", "0", "-timeout", globalTimeout,
+			"policy", "search", "-query", "cis", "-timeout", cmdTimeout}
+		return Execute(args, io.Discard, &errOut)
+	}
+
+	if code := run("5s", "50ms"); code != 3 {
+		t.Fatalf("expected the shorter command -timeout to fail the request, got exit code %d", code)
+	}
+	if code := run("50ms", "5s"); code != 0 {
+		t.Fatalf("expected the longer command -timeout to win over the global one, got exit code %d", code)
+	}
+}
+
+func TestParseGlobalFlags_TimeoutAndDeadline(t *testing.T) {
+	g, _, err := parseGlobalFlags([]string{"-timeout", "0", "-deadline", "5m", "provider", "export"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g.timeout != 0 || g.deadline != 5*time.Minute {
+		t.Fatalf("unexpected timeout/deadline: %s/%s", g.timeout, g.deadline)
+	}
+
+	for _, flagName := range []string{"-timeout", "-deadline"} {
+		_, _, err := parseGlobalFlags([]string{flagName, "-1s", "provider", "export"})
+		if err == nil || !strings.Contains(err.Error(), flagName+" must be >= 0") {
+			t.Fatalf("expected %s validation error, got %v", flagName, err)
+		}
+	}
+}
+
+func TestExecute_JSONFlagMatchesFormatJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"id":"42","attributes":{"content":"# hello"}}}`))
+	}))
+	defer srv.Close()
+
+	run := func(extra ...string) (int, string, string) {
+		var out, errOut bytes.Buffer
+		args := append([]string{"-registry-url", srv.URL, "-no-cache", "provider", "get", "-doc-id", "42"}, extra...)
+		code := Execute(args, &out, &errOut)
+		return code, out.String(), errOut.String()
+	}
+
+	code, want, stderr := run("-format", "json")
+	if code != 0 {
+		t.Fatalf("-format json: exit %d (stderr: %s)", code, stderr)
+	}
+	for _, extra := range [][]string{{"-json"}, {"-json", "-format", "json"}} {
+		code, got, stderr := run(extra...)
+		if code != 0 {
+			t.Fatalf("%v: exit %d (stderr: %s)", extra, code, stderr)
+		}
+		if got != want {
+			t.Fatalf("%v: expected output identical to -format json\n got: %s\nwant: %s", extra, got, want)
+		}
+	}
+
+	code, _, stderr = run("-json", "-format", "markdown")
+	if code != 1 {
+		t.Fatalf("expected exit code 1 for conflicting flags, got %d", code)
+	}
+	if !strings.Contains(stderr, "-json conflicts with -format markdown") {
+		t.Fatalf("unexpected stderr: %s", stderr)
+	}
+}
+
+func TestMapErrorToExitCode_ServiceUnavailable(t *testing.T) {
+	apiErr := &registry.APIError{StatusCode: 503, URL: "https://registry.terraform.io/v1/x"}
+	if got := mapErrorToExitCode(&registry.ServiceUnavailableError{APIError: apiErr}); got != 6 {
+		t.Fatalf("expected exit code 6, got %d", got)
+	}
+	if got := mapErrorToExitCode(apiErr); got != 3 {
+		t.Fatalf("expected plain 503 APIError to keep exit code 3, got %d", got)
+	}
+}
+
+func TestMapErrorToExitCode_VerifyError(t *testing.T) {
+	err := &provider.VerifyError{Manifest: "_manifest.json", Checked: 1, Problems: []provider.VerifyProblem{{Path: "a.md", Reason: "missing"}}}
+	if got := mapErrorToExitCode(err); got != 7 {
+		t.Fatalf("expected exit code 7, got %d", got)
+	}
+}
+
+func TestExecute_ExportOutDirDashStreamsTarToStdout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/v2/providers/hashicorp/aws"):
+			_, _ = w.Write([]byte(`{"included":[{"type":"provider-versions","id":"70800","attributes":{"version":"6.31.0"}}]}`))
+		case r.URL.Path == "/v2/provider-docs" && r.URL.Query().Get("page[number]") == "1":
+			_, _ = w.Write([]byte(`{"data":[{"id":"1","type":"provider-docs","attributes":{"category":"guides","slug":"tag-policy-compliance","title":"Tag Policy Compliance"}}]}`))
+		case r.URL.Path == "/v2/provider-docs/1":
+			_, _ = w.Write([]byte(`{"data":{"id":"1","type":"provider-docs","attributes":{"category":"guides","slug":"tag-policy-compliance","title":"Tag Policy Compliance","content":"# Tag Policy"}}}`))
+		default:
+			_, _ = w.Write([]byte(`{"data":[]}`))
+		}
+	}))
+	defer srv.Close()
+
+	var out, errOut bytes.Buffer
+	code := Execute([]string{
+		"-registry-url", srv.URL,
+		"-no-cache",
+		"provider", "export",
+		"-name", "aws",
+		"-version", "6.31.0",
+		"-categories", "guides",
+		"-out-dir", "-",
+	}, &out, &errOut)
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d; stderr=%s", code, errOut.String())
+	}
+
+	files := map[string]string{}
+	tr := tar.NewReader(&out)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("stdout is not a valid tar stream: %v", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		b, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[hdr.Name] = string(b)
+	}
+
+	docPath := "terraform/hashicorp/aws/6.31.0/docs/guides/tag-policy-compliance.md"
+	manifestPath := "terraform/hashicorp/aws/6.31.0/docs/_manifest.json"
+	if !strings.Contains(files[docPath], "# Tag Policy") {
+		t.Fatalf("expected %s in archive, got entries %v", docPath, files)
+	}
+	if _, ok := files[manifestPath]; !ok {
+		t.Fatalf("expected %s in archive, got entries %v", manifestPath, files)
+	}
+	if len(files) != 2 {
+		t.Fatalf("expected exactly 2 files in archive, got %v", files)
+	}
+	if !strings.Contains(errOut.String(), "manifest: "+manifestPath) {
+		t.Fatalf("expected summary on stderr naming the archive entry, got: %s", errOut.String())
+	}
+}
+
+func TestExecute_ModuleGetFullIncludesRawJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/modules/acme/vpc/aws/1.0.0" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"acme/vpc/aws/1.0.0","name":"vpc","root":{"readme":"# VPC","inputs":[{"name":"cidr"}]},"submodules":[]}`))
+	}))
+	defer srv.Close()
+
+	run := func(extra ...string) string {
+		t.Helper()
+		var out, errOut bytes.Buffer
+		args := append([]string{"-registry-url", srv.URL, "-no-cache", "module", "get", "-id", "acme/vpc/aws/1.0.0"}, extra...)
+		if code := Execute(args, &out, &errOut); code != 0 {
+			t.Fatalf("%v: expected exit code 0, got %d (stderr: %s)", extra, code, errOut.String())
+		}
+		return out.String()
+	}
+
+	if got := run(); strings.TrimSpace(got) != "# VPC" {
+		t.Fatalf("default: expected only the readme, got %q", got)
+	}
+	if got := run("-full"); !strings.Contains(got, `"submodules":[]`) {
+		t.Fatalf("-full: expected the raw registry JSON, got %q", got)
+	}
+
+	var withRaw struct {
+		Name string          `json:"name"`
+		Raw  json.RawMessage `json:"raw"`
+	}
+	if err := json.Unmarshal([]byte(run("-full", "-json")), &withRaw); err != nil {
+		t.Fatal(err)
+	}
+	if withRaw.Name != "vpc" || !strings.Contains(string(withRaw.Raw), `"submodules"`) {
+		t.Fatalf("-full -json: expected metadata plus raw, got %+v", withRaw)
+	}
+
+	var plain map[string]any
+	if err := json.Unmarshal([]byte(run("-json")), &plain); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := plain["raw"]; ok {
+		t.Fatalf("-json without -full must not include raw: %v", plain)
+	}
+}
+
+func TestExecute_ModuleGetInputsAndOutputs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"acme/vpc/aws/1.0.0","name":"vpc","root":{"readme":"# VPC",
+			"inputs":[
+				{"name":"cidr","type":"string","description":"VPC CIDR","default":"","required":true},
+				{"name":"azs","type":"list(string)","default":["a","b"],"required":false}
+			],
+			"outputs":[{"name":"vpc_id","description":"The VPC ID"}]}}`))
+	}))
+	defer srv.Close()
+
+	run := func(extra ...string) string {
+		t.Helper()
+		var out, errOut bytes.Buffer
+		args := append([]string{"-registry-url", srv.URL, "-no-cache", "module", "get", "-id", "acme/vpc/aws/1.0.0"}, extra...)
+		if code := Execute(args, &out, &errOut); code != 0 {
+			t.Fatalf("%v: expected exit code 0, got %d (stderr: %s)", extra, code, errOut.String())
+		}
+		return out.String()
+	}
+
+	rows := func(table string) []string {
+		var out []string
+		for _, line := range strings.Split(strings.TrimSpace(table), "\n") {
+			out = append(out, strings.Join(strings.Fields(line), " "))
+		}
+		return out
+	}
+	want := []string{"name type default required", "cidr string true", `azs list(string) ["a","b"] false`}
+	if got := rows(run("-inputs")); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("-inputs: expected rows %q, got %q", want, got)
+	}
+	want = []string{"name description", "vpc_id The VPC ID"}
+	if got := rows(run("-outputs")); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("-outputs: expected rows %q, got %q", want, got)
+	}
+
+	var env struct {
+		Items []map[string]any `json:"items"`
+		Total int              `json:"total"`
+	}
+	if err := json.Unmarshal([]byte(run("-inputs", "-json")), &env); err != nil {
+		t.Fatal(err)
+	}
+	if env.Total != 2 || env.Items[0]["required"] != true {
+		t.Fatalf("-inputs -json: unexpected envelope %+v", env)
+	}
+	if def, ok := env.Items[1]["default"].([]any); !ok || len(def) != 2 {
+		t.Fatalf("-inputs -json: expected the list default as JSON, got %#v", env.Items[1]["default"])
+	}
+
+	var errOut bytes.Buffer
+	args := []string{"-registry-url", srv.URL, "-no-cache", "module", "get", "-id", "acme/vpc/aws/1.0.0", "-inputs", "-outputs"}
+	if code := Execute(args, io.Discard, &errOut); code != 1 {
+		t.Fatalf("expected exit code 1 for -inputs with -outputs, got %d", code)
+	}
+}
+
+func TestExecute_ExportRunLogAppendsOneLinePerRun(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/v2/providers/hashicorp/aws"):
+			_, _ = w.Write([]byte(`{"included":[{"type":"provider-versions","id":"70800","attributes":{"version":"6.31.0"}}]}`))
+		case r.URL.Path == "/v2/provider-docs" && r.URL.Query().Get("page[number]") == "1":
+			_, _ = w.Write([]byte(`{"data":[{"id":"1","type":"provider-docs","attributes":{"category":"guides","slug":"tag-policy-compliance","title":"Tag Policy Compliance"}}]}`))
+		case r.URL.Path == "/v2/provider-docs/1":
+			_, _ = w.Write([]byte(`{"data":{"id":"1","type":"provider-docs","attributes":{"category":"guides","slug":"tag-policy-compliance","title":"Tag Policy Compliance","content":"# Tag Policy"}}}`))
+		case strings.HasPrefix(r.URL.Path, "/v2/providers/"):
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			_, _ = w.Write([]byte(`{"data":[]}`))
+		}
+	}))
+	defer srv.Close()
+
+	runLog := filepath.Join(t.TempDir(), "runs.ndjson")
+	outDir := t.TempDir()
+	for _, name := range []string{"aws", "nope"} {
+		var out, errOut bytes.Buffer
+		Execute([]string{
+			"-registry-url", srv.URL,
+			"-no-cache",
+			"provider", "export",
+			"-name", name,
+			"-version", "6.31.0",
+			"-categories", "guides",
+			"-out-dir", outDir,
+			"-run-log", runLog,
+		}, &out, &errOut)
+	}
+
+	b, err := os.ReadFile(runLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 NDJSON lines, got %d:\n%s", len(lines), b)
+	}
+	var entries [2]provider.RunLogEntry
+	for i, line := range lines {
+		if err := json.Unmarshal([]byte(line), &entries[i]); err != nil {
+			t.Fatalf("line %d is not JSON: %v", i+1, err)
+		}
+		if _, err := time.Parse(time.RFC3339, entries[i].Timestamp); err != nil {
+			t.Fatalf("line %d: bad timestamp %q", i+1, entries[i].Timestamp)
+		}
+	}
+	ok := entries[0]
+	if ok.Provider != "aws" || ok.Version != "6.31.0" || ok.Written != 1 || ok.ExitStatus != 0 || ok.Error != "" ||
+		!strings.HasSuffix(ok.Manifest, "terraform/hashicorp/aws/6.31.0/docs/_manifest.json") {
+		t.Fatalf("unexpected first entry: %+v", ok)
+	}
+	failed := entries[1]
+	if failed.Provider != "nope" || failed.Written != 0 || failed.ExitStatus != 2 || failed.Error == "" {
+		t.Fatalf("unexpected second entry: %+v", failed)
+	}
+}
+
+func TestExecute_ExportResolveOnlyPrintsVersionIDAndWritesNothing(t *testing.T) {
+	var listed bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1/providers/hashicorp/aws":
+			_, _ = w.Write([]byte(`{"version":"6.31.0"}`))
+		case strings.HasPrefix(r.URL.Path, "/v2/providers/hashicorp/aws"):
+			_, _ = w.Write([]byte(`{"included":[{"type":"provider-versions","id":"70800","attributes":{"version":"6.31.0"}}]}`))
+		default:
+			listed = true
+			_, _ = w.Write([]byte(`{"data":[]}`))
+		}
+	}))
+	defer srv.Close()
+
+	outDir := t.TempDir()
+	var out, errOut bytes.Buffer
+	code := Execute([]string{
+		"-registry-url", srv.URL,
+		"-no-cache",
+		"provider", "export",
+		"-name", "aws",
+		"-version", "latest",
+		"-out-dir", outDir,
+		"-resolve-only",
+	}, &out, &errOut)
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d; stderr=%s", code, errOut.String())
+	}
+	if got := out.String(); got != "hashicorp/aws@6.31.0 provider_version_id=70800\n" {
+		t.Fatalf("unexpected stdout: %q", got)
+	}
+	if listed {
+		t.Fatal("expected no doc listing requests")
+	}
+	entries, err := os.ReadDir(outDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected no files written, got %v", entries)
+	}
+}
+
+func TestExecute_ExportValidateTemplateMakesNoRequests(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s", r.URL)
+	}))
+	defer srv.Close()
+
+	run := func(template string) (int, string, string) {
+		outDir := t.TempDir()
+		var out, errOut bytes.Buffer
+		code := Execute([]string{
+			"-registry-url", srv.URL,
+			"-no-cache",
+			"provider", "export",
+			"-name", "aws",
+			"-version", "6.31.0",
+			"-out-dir", outDir,
+			"-path-template", template,
+			"-validate-template",
+		}, &out, &errOut)
+		if entries, _ := os.ReadDir(outDir); len(entries) != 0 {
+			t.Fatalf("expected nothing written, got %v", entries)
+		}
+		return code, out.String(), errOut.String()
+	}
+
+	code, out, errOut := run("{out}/{provider}/{category}/{slug}.{ext}")
+	if code != 0 {
+		t.Fatalf("good template: expected exit code 0, got %d; stderr=%s", code, errOut)
+	}
+	if out != "path template OK: sample doc path aws/resources/sample_resource.md\n" {
+		t.Fatalf("good template: unexpected stdout %q", out)
+	}
+
+	code, _, errOut = run("{out}/../outside/{slug}.{ext}")
+	if code != 1 {
+		t.Fatalf("bad template: expected exit code 1, got %d", code)
+	}
+	if !strings.Contains(errOut, "outside -out-dir") {
+		t.Fatalf("bad template: expected out-dir error, got %q", errOut)
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	valid := map[string]int64{"": 0, "512": 512, "64KB": 64 << 10, "5mb": 5 << 20, "5 MiB": 5 << 20, "1G": 1 << 30}
+	for in, want := range valid {
+		got, err := parseByteSize("-max-file-size", in)
+		if err != nil || got != want {
+			t.Fatalf("%q: expected %d, got %d (%v)", in, want, got, err)
+		}
+	}
+	for _, in := range []string{"MB", "5.5MB", "5TB", "99999999999GB"} {
+		if _, err := parseByteSize("-max-file-size", in); err == nil {
+			t.Fatalf("%q: expected error", in)
+		}
+	}
+}
+
+func TestWriteExportPlans(t *testing.T) {
+	var out bytes.Buffer
+	err := writeExportPlans(&out, []provider.ExportSummary{{
+		Provider: "aws",
+		Version:  "6.31.0",
+		Plan: &provider.ExportPlan{
+			Added:     []string{"docs/a.md"},
+			Modified:  []string{"docs/b.md"},
+			Unchanged: []string{"docs/c.md"},
+			Removed:   []string{"docs/d.md"},
+		},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := out.String(), "+ docs/a.md\n~ docs/b.md\n- docs/d.md\n"; got != want {
+		t.Fatalf("unexpected plan output:\n%s", got)
+	}
+}
+
+func TestPrintSummaries_AggregateLine(t *testing.T) {
+	var out bytes.Buffer
+	printSummaries([]provider.ExportSummary{
+		{Provider: "aws", Version: "6.31.0", Written: 10, Manifest: "a/_manifest.json"},
+		{Provider: "google", Version: "5.0.0", Written: 7, Unchanged: 2, Manifest: "b/_manifest.json"},
+		{Provider: "random", Version: "3.6.0", Written: 1, Manifest: "c/_manifest.json"},
+	}, 1500*time.Millisecond, &out)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if got, want := lines[len(lines)-1], "total: exported 18 docs (2 unchanged) across 3 providers in 1.5s"; got != want {
+		t.Fatalf("unexpected aggregate line:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestPrintSummaries_SingleProviderHasNoAggregate(t *testing.T) {
+	var out bytes.Buffer
+	printSummaries([]provider.ExportSummary{{Provider: "aws", Version: "6.31.0", Written: 3, Manifest: "m"}}, time.Second, &out)
+	if strings.Contains(out.String(), "total:") {
+		t.Fatalf("expected no aggregate line for one provider, got %q", out.String())
+	}
+}
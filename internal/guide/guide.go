@@ -21,7 +21,17 @@ var ModuleDevSections = []string{"index", "composition", "structure", "providers
 
 // FetchStyleGuide fetches the Terraform style guide.
 func FetchStyleGuide(ctx context.Context, client APIClient) (string, error) {
-	b, err := client.Get(ctx, styleURL)
+	return FetchStyleGuideForLocale(ctx, client, "")
+}
+
+// FetchStyleGuideForLocale is like FetchStyleGuide but fetches the guide
+// localized for locale, a BCP-47 tag validated by NormalizeLocale.
+func FetchStyleGuideForLocale(ctx context.Context, client APIClient, locale string) (string, error) {
+	locale, err := NormalizeLocale(locale)
+	if err != nil {
+		return "", err
+	}
+	b, err := client.Get(ctx, localizedURL(styleURL, locale))
 	if err != nil {
 		return "", err
 	}
@@ -31,16 +41,28 @@ func FetchStyleGuide(ctx context.Context, client APIClient) (string, error) {
 // FetchModuleDevGuide fetches the module development guide.
 // section can be "all" or one of ModuleDevSections.
 func FetchModuleDevGuide(ctx context.Context, client APIClient, section string) (string, error) {
+	return FetchModuleDevGuideForLocale(ctx, client, section, "")
+}
+
+// FetchModuleDevGuideForLocale is like FetchModuleDevGuide but fetches the
+// guide localized for locale, a BCP-47 tag validated by NormalizeLocale.
+func FetchModuleDevGuideForLocale(ctx context.Context, client APIClient, section, locale string) (string, error) {
+	locale, err := NormalizeLocale(locale)
+	if err != nil {
+		return "", err
+	}
+	base := localizedURL(moduleDevBase, locale)
+
 	section = strings.ToLower(strings.TrimSpace(section))
 	if section == "" || section == "all" {
-		return fetchAllSections(ctx, client)
+		return fetchAllSections(ctx, client, base)
 	}
 
 	if !isValidSection(section) {
 		return "", &ValidationError{Message: fmt.Sprintf("invalid -section: %s (valid: all, %s)", section, strings.Join(ModuleDevSections, ", "))}
 	}
 
-	url := fmt.Sprintf("%s/%s.mdx", moduleDevBase, section)
+	url := fmt.Sprintf("%s/%s.mdx", base, section)
 	b, err := client.Get(ctx, url)
 	if err != nil {
 		return "", err
@@ -48,10 +70,10 @@ func FetchModuleDevGuide(ctx context.Context, client APIClient, section string)
 	return string(b), nil
 }
 
-func fetchAllSections(ctx context.Context, client APIClient) (string, error) {
+func fetchAllSections(ctx context.Context, client APIClient, base string) (string, error) {
 	var parts []string
 	for _, section := range ModuleDevSections {
-		url := fmt.Sprintf("%s/%s.mdx", moduleDevBase, section)
+		url := fmt.Sprintf("%s/%s.mdx", base, section)
 		b, err := client.Get(ctx, url)
 		if err != nil {
 			return "", err
@@ -0,0 +1,62 @@
+package guide
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultLocale is the language the guides are published in today; it
+// keeps the unlocalized URLs.
+const DefaultLocale = "en"
+
+var (
+	reLocaleLanguage = regexp.MustCompile(`^([a-z]{2,3}|[a-z]{5,8})$`)
+	reLocaleScript   = regexp.MustCompile(`^[a-z]{4}$`)
+	reLocaleRegion   = regexp.MustCompile(`^([a-z]{2}|[0-9]{3})$`)
+	reLocaleVariant  = regexp.MustCompile(`^([a-z0-9]{5,8}|[0-9][a-z0-9]{3})$`)
+)
+
+// NormalizeLocale validates a BCP-47 language tag of the form
+// language[-script][-region][-variant...] and returns it in canonical case,
+// e.g. "pt_br" becomes "pt-BR" and "zh-hant-tw" becomes "zh-Hant-TW".
+// Extensions and private-use subtags are not accepted. The empty tag stays
+// empty.
+func NormalizeLocale(tag string) (string, error) {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return "", nil
+	}
+	invalid := &ValidationError{Message: fmt.Sprintf("invalid -locale: %q is not a BCP-47 language tag such as en, ja or pt-BR", tag)}
+
+	subtags := strings.Split(strings.ToLower(strings.ReplaceAll(tag, "_", "-")), "-")
+	if !reLocaleLanguage.MatchString(subtags[0]) {
+		return "", invalid
+	}
+	out := []string{subtags[0]}
+	rest := subtags[1:]
+	if len(rest) > 0 && reLocaleScript.MatchString(rest[0]) {
+		out = append(out, strings.ToUpper(rest[0][:1])+rest[0][1:])
+		rest = rest[1:]
+	}
+	if len(rest) > 0 && reLocaleRegion.MatchString(rest[0]) {
+		out = append(out, strings.ToUpper(rest[0]))
+		rest = rest[1:]
+	}
+	for _, v := range rest {
+		if !reLocaleVariant.MatchString(v) {
+			return "", invalid
+		}
+		out = append(out, v)
+	}
+	return strings.Join(out, "-"), nil
+}
+
+// localizedURL inserts locale as a path segment after "/content/" in a
+// guide URL. The empty and default locales return u unchanged.
+func localizedURL(u, locale string) string {
+	if locale == "" || locale == DefaultLocale {
+		return u
+	}
+	return strings.Replace(u, "/content/", "/content/"+locale+"/", 1)
+}
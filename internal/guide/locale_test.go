@@ -0,0 +1,72 @@
+package guide
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestNormalizeLocale(t *testing.T) {
+	valid := map[string]string{
+		"":           "",
+		"en":         "en",
+		"JA":         "ja",
+		"pt_br":      "pt-BR",
+		"zh-hant-tw": "zh-Hant-TW",
+		"es-419":     "es-419",
+		"de-CH-1996": "de-CH-1996",
+	}
+	for in, want := range valid {
+		got, err := NormalizeLocale(in)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", in, err)
+		}
+		if got != want {
+			t.Fatalf("%q: expected %q, got %q", in, want, got)
+		}
+	}
+
+	for _, in := range []string{"e", "english!", "en-", "en--US", "en-US-x", "12", "en-u-ca-gregory"} {
+		_, err := NormalizeLocale(in)
+		var vErr *ValidationError
+		if !errors.As(err, &vErr) {
+			t.Fatalf("%q: expected ValidationError, got %v", in, err)
+		}
+	}
+}
+
+// recordingGuideClient records requested URLs and serves placeholder content.
+type recordingGuideClient struct {
+	paths []string
+}
+
+func (f *recordingGuideClient) Get(_ context.Context, path string) ([]byte, error) {
+	f.paths = append(f.paths, path)
+	return []byte(fmt.Sprintf("# %s", path)), nil
+}
+
+func TestFetchGuidesForLocale(t *testing.T) {
+	client := &recordingGuideClient{}
+	if _, err := FetchStyleGuideForLocale(context.Background(), client, "ja_jp"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := FetchModuleDevGuideForLocale(context.Background(), client, "composition", "en"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		"https://raw.githubusercontent.com/hashicorp/web-unified-docs/main/content/ja-JP/terraform/v1.12.x/docs/language/style.mdx",
+		moduleDevBase + "/composition.mdx",
+	}
+	if fmt.Sprint(client.paths) != fmt.Sprint(want) {
+		t.Fatalf("expected requests %v, got %v", want, client.paths)
+	}
+
+	client.paths = nil
+	if _, err := FetchModuleDevGuideForLocale(context.Background(), client, "all", "ja!"); err == nil {
+		t.Fatal("expected a malformed locale to be rejected")
+	}
+	if len(client.paths) != 0 {
+		t.Fatalf("expected no requests for a malformed locale, got %v", client.paths)
+	}
+}
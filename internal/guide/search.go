@@ -0,0 +1,102 @@
+package guide
+
+import (
+	"context"
+	"strings"
+)
+
+// snippetRadius is how many bytes of context are kept on each side of a
+// match in a search snippet.
+const snippetRadius = 60
+
+// SearchOptions configures Search.
+type SearchOptions struct {
+	Query        string
+	IncludeStyle bool
+	// Locale selects localized guides; see NormalizeLocale.
+	Locale string
+}
+
+// Match is a guide section whose content contains the query.
+type Match struct {
+	Guide   string `json:"guide"`
+	Section string `json:"section"`
+	Matches int    `json:"matches"`
+	Snippet string `json:"snippet"`
+}
+
+// Search fetches every module-dev section, and the style guide when
+// IncludeStyle is set, and reports the sections whose content contains
+// Query, compared case-insensitively. Sections are reported in the order
+// they are fetched, each with a snippet around its first match.
+func Search(ctx context.Context, client APIClient, opts SearchOptions) ([]Match, error) {
+	query := strings.TrimSpace(opts.Query)
+	if query == "" {
+		return nil, &ValidationError{Message: "-query is required"}
+	}
+	needle := strings.ToLower(query)
+	locale, err := NormalizeLocale(opts.Locale)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Match
+	for _, section := range ModuleDevSections {
+		content, err := FetchModuleDevGuideForLocale(ctx, client, section, locale)
+		if err != nil {
+			return nil, err
+		}
+		if m, ok := matchContent("module-dev", section, content, needle); ok {
+			matches = append(matches, m)
+		}
+	}
+	if opts.IncludeStyle {
+		content, err := FetchStyleGuideForLocale(ctx, client, locale)
+		if err != nil {
+			return nil, err
+		}
+		if m, ok := matchContent("style", "style", content, needle); ok {
+			matches = append(matches, m)
+		}
+	}
+	return matches, nil
+}
+
+func matchContent(guideName, section, content, needle string) (Match, bool) {
+	lower := strings.ToLower(content)
+	idx := strings.Index(lower, needle)
+	if idx < 0 {
+		return Match{}, false
+	}
+	return Match{
+		Guide:   guideName,
+		Section: section,
+		Matches: strings.Count(lower, needle),
+		Snippet: snippet(content, idx, len(needle)),
+	}, true
+}
+
+// snippet returns the text around content[idx:idx+n] on a single line,
+// with ellipses marking truncation. Offsets are taken from the lowercased
+// content, which has the same byte length for the ASCII text guides use.
+func snippet(content string, idx, n int) string {
+	idx = min(idx, len(content))
+	start := max(idx-snippetRadius, 0)
+	end := min(idx+n+snippetRadius, len(content))
+	for start > 0 && !isRuneStart(content[start]) {
+		start--
+	}
+	for end < len(content) && !isRuneStart(content[end]) {
+		end++
+	}
+	s := strings.Join(strings.Fields(content[start:end]), " ")
+	if start > 0 {
+		s = "..." + s
+	}
+	if end < len(content) {
+		s += "..."
+	}
+	return s
+}
+
+func isRuneStart(b byte) bool { return b&0xC0 != 0x80 }
@@ -0,0 +1,58 @@
+package guide
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type fakeSearchGuideClient struct {
+	fakeGuideClient
+}
+
+func (f *fakeSearchGuideClient) Get(ctx context.Context, path string) ([]byte, error) {
+	if path == fmt.Sprintf("%s/providers.mdx", moduleDevBase) {
+		return []byte("# Providers\n\nA module may declare a Provider Alias\nusing configuration_aliases."), nil
+	}
+	return f.fakeGuideClient.Get(ctx, path)
+}
+
+func TestSearch_ReportsOnlyMatchingSection(t *testing.T) {
+	matches, err := Search(context.Background(), &fakeSearchGuideClient{}, SearchOptions{Query: "alias"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 match, got %+v", matches)
+	}
+	m := matches[0]
+	if m.Guide != "module-dev" || m.Section != "providers" {
+		t.Fatalf("unexpected match: %+v", m)
+	}
+	if m.Matches != 2 {
+		t.Fatalf("expected 2 occurrences, got %d", m.Matches)
+	}
+	if !strings.Contains(m.Snippet, "Provider Alias using") {
+		t.Fatalf("expected snippet around match on one line, got %q", m.Snippet)
+	}
+}
+
+func TestSearch_IncludeStyle(t *testing.T) {
+	matches, err := Search(context.Background(), &fakeSearchGuideClient{}, SearchOptions{Query: "CONSISTENT", IncludeStyle: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(matches) != 1 || matches[0].Guide != "style" {
+		t.Fatalf("expected only the style guide to match, got %+v", matches)
+	}
+}
+
+func TestSearch_RequiresQuery(t *testing.T) {
+	_, err := Search(context.Background(), &fakeSearchGuideClient{}, SearchOptions{Query: " "})
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+}
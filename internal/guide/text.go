@@ -0,0 +1,65 @@
+package guide
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// StripBOM removes a leading UTF-8 byte order mark, if any.
+func StripBOM(b []byte) []byte {
+	return bytes.TrimPrefix(b, utf8BOM)
+}
+
+// NativeEOL is the line ending style of the running OS: crlf on Windows,
+// lf elsewhere.
+func NativeEOL() string {
+	if runtime.GOOS == "windows" {
+		return "crlf"
+	}
+	return "lf"
+}
+
+// NormalizeEOL rewrites every CRLF and LF line ending in b to eol, which
+// must be "lf" or "crlf"; any other value returns b unchanged. Mixed input
+// comes out uniform.
+func NormalizeEOL(b []byte, eol string) []byte {
+	if eol != "lf" && eol != "crlf" {
+		return b
+	}
+	lf := bytes.ReplaceAll(b, []byte("\r\n"), []byte("\n"))
+	if eol == "lf" {
+		return lf
+	}
+	return bytes.ReplaceAll(lf, []byte("\n"), []byte("\r\n"))
+}
+
+// TextOptions controls post-processing of fetched guide content.
+type TextOptions struct {
+	StripBOM bool
+	// EOL is keep (or empty) to leave line endings as fetched, or lf, crlf
+	// or native to normalize them.
+	EOL string
+}
+
+// ApplyTextOptions post-processes fetched guide content per opts.
+func ApplyTextOptions(content string, opts TextOptions) (string, error) {
+	eol := strings.ToLower(strings.TrimSpace(opts.EOL))
+	switch eol {
+	case "", "keep":
+		eol = ""
+	case "native":
+		eol = NativeEOL()
+	case "lf", "crlf":
+	default:
+		return "", &ValidationError{Message: fmt.Sprintf("unsupported -eol: %s (valid: keep, lf, crlf, native)", opts.EOL)}
+	}
+	b := []byte(content)
+	if opts.StripBOM {
+		b = StripBOM(b)
+	}
+	return string(NormalizeEOL(b, eol)), nil
+}
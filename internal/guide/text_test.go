@@ -0,0 +1,44 @@
+package guide
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeEOL_MixedInput(t *testing.T) {
+	input := []byte("a\r\nb\nc\r\n")
+	if got := string(NormalizeEOL(input, "lf")); got != "a\nb\nc\n" {
+		t.Fatalf("lf: got %q", got)
+	}
+	if got := string(NormalizeEOL(input, "crlf")); got != "a\r\nb\r\nc\r\n" {
+		t.Fatalf("crlf: got %q", got)
+	}
+	if got := string(NormalizeEOL(input, "")); got != string(input) {
+		t.Fatalf("empty mode must keep input, got %q", got)
+	}
+}
+
+func TestApplyTextOptions_StripsBOMAndNormalizes(t *testing.T) {
+	input := "\xEF\xBB\xBF# Style\r\n\nBe consistent.\r\n"
+	got, err := ApplyTextOptions(input, TextOptions{StripBOM: true, EOL: "crlf"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "# Style\r\n\r\nBe consistent.\r\n" {
+		t.Fatalf("unexpected output: %q", got)
+	}
+
+	kept, err := ApplyTextOptions(input, TextOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kept != input {
+		t.Fatalf("default options must not change content, got %q", kept)
+	}
+
+	_, err = ApplyTextOptions(input, TextOptions{EOL: "cr"})
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError for bad -eol, got %v", err)
+	}
+}
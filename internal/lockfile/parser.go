@@ -101,11 +101,37 @@ func parseProviderAddress(addr string) (namespace, name string, err error) {
 		return "", "", fmt.Errorf("invalid provider address: expected hostname/namespace/name, got %q", addr)
 	}
 	// Take the last two segments as namespace and name, allowing for hostnames
-	// with multiple parts (though uncommon).
-	namespace = parts[len(parts)-2]
-	name = parts[len(parts)-1]
+	// with multiple parts (though uncommon). Registry addresses are
+	// case-insensitive, so normalize to the lowercase form the registry uses.
+	namespace = strings.ToLower(strings.TrimSpace(parts[len(parts)-2]))
+	name = strings.ToLower(strings.TrimSpace(parts[len(parts)-1]))
 	if namespace == "" || name == "" {
 		return "", "", fmt.Errorf("invalid provider address: empty namespace or name in %q", addr)
 	}
 	return namespace, name, nil
 }
+
+// CheckDuplicates returns a ParseError for path when locks contain the same
+// provider address (compared case-insensitively) more than once, listing
+// the versions each occurrence pins.
+func CheckDuplicates(path string, locks []ProviderLock) error {
+	versions := make(map[string][]string)
+	var order []string
+	for _, lock := range locks {
+		key := strings.ToLower(lock.Address)
+		if _, seen := versions[key]; !seen {
+			order = append(order, key)
+		}
+		versions[key] = append(versions[key], lock.Version)
+	}
+	var dups []string
+	for _, key := range order {
+		if vs := versions[key]; len(vs) > 1 {
+			dups = append(dups, fmt.Sprintf("%s (versions %s)", key, strings.Join(vs, ", ")))
+		}
+	}
+	if len(dups) == 0 {
+		return nil
+	}
+	return &ParseError{Path: path, Err: fmt.Errorf("duplicate provider entries: %s", strings.Join(dups, "; "))}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -147,6 +148,7 @@ func TestParseProviderAddress(t *testing.T) {
 		{"registry.terraform.io/hashicorp/aws", "hashicorp", "aws", false},
 		{"registry.terraform.io/integrations/github", "integrations", "github", false},
 		{"custom.example.com/myorg/myprovider", "myorg", "myprovider", false},
+		{"registry.terraform.io/HashiCorp/AWS", "hashicorp", "aws", false},
 		{"hashicorp/aws", "", "", true},
 		{"aws", "", "", true},
 		{"", "", "", true},
@@ -198,3 +200,35 @@ func assertLock(t *testing.T, got ProviderLock, wantAddr, wantNS, wantName, want
 		t.Errorf("Version: got %q, want %q", got.Version, wantVersion)
 	}
 }
+
+func TestCheckDuplicates_ReportsRepeatedAddress(t *testing.T) {
+	content := `
+provider "registry.terraform.io/hashicorp/aws" {
+  version = "5.31.0"
+}
+
+provider "registry.terraform.io/hashicorp/random" {
+  version = "3.6.0"
+}
+
+provider "registry.terraform.io/HashiCorp/aws" {
+  version = "5.40.0"
+}
+`
+	path := writeTempLockfile(t, content)
+	locks, err := ParseFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = CheckDuplicates(path, locks)
+	var pErr *ParseError
+	if !errors.As(err, &pErr) {
+		t.Fatalf("expected ParseError, got %T: %v", err, err)
+	}
+	if want := "registry.terraform.io/hashicorp/aws (versions 5.31.0, 5.40.0)"; !strings.Contains(err.Error(), want) {
+		t.Fatalf("expected %q in error, got %v", want, err)
+	}
+	if err := CheckDuplicates(path, locks[:2]); err != nil {
+		t.Fatalf("expected no error without duplicates, got %v", err)
+	}
+}
@@ -5,7 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"path"
 	"strings"
+
+	"github.com/mkusaka/tfdc/internal/semver"
 )
 
 // APIClient is the interface needed for module operations.
@@ -19,6 +22,9 @@ type SearchOptions struct {
 	Query  string
 	Offset int
 	Limit  int
+	// ResolveLatest collapses results to one row per namespace/name/provider,
+	// keeping the highest version among them.
+	ResolveLatest bool
 }
 
 // SearchResult represents one matching module.
@@ -33,9 +39,72 @@ type SearchResult struct {
 
 // GetResult holds the result of fetching a module.
 type GetResult struct {
-	ID      string
-	Content string // readme content for text/markdown
-	Raw     json.RawMessage
+	ID       string
+	Content  string // readme content for text/markdown
+	Raw      json.RawMessage
+	Metadata Metadata  // structured fields for -format json
+	Examples []Example // example directories shipped with the module
+}
+
+// Example is an example directory shipped with a module version.
+type Example struct {
+	Name    string   `json:"name"`
+	Path    string   `json:"path"`
+	Source  string   `json:"source"` // registry address usable as a module source
+	Readme  string   `json:"readme"`
+	Inputs  []Input  `json:"inputs"`
+	Outputs []Output `json:"outputs"`
+}
+
+// Metadata is the structured view of a module version.
+type Metadata struct {
+	ID                   string               `json:"id"`
+	Namespace            string               `json:"namespace"`
+	Name                 string               `json:"name"`
+	Provider             string               `json:"provider"`
+	Version              string               `json:"version"`
+	Description          string               `json:"description"`
+	Source               string               `json:"source"`
+	PublishedAt          string               `json:"published_at"`
+	Downloads            int                  `json:"downloads"`
+	Verified             bool                 `json:"verified"`
+	Providers            []string             `json:"providers"`
+	Versions             []string             `json:"versions"`
+	Inputs               []Input              `json:"inputs"`
+	Outputs              []Output             `json:"outputs"`
+	Dependencies         []Dependency         `json:"dependencies"`
+	ProviderDependencies []ProviderDependency `json:"provider_dependencies"`
+	Readme               string               `json:"readme"`
+}
+
+// Input is a root module input variable.
+type Input struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	Default     any    `json:"default"`
+	Required    bool   `json:"required"`
+}
+
+// Output is a root module output value.
+type Output struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Dependency is a module called by the root module.
+type Dependency struct {
+	Name    string `json:"name"`
+	Source  string `json:"source"`
+	Version string `json:"version"`
+}
+
+// ProviderDependency is a provider required by the root module.
+type ProviderDependency struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Source    string `json:"source"`
+	Version   string `json:"version"`
 }
 
 type v1ModuleSearchResponse struct {
@@ -54,9 +123,32 @@ type v1ModuleSearchResponse struct {
 }
 
 type v1ModuleGetResponse struct {
-	Root struct {
-		Readme string `json:"readme"`
+	ID          string   `json:"id"`
+	Namespace   string   `json:"namespace"`
+	Name        string   `json:"name"`
+	Provider    string   `json:"provider"`
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	Source      string   `json:"source"`
+	PublishedAt string   `json:"published_at"`
+	Downloads   int      `json:"downloads"`
+	Verified    bool     `json:"verified"`
+	Providers   []string `json:"providers"`
+	Versions    []string `json:"versions"`
+	Root        struct {
+		Readme               string               `json:"readme"`
+		Inputs               []Input              `json:"inputs"`
+		Outputs              []Output             `json:"outputs"`
+		Dependencies         []Dependency         `json:"dependencies"`
+		ProviderDependencies []ProviderDependency `json:"provider_dependencies"`
 	} `json:"root"`
+	Examples []struct {
+		Name    string   `json:"name"`
+		Path    string   `json:"path"`
+		Readme  string   `json:"readme"`
+		Inputs  []Input  `json:"inputs"`
+		Outputs []Output `json:"outputs"`
+	} `json:"examples"`
 }
 
 // SearchModules searches the Terraform module registry.
@@ -93,9 +185,47 @@ func SearchModules(ctx context.Context, client APIClient, opts SearchOptions) ([
 			PublishedAt: m.PublishedAt,
 		}
 	}
+	if opts.ResolveLatest {
+		results = collapseToLatest(results)
+	}
 	return results, len(results), nil
 }
 
+// collapseToLatest keeps the highest-versioned result per
+// namespace/name/provider, in order of each module's first appearance. IDs
+// that are not namespace/name/provider/version are kept as-is.
+func collapseToLatest(results []SearchResult) []SearchResult {
+	out := make([]SearchResult, 0, len(results))
+	index := make(map[string]int)
+	for _, r := range results {
+		base, version, ok := splitModuleVersion(r.ModuleID)
+		if !ok {
+			out = append(out, r)
+			continue
+		}
+		i, seen := index[base]
+		if !seen {
+			index[base] = len(out)
+			out = append(out, r)
+			continue
+		}
+		if _, current, _ := splitModuleVersion(out[i].ModuleID); semver.Compare(version, current) > 0 {
+			out[i] = r
+		}
+	}
+	return out
+}
+
+// splitModuleVersion splits "ns/name/provider/version" into its module
+// address and version.
+func splitModuleVersion(id string) (base, version string, ok bool) {
+	i := strings.LastIndexByte(id, '/')
+	if i < 0 || strings.Count(id, "/") != 3 {
+		return "", "", false
+	}
+	return id[:i], id[i+1:], true
+}
+
 // GetModule fetches details for a specific module.
 // id must be in namespace/name/provider/version format (4 segments).
 func GetModule(ctx context.Context, client APIClient, id string) (*GetResult, error) {
@@ -109,11 +239,11 @@ func GetModule(ctx context.Context, client APIClient, id string) (*GetResult, er
 		return nil, &ValidationError{Message: fmt.Sprintf("-id must have 4 segments (namespace/name/provider/version), got %d", len(parts))}
 	}
 
-	path := fmt.Sprintf("/v1/modules/%s/%s/%s/%s",
+	modulePath := fmt.Sprintf("/v1/modules/%s/%s/%s/%s",
 		url.PathEscape(parts[0]), url.PathEscape(parts[1]),
 		url.PathEscape(parts[2]), url.PathEscape(parts[3]))
 
-	raw, err := client.Get(ctx, path)
+	raw, err := client.Get(ctx, modulePath)
 	if err != nil {
 		return nil, err
 	}
@@ -123,16 +253,87 @@ func GetModule(ctx context.Context, client APIClient, id string) (*GetResult, er
 		return nil, fmt.Errorf("failed to parse module response: %w", err)
 	}
 
+	examples := make([]Example, 0, len(parsed.Examples))
+	for _, ex := range parsed.Examples {
+		name := ex.Name
+		if name == "" {
+			name = path.Base(ex.Path)
+		}
+		examples = append(examples, Example{
+			Name:    name,
+			Path:    ex.Path,
+			Source:  fmt.Sprintf("%s/%s/%s//%s", parts[0], parts[1], parts[2], ex.Path),
+			Readme:  ex.Readme,
+			Inputs:  nonNil(ex.Inputs),
+			Outputs: nonNil(ex.Outputs),
+		})
+	}
+
 	return &GetResult{
-		ID:      id,
-		Content: parsed.Root.Readme,
-		Raw:     raw,
+		ID:       id,
+		Content:  parsed.Root.Readme,
+		Raw:      raw,
+		Examples: examples,
+		Metadata: Metadata{
+			ID:                   id,
+			Namespace:            parsed.Namespace,
+			Name:                 parsed.Name,
+			Provider:             parsed.Provider,
+			Version:              parsed.Version,
+			Description:          parsed.Description,
+			Source:               parsed.Source,
+			PublishedAt:          parsed.PublishedAt,
+			Downloads:            parsed.Downloads,
+			Verified:             parsed.Verified,
+			Providers:            nonNil(parsed.Providers),
+			Versions:             nonNil(parsed.Versions),
+			Inputs:               nonNil(parsed.Root.Inputs),
+			Outputs:              nonNil(parsed.Root.Outputs),
+			Dependencies:         nonNil(parsed.Root.Dependencies),
+			ProviderDependencies: nonNil(parsed.Root.ProviderDependencies),
+			Readme:               parsed.Root.Readme,
+		},
 	}, nil
 }
 
+// Example returns the example called name, matched case-insensitively
+// against its name or path.
+func (r *GetResult) Example(name string) (Example, error) {
+	name = strings.TrimSpace(name)
+	for _, ex := range r.Examples {
+		if strings.EqualFold(ex.Name, name) || strings.EqualFold(ex.Path, name) {
+			return ex, nil
+		}
+	}
+	names := make([]string, len(r.Examples))
+	for i, ex := range r.Examples {
+		names[i] = ex.Name
+	}
+	if len(names) == 0 {
+		return Example{}, &NotFoundError{Message: fmt.Sprintf("module %s has no examples", r.ID)}
+	}
+	return Example{}, &NotFoundError{Message: fmt.Sprintf("example %q not found in module %s (available: %s)", name, r.ID, strings.Join(names, ", "))}
+}
+
+// nonNil returns s, or an empty slice when s is nil, so JSON output has []
+// rather than null for absent lists.
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
 // ValidationError indicates invalid input.
 type ValidationError struct {
 	Message string
 }
 
 func (e *ValidationError) Error() string { return e.Message }
+
+// NotFoundError indicates a requested part of a module does not exist.
+type NotFoundError struct {
+	Message string
+}
+
+func (e *NotFoundError) Error() string { return e.Message }
@@ -42,8 +42,34 @@ func (f *fakeModuleClient) GetJSON(_ context.Context, path string, dst any) erro
 func (f *fakeModuleClient) Get(_ context.Context, path string) ([]byte, error) {
 	if path == "/v1/modules/terraform-aws-modules/vpc/aws/6.0.1" {
 		return json.Marshal(map[string]any{
+			"id":           "terraform-aws-modules/vpc/aws/6.0.1",
+			"namespace":    "terraform-aws-modules",
+			"name":         "vpc",
+			"provider":     "aws",
+			"version":      "6.0.1",
+			"source":       "https://github.com/terraform-aws-modules/terraform-aws-vpc",
+			"published_at": "2024-01-15T00:00:00Z",
+			"downloads":    50000,
+			"verified":     true,
+			"providers":    []string{"aws"},
+			"versions":     []string{"5.0.0", "6.0.1"},
 			"root": map[string]any{
 				"readme": "# VPC Module\n\nThis module creates a VPC.",
+				"inputs": []map[string]any{
+					{"name": "cidr", "type": "string", "description": "VPC CIDR", "default": "\"10.0.0.0/16\"", "required": false},
+					{"name": "name", "type": "string", "description": "Name prefix", "required": true},
+				},
+				"outputs": []map[string]any{
+					{"name": "vpc_id", "description": "The ID of the VPC"},
+				},
+				"provider_dependencies": []map[string]any{
+					{"name": "aws", "namespace": "hashicorp", "source": "hashicorp/aws", "version": ">= 5.0"},
+				},
+			},
+			"examples": []map[string]any{
+				{"name": "complete", "path": "examples/complete", "readme": "# Complete VPC",
+					"inputs": []map[string]any{{"name": "region", "type": "string", "required": true}}},
+				{"path": "examples/simple", "readme": "# Simple VPC"},
 			},
 		})
 	}
@@ -69,6 +95,49 @@ func TestSearchModules_Success(t *testing.T) {
 	}
 }
 
+type fakeModuleSearchClient struct {
+	fakeModuleClient
+	ids []string
+}
+
+func (f *fakeModuleSearchClient) GetJSON(_ context.Context, _ string, dst any) error {
+	modules := make([]map[string]any, len(f.ids))
+	for i, id := range f.ids {
+		modules[i] = map[string]any{"id": id, "name": strings.Split(id, "/")[1]}
+	}
+	b, _ := json.Marshal(map[string]any{"modules": modules})
+	return json.Unmarshal(b, dst)
+}
+
+func TestSearchModules_ResolveLatestKeepsHighestVersion(t *testing.T) {
+	client := &fakeModuleSearchClient{ids: []string{
+		"terraform-aws-modules/vpc/aws/5.9.0",
+		"terraform-aws-modules/eks/aws/20.0.0",
+		"terraform-aws-modules/vpc/aws/5.10.1",
+		"terraform-aws-modules/vpc/aws/6.0.0-beta1",
+	}}
+	results, total, err := SearchModules(context.Background(), client, SearchOptions{Query: "aws", ResolveLatest: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var ids []string
+	for _, r := range results {
+		ids = append(ids, r.ModuleID)
+	}
+	want := []string{"terraform-aws-modules/vpc/aws/6.0.0-beta1", "terraform-aws-modules/eks/aws/20.0.0"}
+	if strings.Join(ids, ",") != strings.Join(want, ",") || total != 2 {
+		t.Fatalf("expected %v (total 2), got %v (total %d)", want, ids, total)
+	}
+
+	results, _, err = SearchModules(context.Background(), client, SearchOptions{Query: "aws"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("expected all versions without -resolve-latest, got %d", len(results))
+	}
+}
+
 func TestSearchModules_EmptyQuery(t *testing.T) {
 	_, _, err := SearchModules(context.Background(), &fakeModuleClient{}, SearchOptions{Query: ""})
 	if err == nil {
@@ -109,3 +178,79 @@ func TestGetModule_InvalidSegments(t *testing.T) {
 		t.Errorf("expected segment count error, got: %v", err)
 	}
 }
+
+func TestGetModule_ParsesMetadata(t *testing.T) {
+	result, err := GetModule(context.Background(), &fakeModuleClient{}, "terraform-aws-modules/vpc/aws/6.0.1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	b, err := json.Marshal(result.Metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]any{
+		"downloads":    float64(50000),
+		"published_at": "2024-01-15T00:00:00Z",
+		"verified":     true,
+		"version":      "6.0.1",
+	} {
+		if got[key] != want {
+			t.Errorf("%s: got %v, want %v", key, got[key], want)
+		}
+	}
+	if providers, _ := got["providers"].([]any); len(providers) != 1 || providers[0] != "aws" {
+		t.Errorf("unexpected providers: %v", got["providers"])
+	}
+	if deps, _ := got["provider_dependencies"].([]any); len(deps) != 1 {
+		t.Errorf("unexpected provider_dependencies: %v", got["provider_dependencies"])
+	}
+	if deps, ok := got["dependencies"].([]any); !ok || len(deps) != 0 {
+		t.Errorf("expected empty dependencies array, got %v", got["dependencies"])
+	}
+	if len(result.Metadata.Inputs) != 2 || !result.Metadata.Inputs[1].Required {
+		t.Errorf("unexpected inputs: %+v", result.Metadata.Inputs)
+	}
+	if !strings.Contains(result.Metadata.Readme, "VPC Module") {
+		t.Errorf("expected readme in metadata, got %q", result.Metadata.Readme)
+	}
+}
+
+func TestGetModule_Examples(t *testing.T) {
+	result, err := GetModule(context.Background(), &fakeModuleClient{}, "terraform-aws-modules/vpc/aws/6.0.1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Examples) != 2 {
+		t.Fatalf("expected 2 examples, got %+v", result.Examples)
+	}
+	if result.Examples[0].Name != "complete" || result.Examples[1].Name != "simple" {
+		t.Errorf("unexpected example names: %q, %q", result.Examples[0].Name, result.Examples[1].Name)
+	}
+	if got, want := result.Examples[0].Source, "terraform-aws-modules/vpc/aws//examples/complete"; got != want {
+		t.Errorf("expected source %q, got %q", want, got)
+	}
+
+	ex, err := result.Example("examples/simple")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ex.Readme != "# Simple VPC" {
+		t.Errorf("expected simple readme, got %q", ex.Readme)
+	}
+	ex, err = result.Example("Complete")
+	if err != nil || len(ex.Inputs) != 1 || ex.Inputs[0].Name != "region" {
+		t.Fatalf("expected complete example with inputs, got %+v (%v)", ex, err)
+	}
+
+	_, err = result.Example("missing")
+	var nfErr *NotFoundError
+	if !errors.As(err, &nfErr) || !strings.Contains(nfErr.Message, "complete, simple") {
+		t.Fatalf("expected NotFoundError listing available examples, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package output
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -12,6 +13,22 @@ import (
 type SearchResult struct {
 	Items []map[string]any `json:"items"`
 	Total int              `json:"total"`
+	// HasMore is set by searches that can tell whether -limit truncated
+	// the results; it is omitted elsewhere.
+	HasMore *bool `json:"has_more,omitempty"`
+	// PagesFetched is set by searches that paginate the registry listing;
+	// it is omitted elsewhere.
+	PagesFetched *int `json:"pages_fetched,omitempty"`
+	// ByProvider counts the results each provider contributed to a search
+	// across several providers; it is omitted elsewhere.
+	ByProvider map[string]int `json:"by_provider,omitempty"`
+}
+
+// SearchMeta holds optional envelope fields for WriteSearchWithMeta.
+type SearchMeta struct {
+	HasMore      *bool
+	PagesFetched *int
+	ByProvider   map[string]int
 }
 
 // DetailResult is the JSON envelope for get/detail commands.
@@ -19,6 +36,16 @@ type DetailResult struct {
 	ID          string `json:"id"`
 	Content     string `json:"content"`
 	ContentType string `json:"content_type"`
+	// ContentEncoding is "base64" when Content is base64-encoded and
+	// omitted when Content is plain text.
+	ContentEncoding string `json:"content_encoding,omitempty"`
+}
+
+// DetailOptions holds optional settings for WriteDetailWithOptions.
+type DetailOptions struct {
+	// Base64Content base64-encodes the content in JSON output. Text and
+	// markdown output always write the content as-is.
+	Base64Content bool
 }
 
 // FormatError indicates an unsupported output format.
@@ -33,9 +60,17 @@ func (e *FormatError) Error() string {
 // WriteSearch writes search results to w in the given format.
 // columns controls the order and selection of fields for text/markdown output.
 func WriteSearch(w io.Writer, format string, items []map[string]any, total int, columns []string) error {
+	return WriteSearchWithMeta(w, format, items, total, columns, SearchMeta{})
+}
+
+// WriteSearchWithMeta is WriteSearch with extra envelope fields for JSON
+// output. Text, markdown and ndjson output ignore meta.
+func WriteSearchWithMeta(w io.Writer, format string, items []map[string]any, total int, columns []string, meta SearchMeta) error {
 	switch format {
 	case "json":
-		return writeJSON(w, SearchResult{Items: items, Total: total})
+		return writeJSON(w, SearchResult{Items: items, Total: total, HasMore: meta.HasMore, PagesFetched: meta.PagesFetched, ByProvider: meta.ByProvider})
+	case "ndjson":
+		return writeNDJSON(w, items)
 	case "text":
 		return writeTable(w, items, columns)
 	case "markdown":
@@ -47,9 +82,14 @@ func WriteSearch(w io.Writer, format string, items []map[string]any, total int,
 
 // WriteDetail writes a single detail/get result to w in the given format.
 func WriteDetail(w io.Writer, format string, id, content, contentType string) error {
+	return WriteDetailWithOptions(w, format, id, content, contentType, DetailOptions{})
+}
+
+// WriteDetailWithOptions is WriteDetail with opts applied to JSON output.
+func WriteDetailWithOptions(w io.Writer, format string, id, content, contentType string, opts DetailOptions) error {
 	switch format {
 	case "json":
-		return writeJSON(w, DetailResult{ID: id, Content: content, ContentType: contentType})
+		return writeJSON(w, NewDetailResult(id, content, contentType, opts))
 	case "text", "markdown":
 		_, err := fmt.Fprint(w, content)
 		return err
@@ -58,38 +98,101 @@ func WriteDetail(w io.Writer, format string, id, content, contentType string) er
 	}
 }
 
+// NewDetailResult builds the JSON detail for one document with opts
+// applied, for WriteDetailBatch.
+func NewDetailResult(id, content, contentType string, opts DetailOptions) DetailResult {
+	result := DetailResult{ID: id, Content: content, ContentType: contentType}
+	if opts.Base64Content {
+		result.Content = base64.StdEncoding.EncodeToString([]byte(content))
+		result.ContentEncoding = "base64"
+	}
+	return result
+}
+
+// WriteDetailBatch writes several details at once: a single JSON array in
+// json format, or the contents joined by newlines in text and markdown.
+func WriteDetailBatch(w io.Writer, format string, results []DetailResult) error {
+	switch format {
+	case "json":
+		if results == nil {
+			results = []DetailResult{}
+		}
+		return writeJSON(w, results)
+	case "text", "markdown":
+		contents := make([]string, len(results))
+		for i, r := range results {
+			contents[i] = r.Content
+		}
+		_, err := fmt.Fprint(w, strings.Join(contents, "\n"))
+		return err
+	default:
+		return &FormatError{Format: format}
+	}
+}
+
+// WriteJSON writes v as indented JSON, for commands whose JSON output is a
+// structured document rather than a search or detail envelope.
+func WriteJSON(w io.Writer, v any) error {
+	return writeJSON(w, v)
+}
+
 func writeJSON(w io.Writer, v any) error {
 	enc := json.NewEncoder(w)
 	enc.SetIndent("", "  ")
 	return enc.Encode(v)
 }
 
+// writeNDJSON writes each item as one compact JSON line, without an
+// envelope, so results stream into line-oriented tools.
+func writeNDJSON(w io.Writer, items []map[string]any) error {
+	enc := json.NewEncoder(w)
+	for _, item := range items {
+		if err := enc.Encode(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeTable and writeMarkdownTable return the first write error, so a
+// reader that closes the pipe early (e.g. head) fails the command instead
+// of it exiting 0 with truncated output.
 func writeTable(w io.Writer, items []map[string]any, columns []string) error {
 	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
-	_, _ = fmt.Fprintln(tw, strings.Join(columns, "\t"))
+	if _, err := fmt.Fprintln(tw, strings.Join(columns, "\t")); err != nil {
+		return err
+	}
 	for _, item := range items {
 		vals := make([]string, len(columns))
 		for i, col := range columns {
 			vals[i] = fmt.Sprintf("%v", item[col])
 		}
-		_, _ = fmt.Fprintln(tw, strings.Join(vals, "\t"))
+		if _, err := fmt.Fprintln(tw, strings.Join(vals, "\t")); err != nil {
+			return err
+		}
 	}
 	return tw.Flush()
 }
 
 func writeMarkdownTable(w io.Writer, items []map[string]any, columns []string) error {
-	_, _ = fmt.Fprintf(w, "| %s |\n", strings.Join(columns, " | "))
+	if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(columns, " | ")); err != nil {
+		return err
+	}
 	seps := make([]string, len(columns))
 	for i := range seps {
 		seps[i] = "---"
 	}
-	_, _ = fmt.Fprintf(w, "| %s |\n", strings.Join(seps, " | "))
+	if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(seps, " | ")); err != nil {
+		return err
+	}
 	for _, item := range items {
 		vals := make([]string, len(columns))
 		for i, col := range columns {
 			vals[i] = fmt.Sprintf("%v", item[col])
 		}
-		_, _ = fmt.Fprintf(w, "| %s |\n", strings.Join(vals, " | "))
+		if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(vals, " | ")); err != nil {
+			return err
+		}
 	}
 	return nil
 }
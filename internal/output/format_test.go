@@ -2,7 +2,9 @@ package output
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"strings"
 	"testing"
 )
@@ -62,6 +64,51 @@ func TestWriteSearch_Markdown(t *testing.T) {
 	}
 }
 
+func TestWriteSearch_NDJSON(t *testing.T) {
+	items := []map[string]any{
+		{"id": "1", "title": "foo\nbar"},
+		{"id": "2", "title": "baz"},
+	}
+	var buf bytes.Buffer
+	if err := WriteSearch(&buf, "ndjson", items, 2, []string{"id", "title"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(items) {
+		t.Fatalf("expected %d lines, got %d: %q", len(items), len(lines), buf.String())
+	}
+	for i, line := range lines {
+		var got map[string]any
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("line %d is not standalone JSON: %v", i+1, err)
+		}
+		if got["id"] != items[i]["id"] || got["title"] != items[i]["title"] {
+			t.Fatalf("line %d: expected %v, got %v", i+1, items[i], got)
+		}
+	}
+	if strings.Contains(buf.String(), "items") {
+		t.Fatalf("expected no envelope, got %s", buf.String())
+	}
+}
+
+func TestWriteSearch_NDJSONEmptyWritesNothing(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSearch(&buf, "ndjson", nil, 0, []string{"id"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("expected no output, got %q", buf.String())
+	}
+}
+
+func TestWriteDetail_RejectsNDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	var fErr *FormatError
+	if err := WriteDetail(&buf, "ndjson", "1", "content", "text/markdown"); !errors.As(err, &fErr) {
+		t.Fatalf("expected FormatError, got %v", err)
+	}
+}
+
 func TestWriteDetail_JSON(t *testing.T) {
 	var buf bytes.Buffer
 	if err := WriteDetail(&buf, "json", "123", "content here", "text/markdown"); err != nil {
@@ -79,6 +126,41 @@ func TestWriteDetail_JSON(t *testing.T) {
 	}
 }
 
+func TestWriteDetailWithOptions_Base64Content(t *testing.T) {
+	content := "line \"one\"\nline two\n"
+	var buf bytes.Buffer
+	if err := WriteDetailWithOptions(&buf, "json", "123", content, "text/markdown", DetailOptions{Base64Content: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var result DetailResult
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if result.ContentEncoding != "base64" {
+		t.Fatalf("expected content_encoding=base64, got %q", result.ContentEncoding)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(result.Content)
+	if err != nil {
+		t.Fatalf("content is not base64: %v", err)
+	}
+	if string(decoded) != content {
+		t.Fatalf("decoded content mismatch: %q", decoded)
+	}
+	if !strings.Contains(buf.String(), `"content_encoding"`) {
+		t.Fatalf("expected content_encoding field in %s", buf.String())
+	}
+}
+
+func TestWriteDetail_JSONOmitsContentEncoding(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteDetail(&buf, "json", "123", "plain", "text/markdown"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(buf.String(), "content_encoding") {
+		t.Fatalf("expected no content_encoding by default, got %s", buf.String())
+	}
+}
+
 func TestWriteDetail_Text(t *testing.T) {
 	var buf bytes.Buffer
 	if err := WriteDetail(&buf, "text", "123", "raw content", "text/markdown"); err != nil {
@@ -114,3 +196,115 @@ func TestWriteDetail_UnsupportedFormat(t *testing.T) {
 		t.Fatal("expected error for unsupported format")
 	}
 }
+
+func TestWriteSearchWithMeta_JSONHasMore(t *testing.T) {
+	more := true
+	var buf bytes.Buffer
+	items := []map[string]any{{"id": "1"}}
+	if err := WriteSearchWithMeta(&buf, "json", items, 1, []string{"id"}, SearchMeta{HasMore: &more}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"has_more": true`) {
+		t.Fatalf("expected has_more in envelope, got %s", buf.String())
+	}
+}
+
+func TestWriteSearchWithMeta_JSONPagesFetched(t *testing.T) {
+	pages := 3
+	var buf bytes.Buffer
+	items := []map[string]any{{"id": "1"}}
+	if err := WriteSearchWithMeta(&buf, "json", items, 1, []string{"id"}, SearchMeta{PagesFetched: &pages}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"pages_fetched": 3`) {
+		t.Fatalf("expected pages_fetched in envelope, got %s", buf.String())
+	}
+	buf.Reset()
+	if err := WriteSearch(&buf, "json", items, 1, []string{"id"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(buf.String(), "pages_fetched") {
+		t.Fatalf("expected pages_fetched to be omitted, got %s", buf.String())
+	}
+}
+
+func TestWriteDetailBatch_JSONArray(t *testing.T) {
+	results := []DetailResult{
+		{ID: "1", Content: "# one", ContentType: "text/markdown"},
+		{ID: "2", Content: "# two", ContentType: "text/markdown"},
+	}
+	var buf bytes.Buffer
+	if err := WriteDetailBatch(&buf, "json", results); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []DetailResult
+	dec := json.NewDecoder(&buf)
+	if err := dec.Decode(&got); err != nil {
+		t.Fatalf("expected a single JSON array: %v", err)
+	}
+	if dec.More() {
+		t.Fatal("expected exactly one JSON value")
+	}
+	if len(got) != 2 || got[0].ID != "1" || got[1].Content != "# two" {
+		t.Fatalf("unexpected results: %+v", got)
+	}
+
+	buf.Reset()
+	if err := WriteDetailBatch(&buf, "text", results); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.String() != "# one\n# two" {
+		t.Fatalf("expected newline-joined contents, got %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteDetailBatch(&buf, "json", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.TrimSpace(buf.String()) != "[]" {
+		t.Fatalf("expected an empty array, got %q", buf.String())
+	}
+}
+
+var errClosedPipe = errors.New("closed pipe")
+
+// failingWriter accepts limit bytes and then fails every write, like a
+// pipe whose reader exited partway through the output.
+type failingWriter struct {
+	limit int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.limit {
+		n := w.limit
+		w.limit = 0
+		return n, errClosedPipe
+	}
+	w.limit -= len(p)
+	return len(p), nil
+}
+
+func TestWriters_PropagateWriteErrors(t *testing.T) {
+	items := make([]map[string]any, 200)
+	for i := range items {
+		items[i] = map[string]any{"id": i, "title": strings.Repeat("x", 40)}
+	}
+	columns := []string{"id", "title"}
+
+	for _, format := range []string{"json", "ndjson", "text", "markdown"} {
+		err := WriteSearch(&failingWriter{limit: 64}, format, items, len(items), columns)
+		if !errors.Is(err, errClosedPipe) {
+			t.Fatalf("%s search: expected the write error, got %v", format, err)
+		}
+	}
+	for _, format := range []string{"json", "text", "markdown"} {
+		err := WriteDetail(&failingWriter{limit: 0}, format, "1", "# doc", "text/markdown")
+		if !errors.Is(err, errClosedPipe) {
+			t.Fatalf("%s detail: expected the write error, got %v", format, err)
+		}
+		err = WriteDetailBatch(&failingWriter{limit: 0}, format, []DetailResult{{ID: "1", Content: "# doc"}})
+		if !errors.Is(err, errClosedPipe) {
+			t.Fatalf("%s batch: expected the write error, got %v", format, err)
+		}
+	}
+}
@@ -59,22 +59,43 @@ type v2PolicyDetailResponse struct {
 	} `json:"data"`
 }
 
+// SearchPage is the result of SearchPoliciesPage.
+type SearchPage struct {
+	Results []SearchResult
+	Total   int
+	// PagesFetched counts the /v2/policies pages requested, including the
+	// empty page that ends the listing.
+	PagesFetched int
+}
+
 // SearchPolicies searches for policies matching the query.
 // It fetches all policies (paginated) and filters client-side.
 func SearchPolicies(ctx context.Context, client APIClient, query string) ([]SearchResult, int, error) {
+	page, err := SearchPoliciesPage(ctx, client, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	return page.Results, page.Total, nil
+}
+
+// SearchPoliciesPage is like SearchPolicies but also reports how many
+// listing pages were fetched.
+func SearchPoliciesPage(ctx context.Context, client APIClient, query string) (SearchPage, error) {
 	query = strings.TrimSpace(query)
 	if query == "" {
-		return nil, 0, &ValidationError{Message: "-query is required"}
+		return SearchPage{}, &ValidationError{Message: "-query is required"}
 	}
 
 	lowerQuery := strings.ToLower(query)
 	var results []SearchResult
+	pages := 0
 	for page := 1; ; page++ {
 		path := fmt.Sprintf("/v2/policies?page[size]=100&page[number]=%d&include=latest-version", page)
 		var resp v2PoliciesResponse
 		if err := client.GetJSON(ctx, path, &resp); err != nil {
-			return nil, 0, err
+			return SearchPage{}, err
 		}
+		pages++
 		if len(resp.Data) == 0 {
 			break
 		}
@@ -101,7 +122,7 @@ func SearchPolicies(ctx context.Context, client APIClient, query string) ([]Sear
 			})
 		}
 	}
-	return results, len(results), nil
+	return SearchPage{Results: results, Total: len(results), PagesFetched: pages}, nil
 }
 
 // GetPolicy fetches details for a specific policy.
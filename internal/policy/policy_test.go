@@ -154,3 +154,43 @@ func TestExtractPolicyID(t *testing.T) {
 		}
 	}
 }
+
+// pagedPolicyClient serves pages policies, one matching policy per page,
+// followed by an empty page.
+type pagedPolicyClient struct {
+	pages int
+}
+
+func (f *pagedPolicyClient) GetJSON(_ context.Context, path string, dst any) error {
+	var page int
+	if _, err := fmt.Sscanf(path[strings.Index(path, "page[number]=")+len("page[number]="):], "%d", &page); err != nil {
+		return fmt.Errorf("unexpected GetJSON path: %s", path)
+	}
+	data := []map[string]any{}
+	if page <= f.pages {
+		data = append(data, map[string]any{
+			"id":         fmt.Sprintf("hashicorp/policy-%d", page),
+			"attributes": map[string]any{"name": fmt.Sprintf("policy-%d", page)},
+		})
+	}
+	b, _ := json.Marshal(map[string]any{"data": data})
+	return json.Unmarshal(b, dst)
+}
+
+func (f *pagedPolicyClient) Get(_ context.Context, path string) ([]byte, error) {
+	return nil, fmt.Errorf("unexpected Get path: %s", path)
+}
+
+func TestSearchPoliciesPage_CountsPagesFetched(t *testing.T) {
+	page, err := SearchPoliciesPage(context.Background(), &pagedPolicyClient{pages: 3}, "policy")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Total != 3 {
+		t.Fatalf("expected 3 results, got %d", page.Total)
+	}
+	// Three pages of data plus the empty page that ends the listing.
+	if page.PagesFetched != 4 {
+		t.Fatalf("expected 4 pages fetched, got %d", page.PagesFetched)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"sync"
 	"time"
 )
@@ -21,20 +22,62 @@ type Spinner struct {
 	started  bool
 	stopOnce sync.Once
 	isTTY    bool
+	disabled bool
+}
+
+// Mode selects how a Spinner reports status.
+type Mode string
+
+const (
+	// ModeAuto animates on a terminal and prints plain lines otherwise.
+	ModeAuto Mode = "auto"
+	// ModeAlways animates even when w is not a terminal.
+	ModeAlways Mode = "always"
+	// ModeNever suppresses status output; Log still prints.
+	ModeNever Mode = "never"
+)
+
+// ParseMode parses a -progress value. An empty value is ModeAuto.
+func ParseMode(s string) (Mode, error) {
+	switch m := Mode(strings.ToLower(strings.TrimSpace(s))); m {
+	case "":
+		return ModeAuto, nil
+	case ModeAuto, ModeAlways, ModeNever:
+		return m, nil
+	default:
+		return "", fmt.Errorf("unsupported -progress: %s (valid: auto, always, never)", s)
+	}
 }
 
 // New creates a new Spinner that writes to w.
 func New(w io.Writer) *Spinner {
-	return &Spinner{
+	return NewWithMode(w, ModeAuto)
+}
+
+// NewWithMode creates a Spinner that writes to w, overriding terminal
+// detection according to mode.
+func NewWithMode(w io.Writer, mode Mode) *Spinner {
+	s := &Spinner{
 		w:      w,
 		done:   make(chan struct{}),
 		exited: make(chan struct{}),
-		isTTY:  isTerminal(w),
 	}
+	switch mode {
+	case ModeAlways:
+		s.isTTY = true
+	case ModeNever:
+		s.disabled = true
+	default:
+		s.isTTY = isTerminal(w)
+	}
+	return s
 }
 
 // Start begins the spinner animation with the given message.
 func (s *Spinner) Start(msg string) {
+	if s.disabled {
+		return
+	}
 	s.mu.Lock()
 	if s.started {
 		s.mu.Unlock()
@@ -63,9 +106,8 @@ func (s *Spinner) run() {
 			return
 		case <-ticker.C:
 			s.mu.Lock()
-			msg := s.message
+			_, _ = fmt.Fprintf(s.w, "\r\033[K%s %s", frames[i%len(frames)], s.message)
 			s.mu.Unlock()
-			_, _ = fmt.Fprintf(s.w, "\r\033[K%s %s", frames[i%len(frames)], msg)
 			i++
 		}
 	}
@@ -84,6 +126,18 @@ func (s *Spinner) Update(msg string) {
 	}
 }
 
+// Log prints msg on its own line without disturbing the status message. On a
+// terminal the spinner line is cleared first and redrawn on the next tick.
+func (s *Spinner) Log(msg string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.isTTY {
+		_, _ = fmt.Fprintf(s.w, "\r\033[K%s\n", msg)
+		return
+	}
+	_, _ = fmt.Fprintf(s.w, "%s\n", msg)
+}
+
 // Stop halts the spinner and clears the line.
 // Safe to call multiple times and even if Start was never called.
 func (s *Spinner) Stop() {
@@ -117,3 +117,57 @@ func TestSpinner_NonTTY_StopIsFast(t *testing.T) {
 		t.Fatalf("Stop took too long for non-TTY spinner: %v", elapsed)
 	}
 }
+
+func TestSpinner_NonTTY_LogPrintsLine(t *testing.T) {
+	var buf bytes.Buffer
+	s := New(&buf)
+
+	s.Start("starting")
+	s.Log("wrote a.md")
+	s.Stop()
+
+	if got, want := buf.String(), "starting\nwrote a.md\n"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestNewWithMode_OutputPerMode(t *testing.T) {
+	run := func(mode Mode) string {
+		var buf bytes.Buffer
+		s := NewWithMode(&buf, mode)
+		s.Start("starting")
+		s.Update("step 1")
+		if mode == ModeAlways {
+			time.Sleep(200 * time.Millisecond) // let the animation draw a frame
+		}
+		s.Log("wrote a.md")
+		s.Stop()
+		return buf.String()
+	}
+
+	if got, want := run(ModeAuto), "starting\nstep 1\nwrote a.md\n"; got != want {
+		t.Fatalf("auto: expected %q, got %q", want, got)
+	}
+	if got, want := run(ModeNever), "wrote a.md\n"; got != want {
+		t.Fatalf("never: expected only log lines %q, got %q", want, got)
+	}
+	always := run(ModeAlways)
+	if !strings.Contains(always, "\r\033[K") || !strings.Contains(always, "step 1") {
+		t.Fatalf("always: expected spinner frames for a non-terminal writer, got %q", always)
+	}
+	if strings.Contains(always, "starting\n") {
+		t.Fatalf("always: expected no plain status lines, got %q", always)
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	for in, want := range map[string]Mode{"": ModeAuto, "auto": ModeAuto, "ALWAYS": ModeAlways, " never ": ModeNever} {
+		got, err := ParseMode(in)
+		if err != nil || got != want {
+			t.Fatalf("ParseMode(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseMode("sometimes"); err == nil {
+		t.Fatal("expected error for unknown mode")
+	}
+}
@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"archive/tar"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// WriteTarArchive streams every directory and regular file under root to w
+// as an uncompressed tar. Entry names are slash-separated and relative to
+// root, in lexical order, so the stream extracts to the same layout an
+// on-disk export would produce.
+func WriteTarArchive(w io.Writer, root string) error {
+	tw := tar.NewWriter(w)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsDir() && !info.Mode().IsRegular() {
+			return nil
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return &WriteError{Path: "-", Err: err}
+	}
+	if err := tw.Close(); err != nil {
+		return &WriteError{Path: "-", Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// fakeCatalogDoc describes one doc served by fakeCatalogClient. Extra is
+// merged into both the listing and detail attributes.
+type fakeCatalogDoc struct {
+	ID       string
+	Category string
+	Slug     string
+	Title    string
+	Content  string
+	Extra    map[string]any
+}
+
+// fakeCatalogClient serves hashicorp/aws@6.31.0 (version ID 70800) with a
+// configurable set of docs, all on page 1 of their category. It records every
+// path requested so tests can assert on call patterns.
+type fakeCatalogClient struct {
+	docs []fakeCatalogDoc
+
+	mu    sync.Mutex
+	calls []string
+}
+
+func (f *fakeCatalogClient) record(path string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, path)
+}
+
+func (f *fakeCatalogClient) callCount(prefix string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, c := range f.calls {
+		if strings.HasPrefix(c, prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+func (f *fakeCatalogClient) GetJSON(_ context.Context, path string, dst any) error {
+	f.record(path)
+	if strings.HasPrefix(path, "/v2/providers/hashicorp/aws") {
+		b, _ := json.Marshal(map[string]any{
+			"included": []any{
+				map[string]any{"type": "provider-versions", "id": "70800", "attributes": map[string]any{"version": "6.31.0"}},
+			},
+		})
+		return json.Unmarshal(b, dst)
+	}
+
+	if strings.HasPrefix(path, "/v2/provider-docs?") {
+		u, err := url.Parse(path)
+		if err != nil {
+			return err
+		}
+		q := u.Query()
+		data := []map[string]any{}
+		if q.Get("page[number]") == "1" {
+			for _, doc := range f.docs {
+				if doc.Category != q.Get("filter[category]") {
+					continue
+				}
+				attrs := map[string]any{"category": doc.Category, "slug": doc.Slug, "title": doc.Title}
+				for k, v := range doc.Extra {
+					attrs[k] = v
+				}
+				data = append(data, map[string]any{"id": doc.ID, "attributes": attrs})
+			}
+		}
+		b, _ := json.Marshal(map[string]any{"data": data})
+		return json.Unmarshal(b, dst)
+	}
+
+	return fmt.Errorf("unexpected GetJSON path: %s", path)
+}
+
+func (f *fakeCatalogClient) Get(_ context.Context, path string) ([]byte, error) {
+	f.record(path)
+	id := strings.TrimPrefix(path, "/v2/provider-docs/")
+	for _, doc := range f.docs {
+		if doc.ID != id {
+			continue
+		}
+		attrs := map[string]any{"category": doc.Category, "slug": doc.Slug, "title": doc.Title, "content": doc.Content}
+		for k, v := range doc.Extra {
+			attrs[k] = v
+		}
+		return json.Marshal(map[string]any{"data": map[string]any{"id": doc.ID, "attributes": attrs}})
+	}
+	return nil, fmt.Errorf("unexpected Get path: %s", path)
+}
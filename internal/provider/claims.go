@@ -0,0 +1,159 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// docSet records the doc IDs already queued for export, so a doc listed on
+// several pages is fetched once. It is safe for concurrent use.
+type docSet struct {
+	mu  sync.Mutex
+	ids map[string]struct{}
+}
+
+func newDocSet() *docSet {
+	return &docSet{ids: make(map[string]struct{})}
+}
+
+// add records id and reports whether it was not already present.
+func (s *docSet) add(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.ids[id]; exists {
+		return false
+	}
+	s.ids[id] = struct{}{}
+	return true
+}
+
+// pathClaim is one doc's claim on an output path. seq is the doc's position
+// in listing order; it decides which doc owns a contested path, however the
+// fetches that made the claims were scheduled.
+type pathClaim struct {
+	seq      int
+	docID    string
+	category string
+	slug     string
+}
+
+func (c pathClaim) slugKey() string {
+	return sanitizeSegment(c.category) + "/" + sanitizeSegment(c.slug)
+}
+
+// pathClaims collects the output path of every planned doc. Claims never
+// fail as they are made; collision reports the lexicographically first
+// contested path once planning is done, so the error is the same whatever
+// order concurrent fetches finish in. It is safe for concurrent use.
+type pathClaims struct {
+	mu sync.Mutex
+	// reserved maps paths written by the export itself, such as the
+	// manifest, to how collision messages name them.
+	reserved map[string]string
+	claims   map[string][]pathClaim
+}
+
+func newPathClaims() *pathClaims {
+	return &pathClaims{reserved: make(map[string]string), claims: make(map[string][]pathClaim)}
+}
+
+// reserve marks path as taken by the export itself; what names it in
+// collision messages, e.g. "the sitemap".
+func (p *pathClaims) reserve(path, what string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.reserved[path] = what
+}
+
+func (p *pathClaims) claim(path string, c pathClaim) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.claims[path] = append(p.claims[path], c)
+}
+
+// collision returns a ValidationError for the lexicographically first path
+// claimed by more than one doc, or by a doc and the export itself, and nil
+// when every path has a single owner.
+func (p *pathClaims) collision() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var contested []string
+	for path, claims := range p.claims {
+		if _, reserved := p.reserved[path]; reserved || len(claims) > 1 {
+			contested = append(contested, path)
+		}
+	}
+	if len(contested) == 0 {
+		return nil
+	}
+	sort.Strings(contested)
+	path := contested[0]
+	if what, reserved := p.reserved[path]; reserved {
+		return &ValidationError{Message: fmt.Sprintf("path collision detected in -path-template: %s conflicts with %s", path, what)}
+	}
+
+	claims := append([]pathClaim(nil), p.claims[path]...)
+	sort.Slice(claims, func(i, j int) bool { return claims[i].seq < claims[j].seq })
+	owner, other := claims[0], claims[1]
+	if other.slugKey() == owner.slugKey() && p.firstWithSlugKey(owner.slugKey()) == owner.seq {
+		return &ValidationError{Message: fmt.Sprintf("duplicate slug %q in category %q: doc_id=%s and doc_id=%s both map to %s; add {doc_id} to -path-template to keep both", sanitizeSegment(other.slug), sanitizeSegment(other.category), owner.docID, other.docID, path)}
+	}
+	return &ValidationError{Message: fmt.Sprintf("path collision detected in -path-template: %s (doc_id=%s conflicts with doc_id=%s)", path, owner.docID, other.docID)}
+}
+
+// firstWithSlugKey returns the lowest seq of any claim with key, so a
+// collision caused by registry slug reuse gets a targeted message. The
+// caller holds p.mu.
+func (p *pathClaims) firstWithSlugKey(key string) int {
+	first := -1
+	for _, claims := range p.claims {
+		for _, c := range claims {
+			if c.slugKey() == key && (first < 0 || c.seq < first) {
+				first = c.seq
+			}
+		}
+	}
+	return first
+}
+
+// docPlan is what planning one doc produced: a file to write, a doc refused
+// by MaxFileSize, or neither when the doc was filtered out.
+type docPlan struct {
+	file    *plannedFile
+	refused error
+}
+
+// docPlans collects planned docs by seq, so they are read back in listing
+// order however the fetches were scheduled. It is safe for concurrent use.
+type docPlans struct {
+	mu    sync.Mutex
+	bySeq map[int]docPlan
+}
+
+func newDocPlans() *docPlans {
+	return &docPlans{bySeq: make(map[int]docPlan)}
+}
+
+func (d *docPlans) set(seq int, p docPlan) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.bySeq[seq] = p
+}
+
+// inOrder returns the recorded plans sorted by seq.
+func (d *docPlans) inOrder() []docPlan {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	seqs := make([]int, 0, len(d.bySeq))
+	for seq := range d.bySeq {
+		seqs = append(seqs, seq)
+	}
+	sort.Ints(seqs)
+	plans := make([]docPlan, len(seqs))
+	for i, seq := range seqs {
+		plans[i] = d.bySeq[seq]
+	}
+	return plans
+}
@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDocSet_ConcurrentAddDedups(t *testing.T) {
+	set := newDocSet()
+	var added atomic.Int64
+	var wg sync.WaitGroup
+	for g := 0; g < 32; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				if set.add(fmt.Sprintf("doc-%d", i)) {
+					added.Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if got := added.Load(); got != 500 {
+		t.Fatalf("expected each of 500 IDs to be added once, got %d additions", got)
+	}
+}
+
+// claimConcurrently claims every entry of claims from its own goroutine, in
+// a shuffled order, and returns the resulting collision error.
+func claimConcurrently(seed int64, reserved string, claims map[string][]pathClaim) error {
+	type job struct {
+		path  string
+		claim pathClaim
+	}
+	var jobs []job
+	for path, cs := range claims {
+		for _, c := range cs {
+			jobs = append(jobs, job{path, c})
+		}
+	}
+	r := rand.New(rand.NewSource(seed))
+	r.Shuffle(len(jobs), func(i, j int) { jobs[i], jobs[j] = jobs[j], jobs[i] })
+
+	p := newPathClaims()
+	if reserved != "" {
+		p.reserve(reserved, "reserved manifest path")
+	}
+	var wg sync.WaitGroup
+	for _, j := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.claim(j.path, j.claim)
+		}()
+	}
+	wg.Wait()
+	return p.collision()
+}
+
+func TestPathClaims_CollisionIsStableUnderConcurrency(t *testing.T) {
+	claims := map[string][]pathClaim{}
+	for i := 0; i < 200; i++ {
+		path := fmt.Sprintf("out/guides/doc-%03d.md", i)
+		claims[path] = []pathClaim{{seq: i, docID: fmt.Sprint(1000 + i), category: "guides", slug: fmt.Sprintf("doc-%03d", i)}}
+	}
+	// Two collisions; "out/guides/doc-050.md" sorts first and is the one
+	// reported, with the earlier-listed doc as its owner.
+	claims["out/guides/doc-120.md"] = append(claims["out/guides/doc-120.md"], pathClaim{seq: 300, docID: "9120", category: "guides", slug: "doc-120"})
+	claims["out/guides/doc-050.md"] = append(claims["out/guides/doc-050.md"], pathClaim{seq: 250, docID: "9050", category: "guides", slug: "doc-050"})
+	want := `duplicate slug "doc-050" in category "guides": doc_id=1050 and doc_id=9050 both map to out/guides/doc-050.md`
+
+	for seed := int64(0); seed < 50; seed++ {
+		err := claimConcurrently(seed, "", claims)
+		if err == nil || !strings.HasPrefix(err.Error(), want) {
+			t.Fatalf("seed %d: expected %q, got %v", seed, want, err)
+		}
+	}
+
+	for seed := int64(0); seed < 20; seed++ {
+		err := claimConcurrently(seed, "out/guides/doc-000.md", claims)
+		if err == nil || err.Error() != "path collision detected in -path-template: out/guides/doc-000.md conflicts with reserved manifest path" {
+			t.Fatalf("seed %d: expected the reserved path collision to sort first, got %v", seed, err)
+		}
+	}
+}
+
+func TestPathClaims_NoCollision(t *testing.T) {
+	p := newPathClaims()
+	p.reserve("out/_manifest.json", "reserved manifest path")
+	p.claim("out/a.md", pathClaim{seq: 0, docID: "1", category: "guides", slug: "a"})
+	p.claim("out/b.md", pathClaim{seq: 1, docID: "2", category: "guides", slug: "b"})
+	if err := p.collision(); err != nil {
+		t.Fatalf("unexpected collision: %v", err)
+	}
+}
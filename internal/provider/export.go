@@ -1,17 +1,24 @@
 package provider
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
+	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/mkusaka/tfdc/internal/guide"
 )
 
 type ValidationError struct {
@@ -34,6 +41,25 @@ type WriteError struct {
 func (e *WriteError) Error() string { return fmt.Sprintf("failed to write file %s: %v", e.Path, e.Err) }
 func (e *WriteError) Unwrap() error { return e.Err }
 
+// PartialError reports a multi-provider export in which some providers were
+// exported and others failed.
+type PartialError struct {
+	Succeeded int
+	Failed    int
+	Errors    []error
+}
+
+func (e *PartialError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "partial export: %d of %d providers failed", e.Failed, e.Succeeded+e.Failed)
+	for _, err := range e.Errors {
+		fmt.Fprintf(&b, "\n  %v", err)
+	}
+	return b.String()
+}
+
+func (e *PartialError) Unwrap() []error { return e.Errors }
+
 type APIClient interface {
 	GetJSON(ctx context.Context, path string, dst any) error
 	Get(ctx context.Context, path string) ([]byte, error)
@@ -42,13 +68,125 @@ type APIClient interface {
 type ExportOptions struct {
 	Namespace    string
 	Name         string
-	Version      string
+	Version      string // empty or "latest" exports the newest published version
 	Format       string
 	OutDir       string
 	Categories   []string
 	PathTemplate string
 	Clean        bool
-	OnProgress   func(string)
+	LineEndings  string // lf (default), crlf, or native; applied to markdown only
+	Prefix       string // extra path segments nested between {out} and the template
+	// HardlinkIdentical hardlinks byte-identical files to the first copy
+	// written. LinkIndex may be shared across calls to dedupe between versions.
+	HardlinkIdentical bool
+	LinkIndex         *LinkIndex
+	ExcludeDeprecated bool
+	// RetryOnEmpty re-lists a category up to this many times, with backoff,
+	// when its first page comes back empty.
+	RetryOnEmpty int
+	// DetailRetries retries a failed doc detail fetch up to this many
+	// times, with backoff, on top of the client's own retries.
+	DetailRetries int
+	// Concurrency is how many doc details are fetched at once; 0 means
+	// DefaultExportConcurrency. Files are still written, and the manifest
+	// ordered, as if they were fetched one by one.
+	Concurrency int
+	// OnProgress receives progress messages. Calls are serialized even
+	// when docs are fetched concurrently.
+	OnProgress func(string)
+	// JSONStyle controls JSON doc rendering: pretty (default) re-indents with
+	// sorted keys, raw keeps the API bytes, canonical is sorted and compact.
+	JSONStyle string
+	// PageSize sets page[size] on docs listing requests; 0 keeps the
+	// registry default.
+	PageSize int
+	// DocIDs, when non-empty, exports exactly these provider doc IDs instead
+	// of listing Categories. Category and slug come from each doc's detail.
+	DocIDs []string
+	// OnFileWritten, when set, is called with the slash-separated path
+	// (relative to OutDir) of every doc and manifest file written.
+	OnFileWritten func(relPath string)
+	// SinceModified records each doc's Last-Modified in the manifest and, on
+	// re-export, sends it as If-Modified-Since so unchanged docs are kept
+	// as-is instead of being rewritten. Requires a ConditionalGetter client.
+	SinceModified bool
+	// AllowSlugs, when non-empty, limits the export to docs with these exact
+	// slugs. DenySlugs are never exported, even when allowlisted.
+	AllowSlugs []string
+	DenySlugs  []string
+	// TrimTrailingWhitespace strips trailing spaces and tabs from every
+	// markdown line and ends the file with exactly one newline.
+	TrimTrailingWhitespace bool
+	// StripBOM removes a leading UTF-8 byte order mark from markdown docs.
+	StripBOM bool
+	// MaxFileSize, when positive, refuses to write any doc whose rendered
+	// content is larger, in bytes. Refused docs are skipped and the rest
+	// still exported; ExportDocs then returns its summary together with a
+	// WriteError per refused doc.
+	MaxFileSize int64
+	// AllowSymlinkRoot permits OutDir itself to be a symlink. It is resolved
+	// once up front; symlinks above or below the resolved directory are
+	// still rejected.
+	AllowSymlinkRoot bool
+	// NewOnlyManifest is the path to a previous export's manifest, usually
+	// of an older version. When set, only docs it does not list (by
+	// category/slug or doc ID) are exported, and their category/slug pairs
+	// are reported in ExportSummary.NewSlugs.
+	NewOnlyManifest string
+	// PreserveMtime sets each written doc's modification time to the
+	// registry's Last-Modified for it. Requires a ConditionalGetter client;
+	// docs without a parseable Last-Modified keep the write time, and docs
+	// hardlinked by HardlinkIdentical keep the first copy's time.
+	PreserveMtime bool
+	// Plan fetches and renders docs as usual but, instead of writing, sets
+	// ExportSummary.Plan to the diff against the files currently on disk.
+	Plan bool
+	// IncludeOverviewReadme also writes the provider's overview doc (or,
+	// failing that, its registry description) as README.md at the
+	// provider/version root. The README is not listed in the manifest.
+	IncludeOverviewReadme bool
+	// PreserveCase keeps the case of Namespace and Name in registry
+	// requests instead of lowercasing them. Output path segments are still
+	// sanitized (and lowercased) independently.
+	PreserveCase bool
+	// ErrorOnEmptyContent fails the export when a doc's content is empty or
+	// whitespace-only: the rendered markdown, or the API content field for
+	// JSON, whose rendered document is never empty.
+	ErrorOnEmptyContent bool
+	// HashAlgorithm selects the per-doc manifest digest: sha256 (default)
+	// or sha512.
+	HashAlgorithm string
+	// SitemapBase, when set, also writes sitemap.xml next to the manifest
+	// with one URL per exported doc: the base joined with the doc's path
+	// relative to OutDir.
+	SitemapBase string
+	// FilenamePrefix and FilenameSuffix are added around the sanitized slug
+	// in {slug}, before the extension: prefix "tf-" writes
+	// tf-aws_s3_bucket.md. They are sanitized like path segments.
+	FilenamePrefix string
+	FilenameSuffix string
+	// NoEmptyManifest skips writing the manifest when no docs were
+	// exported; ExportSummary.Manifest is then empty. By default the
+	// manifest is always written, with an empty docs list if need be.
+	NoEmptyManifest bool
+	// Incremental leaves a doc's file untouched, mtime included, when the
+	// previous manifest records the same doc_id, path and digest and the
+	// file on disk still holds that content. Such docs are counted in
+	// ExportSummary.Skipped instead of Written.
+	Incremental bool
+	// CompactManifest writes the manifest as single-line JSON instead of
+	// indented, to keep it small for large providers.
+	CompactManifest bool
+	// SkipForbidden skips a category whose listing the registry answers
+	// with 403 Forbidden, as some Terraform Enterprise setups do, instead
+	// of failing the export. Skipped categories are passed to OnWarning and
+	// reported in ExportSummary.SkippedCategories.
+	SkipForbidden bool
+	// OnWarning, when set, receives messages about problems the export
+	// worked around.
+	OnWarning func(string)
+
+	now func() time.Time // overridden in tests for a fixed generated_at
 }
 
 type ExportSummary struct {
@@ -56,7 +194,22 @@ type ExportSummary struct {
 	Version  string `json:"version"`
 	OutDir   string `json:"out_dir"`
 	Written  int    `json:"written"`
-	Manifest string `json:"manifest"`
+	// Unchanged counts docs kept from a previous export because the
+	// registry answered 304 Not Modified (SinceModified only).
+	Unchanged int `json:"unchanged,omitempty"`
+	// Skipped counts docs not rewritten because their content matched the
+	// previous export (Incremental only).
+	Skipped  int    `json:"skipped,omitempty"`
+	Manifest string `json:"manifest"` // empty when NoEmptyManifest skipped it
+	// Plan is set instead of writing files when exporting with Plan.
+	Plan *ExportPlan `json:"plan,omitempty"`
+	// DurationMS is the wall-clock time ExportDocs took, in milliseconds.
+	DurationMS int64 `json:"duration_ms,omitempty"`
+	// NewSlugs lists the category/slug of every exported doc when
+	// exporting with NewOnlyManifest, sorted.
+	NewSlugs []string `json:"new_slugs,omitempty"`
+	// SkippedCategories lists the categories skipped by SkipForbidden.
+	SkippedCategories []string `json:"skipped_categories,omitempty"`
 }
 
 type providerVersionsResponse struct {
@@ -70,15 +223,19 @@ type providerVersionsResponse struct {
 }
 
 type providerDocsListResponse struct {
-	Data []struct {
-		ID         string `json:"id"`
-		Type       string `json:"type"`
-		Attributes struct {
-			Category string `json:"category"`
-			Slug     string `json:"slug"`
-			Title    string `json:"title"`
-		} `json:"attributes"`
-	} `json:"data"`
+	Data []providerDocListItem `json:"data"`
+}
+
+type providerDocListItem struct {
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	Attributes struct {
+		Category   string `json:"category"`
+		Slug       string `json:"slug"`
+		Title      string `json:"title"`
+		Deprecated bool   `json:"deprecated"`
+	} `json:"attributes"`
+	Links docLinks `json:"links"`
 }
 
 type providerDocDetailResponse struct {
@@ -86,23 +243,27 @@ type providerDocDetailResponse struct {
 		ID         string `json:"id"`
 		Type       string `json:"type"`
 		Attributes struct {
-			Category string `json:"category"`
-			Path     string `json:"path"`
-			Slug     string `json:"slug"`
-			Title    string `json:"title"`
-			Content  string `json:"content"`
+			Category   string `json:"category"`
+			Path       string `json:"path"`
+			Slug       string `json:"slug"`
+			Title      string `json:"title"`
+			Content    string `json:"content"`
+			Deprecated bool   `json:"deprecated"`
 		} `json:"attributes"`
 	} `json:"data"`
 }
 
 type manifest struct {
-	Provider    string         `json:"provider"`
-	Namespace   string         `json:"namespace"`
-	Version     string         `json:"version"`
-	Format      string         `json:"format"`
-	GeneratedAt string         `json:"generated_at"`
-	Total       int            `json:"total"`
-	Docs        []manifestItem `json:"docs"`
+	Provider    string `json:"provider"`
+	Namespace   string `json:"namespace"`
+	Version     string `json:"version"`
+	Format      string `json:"format"`
+	GeneratedAt string `json:"generated_at"`
+	// HashAlgorithm names the digest recorded per doc: sha256 or sha512.
+	// Manifests written before it existed use sha256.
+	HashAlgorithm string         `json:"hash_algorithm,omitempty"`
+	Total         int            `json:"total"`
+	Docs          []manifestItem `json:"docs"`
 }
 
 type manifestItem struct {
@@ -111,15 +272,30 @@ type manifestItem struct {
 	Slug     string `json:"slug"`
 	Title    string `json:"title"`
 	Path     string `json:"path"`
+	// SHA256 or SHA512 is the hex digest of the file written to Path,
+	// depending on the manifest's hash_algorithm.
+	SHA256 string `json:"sha256,omitempty"`
+	SHA512 string `json:"sha512,omitempty"`
+	// LastModified is the doc response's Last-Modified header, recorded
+	// when exporting with SinceModified.
+	LastModified string `json:"last_modified,omitempty"`
 }
 
 type plannedFile struct {
 	path    string
 	content []byte
 	item    manifestItem
+	// unlisted files are written but not recorded in the manifest.
+	unlisted bool
+	// unchanged marks a doc the registry reported as not modified; its
+	// existing file is kept and only its manifest entry is rewritten.
+	unchanged bool
+	// lastModified is the doc response's Last-Modified header, when fetched.
+	lastModified string
 }
 
-const reservedManifestPathOwner = "_manifest"
+// MaxPageSize is the largest page[size] accepted for docs listing requests.
+const MaxPageSize = 100
 
 var defaultCategories = []string{
 	"resources",
@@ -132,124 +308,397 @@ var defaultCategories = []string{
 	"list-resources",
 }
 
+// categoryAliases maps common variants of category names, with separators
+// already folded to "-", to their canonical form.
+var categoryAliases = map[string]string{
+	"resource":           "resources",
+	"data-source":        "data-sources",
+	"datasource":         "data-sources",
+	"datasources":        "data-sources",
+	"ephemeral-resource": "ephemeral-resources",
+	"ephemeralresource":  "ephemeral-resources",
+	"ephemeralresources": "ephemeral-resources",
+	"function":           "functions",
+	"guide":              "guides",
+	"action":             "actions",
+	"list-resource":      "list-resources",
+	"listresource":       "list-resources",
+	"listresources":      "list-resources",
+}
+
+// canonicalCategory lowercases cat, folds "_" and spaces to "-" and
+// resolves categoryAliases, so "Data Sources" and "datasource" both become
+// data-sources. Unknown names are returned folded but otherwise unchanged.
+func canonicalCategory(cat string) string {
+	cat = strings.Join(strings.FieldsFunc(strings.ToLower(cat), func(r rune) bool {
+		return r == '-' || r == '_' || r == ' '
+	}), "-")
+	if canonical, ok := categoryAliases[cat]; ok {
+		return canonical
+	}
+	return cat
+}
+
 func ExportDocs(ctx context.Context, client APIClient, opts ExportOptions) (*ExportSummary, error) {
-	progress := opts.OnProgress
-	if progress == nil {
-		progress = func(string) {}
+	started := time.Now()
+	progress := func(string) {}
+	if opts.OnProgress != nil {
+		var progressMu sync.Mutex
+		progress = func(msg string) {
+			progressMu.Lock()
+			defer progressMu.Unlock()
+			opts.OnProgress(msg)
+		}
+	}
+
+	warn := opts.OnWarning
+	if warn == nil {
+		warn = func(string) {}
 	}
 
 	ext, err := prepareExportOptions(&opts)
 	if err != nil {
 		return nil, err
 	}
+	if opts.Version == "latest" {
+		progress(fmt.Sprintf("Resolving latest version of %s/%s", opts.Namespace, opts.Name))
+		if opts.Version, err = resolveExportVersion(ctx, client, opts.Namespace, opts.Name); err != nil {
+			return nil, err
+		}
+		if err := validatePathTemplate(opts, ext); err != nil {
+			return nil, err
+		}
+	}
 
-	progress(fmt.Sprintf("Resolving %s/%s@%s", opts.Namespace, opts.Name, opts.Version))
-	providerVersionID, err := resolveProviderVersionID(ctx, client, opts.Namespace, opts.Name, opts.Version)
-	if err != nil {
-		return nil, err
+	if opts.HardlinkIdentical && opts.LinkIndex == nil {
+		opts.LinkIndex = NewLinkIndex()
 	}
 
-	seen := make(map[string]struct{})
-	planned := make([]plannedFile, 0)
-	pathOwners := make(map[string]string)
-	pathOwners[manifestPathForOptions(opts)] = reservedManifestPathOwner
+	slugAllowed := newSlugFilter(opts.AllowSlugs, opts.DenySlugs)
+	isNew := func(category, slug, docID string) bool { return true }
+	if opts.NewOnlyManifest != "" {
+		old, err := readManifestFile(opts.NewOnlyManifest)
+		if err != nil {
+			return nil, err
+		}
+		isNew = newDocFilter(old.Docs)
+	}
 
-	docCount := 0
-	for _, category := range opts.Categories {
-		for page := 1; ; page++ {
-			progress(fmt.Sprintf("Listing %s (page %d)", category, page))
-			docs, err := listProviderDocs(ctx, client, providerVersionID, category, page)
+	var previous map[string]manifestItem
+	if opts.SinceModified || opts.Incremental {
+		previous, err = readPreviousManifestDocs(opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	seen := newDocSet()
+	plans := newDocPlans()
+	claims := newPathClaims()
+	claims.reserve(manifestPathForOptions(opts), "reserved manifest path")
+	if opts.IncludeOverviewReadme {
+		claims.reserve(readmePathForOptions(opts), "the overview README")
+	}
+	if opts.SitemapBase != "" {
+		claims.reserve(sitemapPathForOptions(opts), "the sitemap")
+	}
+
+	// docPath builds the output path for a doc. listCategory is the
+	// fallback for details that omit their category.
+	docPath := func(category, listCategory, slug, docID string) (string, error) {
+		vars := map[string]string{
+			"out":       opts.OutDir,
+			"namespace": sanitizeSegment(opts.Namespace),
+			"provider":  sanitizeSegment(opts.Name),
+			"version":   sanitizeSegment(opts.Version),
+			"category":  sanitizeSegment(category),
+			"slug":      slugFilename(opts, slug),
+			"doc_id":    sanitizeSegment(docID),
+			"ext":       ext,
+		}
+		if vars["category"] == "unknown" && listCategory != "" {
+			vars["category"] = sanitizeSegment(listCategory)
+		}
+		filePath, err := BuildOutputPath(pathTemplateForOptions(opts), vars, opts.OutDir)
+		if err != nil {
+			return "", &ValidationError{Message: err.Error()}
+		}
+		return filePath, nil
+	}
+
+	// unchangedSince returns the Last-Modified value to send for docID: the
+	// one recorded in the previous manifest, provided that export's file is
+	// still on disk where the current template would put it.
+	unchangedSince := func(docID, listCategory string) (manifestItem, string) {
+		prev, ok := previous[docID]
+		// A digest under another algorithm cannot be carried over.
+		if !ok || prev.LastModified == "" || prev.digest(opts.HashAlgorithm) == "" || !filepath.IsLocal(filepath.FromSlash(prev.Path)) {
+			return prev, ""
+		}
+		filePath, err := docPath(prev.Category, listCategory, prev.Slug, docID)
+		if err != nil || filePath != filepath.Join(opts.OutDir, filepath.FromSlash(prev.Path)) {
+			return prev, ""
+		}
+		if info, err := os.Lstat(filePath); err != nil || !info.Mode().IsRegular() {
+			return prev, ""
+		}
+		return prev, prev.LastModified
+	}
+
+	// planDoc fetches one doc and records where it will be written. The
+	// listing category and slug are fallbacks for details that omit them;
+	// seq is the doc's position in listing order, for claims and plans.
+	// It runs on the fetch pool, so everything it shares is safe for
+	// concurrent use.
+	planDoc := func(ctx context.Context, seq int, docID, listCategory, listSlug string) error {
+		var detail providerDocDetailResponse
+		var raw []byte
+		var lastModified string
+		if conditional, ok := client.(ConditionalGetter); ok && (opts.SinceModified || opts.PreserveMtime) {
+			var prev manifestItem
+			var since string
+			if opts.SinceModified {
+				prev, since = unchangedSince(docID, listCategory)
+			}
+			var notModified bool
+			err := retryDetail(ctx, opts.DetailRetries, docID, progress, func() error {
+				var fetchErr error
+				detail, raw, lastModified, notModified, fetchErr = getProviderDocDetailIfModified(ctx, conditional, docID, since)
+				return fetchErr
+			})
 			if err != nil {
-				return nil, err
+				return err
 			}
-			if len(docs) == 0 {
-				break
+			if notModified {
+				if !slugAllowed(prev.Slug) || !isNew(prev.Category, prev.Slug, docID) {
+					return nil
+				}
+				filePath := filepath.Join(opts.OutDir, filepath.FromSlash(prev.Path))
+				claims.claim(filePath, pathClaim{seq: seq, docID: docID, category: prev.Category, slug: prev.Slug})
+				plans.set(seq, docPlan{file: &plannedFile{path: filePath, item: prev, unchanged: true}})
+				return nil
 			}
-			newDocsOnPage := 0
+		} else {
+			err := retryDetail(ctx, opts.DetailRetries, docID, progress, func() error {
+				var fetchErr error
+				detail, raw, fetchErr = getProviderDocDetail(ctx, client, docID, opts.Format == "json")
+				return fetchErr
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if opts.ExcludeDeprecated && detail.Data.Attributes.Deprecated {
+			return nil
+		}
 
-			for _, doc := range docs {
-				if _, exists := seen[doc.ID]; exists {
-					continue
-				}
-				seen[doc.ID] = struct{}{}
-				newDocsOnPage++
-				docCount++
+		slug := detail.Data.Attributes.Slug
+		if slug == "" {
+			slug = listSlug
+		}
+		if slug == "" {
+			slug = detail.Data.ID
+		}
+		if !slugAllowed(slug) {
+			return nil
+		}
 
-				progress(fmt.Sprintf("Fetching %s/%s (%d docs)", category, doc.Attributes.Slug, docCount))
-				detail, raw, err := getProviderDocDetail(ctx, client, doc.ID, opts.Format == "json")
-				if err != nil {
-					return nil, err
-				}
+		filePath, err := docPath(detail.Data.Attributes.Category, listCategory, slug, detail.Data.ID)
+		if err != nil {
+			return err
+		}
+		category := detail.Data.Attributes.Category
+		if sanitizeSegment(category) == "unknown" && listCategory != "" {
+			category = listCategory
+		}
+		if !isNew(category, slug, detail.Data.ID) {
+			return nil
+		}
+		claims.claim(filePath, pathClaim{seq: seq, docID: detail.Data.ID, category: category, slug: slug})
 
-				slug := detail.Data.Attributes.Slug
-				if slug == "" {
-					slug = doc.Attributes.Slug
-				}
-				if slug == "" {
-					slug = detail.Data.ID
-				}
+		content, err := renderContent(opts, detail, raw)
+		if err != nil {
+			return err
+		}
+		if opts.ErrorOnEmptyContent && hasEmptyContent(opts.Format, detail, content) {
+			return &WriteError{Path: filePath, Err: fmt.Errorf("doc %s (%s/%s) has empty content", detail.Data.ID, category, slug)}
+		}
+		if opts.MaxFileSize > 0 && int64(len(content)) > opts.MaxFileSize {
+			plans.set(seq, docPlan{refused: &WriteError{Path: filePath, Err: fmt.Errorf("doc %s is %d bytes, over -max-file-size of %d bytes", detail.Data.ID, len(content), opts.MaxFileSize)}})
+			return nil
+		}
 
-				vars := map[string]string{
-					"out":       opts.OutDir,
-					"namespace": sanitizeSegment(opts.Namespace),
-					"provider":  sanitizeSegment(opts.Name),
-					"version":   sanitizeSegment(opts.Version),
-					"category":  sanitizeSegment(detail.Data.Attributes.Category),
-					"slug":      sanitizeSegment(slug),
-					"doc_id":    sanitizeSegment(detail.Data.ID),
-					"ext":       ext,
-				}
-				if vars["category"] == "unknown" {
-					vars["category"] = sanitizeSegment(category)
-				}
+		relPath, err := filepath.Rel(opts.OutDir, filePath)
+		if err != nil {
+			relPath = filePath
+		}
 
-				filePath, err := BuildOutputPath(opts.PathTemplate, vars, opts.OutDir)
-				if err != nil {
-					return nil, &ValidationError{Message: err.Error()}
+		item := manifestItem{
+			DocID:    detail.Data.ID,
+			Category: detail.Data.Attributes.Category,
+			Slug:     slug,
+			Title:    detail.Data.Attributes.Title,
+			Path:     filepath.ToSlash(relPath),
+		}
+		if opts.SinceModified {
+			item.LastModified = lastModified
+		}
+		item.setDigest(opts.HashAlgorithm, content)
+		plans.set(seq, docPlan{file: &plannedFile{path: filePath, content: content, item: item, lastModified: lastModified}})
+		return nil
+	}
+
+	// skipForbidden reports whether a listing error for category is a 403
+	// that SkipForbidden lets the export move past.
+	var skippedCategories []string
+	skipForbidden := func(category string, err error) bool {
+		if !opts.SkipForbidden || !isForbidden(err) {
+			return false
+		}
+		warn(fmt.Sprintf("skipping category %s: listing is forbidden (403)", category))
+		skippedCategories = append(skippedCategories, category)
+		return true
+	}
+
+	// Listing stays sequential; doc details are fetched on the pool.
+	pool, poolCtx := newFetchPool(ctx, opts.Concurrency)
+	defer pool.stop()
+	submitDoc := func(seq int, docID, listCategory, listSlug string) error {
+		return pool.submit(func() error {
+			return planDoc(poolCtx, seq, docID, listCategory, listSlug)
+		})
+	}
+
+	var planned []plannedFile
+	docCount := 0
+	if len(opts.DocIDs) > 0 {
+		// Explicit doc IDs skip version resolution and listing entirely.
+		for _, docID := range opts.DocIDs {
+			docCount++
+			progress(fmt.Sprintf("Fetching doc %s (%d/%d docs)", docID, docCount, len(opts.DocIDs)))
+			if err := submitDoc(docCount, docID, "", ""); err != nil {
+				return nil, err
+			}
+		}
+		if err := pool.wait(); err != nil {
+			return nil, err
+		}
+	} else {
+		progress(fmt.Sprintf("Resolving %s/%s@%s", opts.Namespace, opts.Name, opts.Version))
+		providerVersionID, err := resolveProviderVersionID(ctx, client, opts.Namespace, opts.Name, opts.Version)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, category := range opts.Categories {
+			for page := 1; ; page++ {
+				progress(fmt.Sprintf("Listing %s (page %d)", category, page))
+				docs, err := listProviderDocs(poolCtx, client, providerVersionID, category, page, opts.PageSize)
+				if err == nil && len(docs) == 0 && page == 1 && opts.RetryOnEmpty > 0 {
+					docs, err = retryEmptyListing(poolCtx, client, providerVersionID, category, opts.PageSize, opts.RetryOnEmpty, progress)
 				}
-				if existing, exists := pathOwners[filePath]; exists {
-					if existing == reservedManifestPathOwner {
-						return nil, &ValidationError{Message: fmt.Sprintf("path collision detected in -path-template: %s conflicts with reserved manifest path", filePath)}
+				if err != nil {
+					if skipForbidden(category, err) {
+						break
 					}
-					return nil, &ValidationError{Message: fmt.Sprintf("path collision detected in -path-template: %s (doc_id=%s conflicts with doc_id=%s)", filePath, existing, detail.Data.ID)}
+					return nil, pool.failure(err)
 				}
-				pathOwners[filePath] = detail.Data.ID
-
-				content, err := renderContent(opts.Format, detail, raw)
-				if err != nil {
-					return nil, err
+				if len(docs) == 0 {
+					break
 				}
+				newDocsOnPage := 0
 
-				relPath, err := filepath.Rel(opts.OutDir, filePath)
-				if err != nil {
-					relPath = filePath
+				for _, doc := range docs {
+					if !seen.add(doc.ID) {
+						continue
+					}
+					newDocsOnPage++
+					if opts.ExcludeDeprecated && doc.Attributes.Deprecated {
+						continue
+					}
+					// Skip the detail fetch when the listing slug already
+					// rules the doc out.
+					if doc.Attributes.Slug != "" && !slugAllowed(doc.Attributes.Slug) {
+						continue
+					}
+					if doc.Attributes.Slug != "" && !isNew(category, doc.Attributes.Slug, doc.ID) {
+						continue
+					}
+					docCount++
+
+					progress(fmt.Sprintf("Fetching %s/%s (%d docs)", category, doc.Attributes.Slug, docCount))
+					if err := submitDoc(docCount, doc.ID, category, doc.Attributes.Slug); err != nil {
+						return nil, err
+					}
 				}
 
-				planned = append(planned, plannedFile{
-					path:    filePath,
-					content: content,
-					item: manifestItem{
-						DocID:    detail.Data.ID,
-						Category: detail.Data.Attributes.Category,
-						Slug:     slug,
-						Title:    detail.Data.Attributes.Title,
-						Path:     filepath.ToSlash(relPath),
-					},
-				})
+				// Stop paging when the endpoint keeps returning already-seen docs.
+				// This avoids infinite loops against non-compliant pagers/proxies.
+				if newDocsOnPage == 0 && page > 1 {
+					break
+				}
 			}
+		}
 
-			// Stop paging when the endpoint keeps returning already-seen docs.
-			// This avoids infinite loops against non-compliant pagers/proxies.
-			if newDocsOnPage == 0 && page > 1 {
-				break
+		if err := pool.wait(); err != nil {
+			return nil, err
+		}
+
+		if opts.IncludeOverviewReadme {
+			progress("Fetching overview README")
+			readme, err := fetchOverviewReadme(ctx, client, opts, providerVersionID)
+			if err != nil {
+				return nil, err
 			}
+			if readme != nil {
+				readmePath := readmePathForOptions(opts)
+				item := manifestItem{Path: planRelPath(opts, readmePath)}
+				item.setDigest(opts.HashAlgorithm, readme)
+				planned = append(planned, plannedFile{path: readmePath, content: readme, item: item, unlisted: true})
+			}
+		}
+	}
+
+	if err := claims.collision(); err != nil {
+		return nil, err
+	}
+
+	var refused []error
+	for _, p := range plans.inOrder() {
+		if p.file != nil {
+			planned = append(planned, *p.file)
+		}
+		if p.refused != nil {
+			refused = append(refused, p.refused)
 		}
 	}
 
+	// Order by path, then doc ID, so the manifest is byte-identical across
+	// runs regardless of the order the registry lists docs in.
 	sort.Slice(planned, func(i, j int) bool {
-		return planned[i].item.Path < planned[j].item.Path
+		if planned[i].item.Path != planned[j].item.Path {
+			return planned[i].item.Path < planned[j].item.Path
+		}
+		return planned[i].item.DocID < planned[j].item.DocID
 	})
 
+	if opts.Plan {
+		plan, err := buildExportPlan(opts, ext, planned)
+		if err != nil {
+			return nil, err
+		}
+		return &ExportSummary{
+			Provider:          sanitizeSegment(opts.Name),
+			Version:           opts.Version,
+			OutDir:            opts.OutDir,
+			Manifest:          filepath.ToSlash(manifestPathForOptions(opts)),
+			Plan:              plan,
+			DurationMS:        time.Since(started).Milliseconds(),
+			NewSlugs:          newSlugsForOptions(opts, planned),
+			SkippedCategories: skippedCategories,
+		}, errors.Join(refused...)
+	}
+
 	if opts.Clean {
 		cleanTargets, err := deriveCleanTargets(opts, ext)
 		if err != nil {
@@ -266,36 +715,85 @@ func ExportDocs(ctx context.Context, client APIClient, opts ExportOptions) (*Exp
 	}
 
 	manifestDocs := make([]manifestItem, 0, len(planned))
+	written, unchanged, skipped := 0, 0, 0
 	for _, pf := range planned {
+		if pf.unchanged {
+			unchanged++
+			manifestDocs = append(manifestDocs, pf.item)
+			continue
+		}
 		if err := ensureNoSymlinkTraversal(opts.OutDir, pf.path); err != nil {
 			return nil, &ValidationError{Message: fmt.Sprintf("unsafe output path %s: %v", pf.path, err)}
 		}
+		if opts.Incremental && !pf.unlisted && unchangedOnDisk(previous, opts.HashAlgorithm, pf) {
+			skipped++
+			manifestDocs = append(manifestDocs, pf.item)
+			continue
+		}
 		if err := os.MkdirAll(filepath.Dir(pf.path), 0o755); err != nil {
 			return nil, &WriteError{Path: pf.path, Err: err}
 		}
-		if err := os.WriteFile(pf.path, pf.content, 0o644); err != nil {
+		linked := false
+		if opts.HardlinkIdentical {
+			var err error
+			if linked, err = writeLinkedFile(opts.LinkIndex, pf.path, pf.content); err != nil {
+				return nil, &WriteError{Path: pf.path, Err: err}
+			}
+		} else if err := replaceFile(pf.path, pf.content); err != nil {
 			return nil, &WriteError{Path: pf.path, Err: err}
 		}
+		// A hardlinked file shares its times with the earlier copy, so
+		// setting them here would rewrite that copy's mtime too.
+		if opts.PreserveMtime && !linked {
+			if err := setModTime(pf.path, pf.lastModified); err != nil {
+				return nil, &WriteError{Path: pf.path, Err: err}
+			}
+		}
+		fileWritten(opts, pf.path)
+		if pf.unlisted {
+			continue
+		}
+		written++
 		manifestDocs = append(manifestDocs, pf.item)
 	}
 
+	summary := &ExportSummary{
+		Provider:          sanitizeSegment(opts.Name),
+		Version:           opts.Version,
+		OutDir:            opts.OutDir,
+		Written:           written,
+		Unchanged:         unchanged,
+		Skipped:           skipped,
+		NewSlugs:          newSlugsForOptions(opts, planned),
+		SkippedCategories: skippedCategories,
+	}
+	if opts.NoEmptyManifest && len(manifestDocs) == 0 {
+		summary.DurationMS = time.Since(started).Milliseconds()
+		return summary, errors.Join(refused...)
+	}
+
 	manifestPath, err := writeManifest(opts, manifestDocs)
 	if err != nil {
 		return nil, err
 	}
+	fileWritten(opts, manifestPath)
+
+	if opts.SitemapBase != "" {
+		sitemapPath, err := writeSitemap(opts, manifestDocs)
+		if err != nil {
+			return nil, err
+		}
+		fileWritten(opts, sitemapPath)
+	}
 
 	relManifestPath, err := filepath.Rel(opts.OutDir, manifestPath)
 	if err != nil {
 		relManifestPath = manifestPath
 	}
 
-	return &ExportSummary{
-		Provider: sanitizeSegment(opts.Name),
-		Version:  opts.Version,
-		OutDir:   opts.OutDir,
-		Written:  len(planned),
-		Manifest: filepath.ToSlash(filepath.Join(opts.OutDir, relManifestPath)),
-	}, nil
+	summary.Manifest = filepath.ToSlash(filepath.Join(opts.OutDir, relManifestPath))
+	summary.DurationMS = time.Since(started).Milliseconds()
+	return summary, errors.Join(refused...)
 }
 
 func PreflightExportOptions(opts *ExportOptions) error {
@@ -304,12 +802,13 @@ func PreflightExportOptions(opts *ExportOptions) error {
 }
 
 func validateExportOptions(opts *ExportOptions) error {
-	opts.Namespace = strings.ToLower(strings.TrimSpace(opts.Namespace))
-	opts.Name = strings.ToLower(strings.TrimSpace(opts.Name))
+	opts.Namespace = normalizeProviderIdentifier(opts.Namespace, opts.PreserveCase)
+	opts.Name = normalizeProviderIdentifier(opts.Name, opts.PreserveCase)
 	opts.Version = strings.TrimSpace(opts.Version)
 	opts.Format = strings.ToLower(strings.TrimSpace(opts.Format))
 	opts.OutDir = strings.TrimSpace(opts.OutDir)
 	opts.PathTemplate = strings.TrimSpace(opts.PathTemplate)
+	opts.LineEndings = strings.ToLower(strings.TrimSpace(opts.LineEndings))
 
 	if opts.Namespace == "" {
 		opts.Namespace = "hashicorp"
@@ -317,8 +816,12 @@ func validateExportOptions(opts *ExportOptions) error {
 	if opts.Name == "" {
 		return &ValidationError{Message: "-name is required"}
 	}
-	if opts.Version == "" {
-		return &ValidationError{Message: "-version is required"}
+	if err := validateProviderIdentifiers(opts.Namespace, opts.Name, opts.PreserveCase); err != nil {
+		return err
+	}
+	if opts.Version == "" || strings.EqualFold(opts.Version, "latest") {
+		// Resolved by ExportDocs; CheckPathTemplate samples it as is.
+		opts.Version = "latest"
 	}
 	if opts.Format == "" {
 		opts.Format = "markdown"
@@ -326,14 +829,87 @@ func validateExportOptions(opts *ExportOptions) error {
 	if opts.OutDir == "" {
 		return &ValidationError{Message: "-out-dir is required"}
 	}
+	hashAlgo, err := normalizeHashAlgorithm(opts.HashAlgorithm)
+	if err != nil {
+		return err
+	}
+	opts.HashAlgorithm = hashAlgo
+	sitemapBase, err := normalizeSitemapBase(opts.SitemapBase)
+	if err != nil {
+		return err
+	}
+	opts.SitemapBase = sitemapBase
+	if opts.IncludeOverviewReadme && len(opts.DocIDs) > 0 {
+		return &ValidationError{Message: "-include-overview-readme cannot be combined with -doc-ids"}
+	}
+	if opts.SinceModified && opts.Clean {
+		return &ValidationError{Message: "-since-modified cannot be combined with -clean"}
+	}
+	if opts.Incremental && opts.Clean {
+		return &ValidationError{Message: "-incremental cannot be combined with -clean"}
+	}
 	if opts.PathTemplate == "" {
 		opts.PathTemplate = DefaultPathTemplate
 	}
+	prefix, err := normalizePathPrefix(opts.Prefix)
+	if err != nil {
+		return err
+	}
+	opts.Prefix = prefix
+	opts.FilenamePrefix = sanitizeFilenameAffix(opts.FilenamePrefix)
+	opts.FilenameSuffix = sanitizeFilenameAffix(opts.FilenameSuffix)
+	if opts.Prefix != "" && !strings.HasPrefix(opts.PathTemplate, "{out}") && filepath.IsAbs(opts.PathTemplate) {
+		return &ValidationError{Message: "-prefix requires -path-template to start with {out} or be relative"}
+	}
+	if len(opts.DocIDs) > 0 {
+		ids, err := normalizeDocIDs(opts.DocIDs)
+		if err != nil {
+			return err
+		}
+		opts.DocIDs = ids
+	}
+	switch opts.JSONStyle = strings.ToLower(strings.TrimSpace(opts.JSONStyle)); opts.JSONStyle {
+	case "":
+		opts.JSONStyle = "pretty"
+	case "raw", "pretty", "canonical":
+	default:
+		return &ValidationError{Message: fmt.Sprintf("unsupported -json-style: %s (valid: raw, pretty, canonical)", opts.JSONStyle)}
+	}
+	if err := validatePageSize(opts.PageSize); err != nil {
+		return err
+	}
+	if opts.RetryOnEmpty < 0 {
+		return &ValidationError{Message: "-retry-on-empty must be >= 0"}
+	}
+	if opts.DetailRetries < 0 {
+		return &ValidationError{Message: "-detail-retries must be >= 0"}
+	}
+	switch {
+	case opts.Concurrency < 0:
+		return &ValidationError{Message: "-concurrency must be >= 1"}
+	case opts.Concurrency == 0:
+		opts.Concurrency = DefaultExportConcurrency
+	}
+	if opts.MaxFileSize < 0 {
+		return &ValidationError{Message: "-max-file-size must be >= 0"}
+	}
+	switch opts.LineEndings {
+	case "":
+		opts.LineEndings = "lf"
+	case "lf", "crlf", "native":
+	default:
+		return &ValidationError{Message: fmt.Sprintf("unsupported -line-endings: %s (valid: lf, crlf, native)", opts.LineEndings)}
+	}
 
 	outAbs, err := filepath.Abs(opts.OutDir)
 	if err != nil {
 		return &ValidationError{Message: fmt.Sprintf("invalid -out-dir: %v", err)}
 	}
+	if opts.AllowSymlinkRoot {
+		if outAbs, err = resolveSymlinkRoot(outAbs); err != nil {
+			return &ValidationError{Message: fmt.Sprintf("invalid -out-dir: %v", err)}
+		}
+	}
 	opts.OutDir = outAbs
 
 	cats, err := normalizeCategories(opts.Categories)
@@ -348,6 +924,63 @@ func validateExportOptions(opts *ExportOptions) error {
 	return nil
 }
 
+// normalizeDocIDs trims and de-duplicates explicit doc IDs, preserving the
+// given order, and rejects any that are not numeric.
+func normalizeDocIDs(input []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(input))
+	ids := make([]string, 0, len(input))
+	for _, raw := range input {
+		id := bareDocID(strings.TrimSpace(raw))
+		if id == "" {
+			continue
+		}
+		if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+			return nil, &ValidationError{Message: fmt.Sprintf("-doc-ids must be numeric: %s", id)}
+		}
+		if _, exists := seen[id]; exists {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil, &ValidationError{Message: "-doc-ids must list at least one doc ID"}
+	}
+	return ids, nil
+}
+
+// normalizeProviderIdentifier trims a namespace or name and lowercases it
+// unless preserveCase is set.
+func normalizeProviderIdentifier(s string, preserveCase bool) string {
+	s = strings.TrimSpace(s)
+	if preserveCase {
+		return s
+	}
+	return strings.ToLower(s)
+}
+
+// validateProviderIdentifiers rejects namespace/name values the registry
+// would never accept, so users get a clear message instead of an opaque 404.
+func validateProviderIdentifiers(namespace, name string, preserveCase bool) error {
+	if err := validateProviderIdentifier("-namespace", namespace, preserveCase); err != nil {
+		return err
+	}
+	return validateProviderIdentifier("-name", name, preserveCase)
+}
+
+func validateProviderIdentifier(flagName, value string, preserveCase bool) error {
+	if preserveCase {
+		if !reProviderIdentifierAnyCase.MatchString(value) {
+			return &ValidationError{Message: fmt.Sprintf("invalid %s %q: must contain only letters, digits, and hyphens", flagName, value)}
+		}
+		return nil
+	}
+	if !reProviderIdentifier.MatchString(value) {
+		return &ValidationError{Message: fmt.Sprintf("invalid %s %q: must contain only lowercase letters, digits, and hyphens", flagName, value)}
+	}
+	return nil
+}
+
 func normalizeCategories(input []string) ([]string, error) {
 	if len(input) == 0 {
 		return append([]string{}, defaultCategories...), nil
@@ -361,7 +994,7 @@ func normalizeCategories(input []string) ([]string, error) {
 	set := make(map[string]struct{})
 	for _, raw := range input {
 		for _, token := range strings.Split(raw, ",") {
-			cat := strings.ToLower(strings.TrimSpace(token))
+			cat := canonicalCategory(token)
 			if cat == "" {
 				continue
 			}
@@ -369,7 +1002,7 @@ func normalizeCategories(input []string) ([]string, error) {
 				return append([]string{}, defaultCategories...), nil
 			}
 			if _, ok := allowed[cat]; !ok {
-				return nil, &ValidationError{Message: fmt.Sprintf("unsupported category: %s", cat)}
+				return nil, &ValidationError{Message: fmt.Sprintf("unsupported category: %s", strings.TrimSpace(token))}
 			}
 			set[cat] = struct{}{}
 		}
@@ -387,6 +1020,57 @@ func normalizeCategories(input []string) ([]string, error) {
 	return result, nil
 }
 
+// VersionResolution is the outcome of ResolveVersion.
+type VersionResolution struct {
+	Namespace         string
+	Name              string
+	Version           string
+	ProviderVersionID string
+}
+
+// ResolveVersion resolves namespace/name@version to the registry's
+// provider-version ID without listing any docs. An empty version or "latest" is
+// first resolved to the newest published version. preserveCase keeps the
+// namespace and name case as ExportOptions.PreserveCase does.
+func ResolveVersion(ctx context.Context, client APIClient, namespace, name, version string, preserveCase bool) (*VersionResolution, error) {
+	namespace = normalizeProviderIdentifier(namespace, preserveCase)
+	name = normalizeProviderIdentifier(name, preserveCase)
+	version = strings.TrimSpace(version)
+	if namespace == "" {
+		namespace = "hashicorp"
+	}
+	if name == "" {
+		return nil, &ValidationError{Message: "-name is required"}
+	}
+	if err := validateProviderIdentifiers(namespace, name, preserveCase); err != nil {
+		return nil, err
+	}
+	if version == "" || strings.EqualFold(version, "latest") {
+		latest, err := resolveExportVersion(ctx, client, namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		version = latest
+	}
+	id, err := resolveProviderVersionID(ctx, client, namespace, name, version)
+	if err != nil {
+		return nil, err
+	}
+	return &VersionResolution{Namespace: namespace, Name: name, Version: version, ProviderVersionID: id}, nil
+}
+
+// resolveExportVersion resolves the newest published version of a provider
+// exported without an explicit version. A provider the registry does not
+// know is reported as a NotFoundError.
+func resolveExportVersion(ctx context.Context, client APIClient, namespace, name string) (string, error) {
+	version, err := resolveLatestVersion(ctx, client, namespace, name)
+	var statusErr httpStatusError
+	if errors.As(err, &statusErr) && statusErr.HTTPStatus() == http.StatusNotFound {
+		return "", &NotFoundError{Message: fmt.Sprintf("no version found for %s/%s", namespace, name)}
+	}
+	return version, err
+}
+
 func resolveProviderVersionID(ctx context.Context, client APIClient, namespace, provider, version string) (string, error) {
 	path := fmt.Sprintf("/v2/providers/%s/%s?include=provider-versions", url.PathEscape(namespace), url.PathEscape(provider))
 	var resp providerVersionsResponse
@@ -403,32 +1087,125 @@ func resolveProviderVersionID(ctx context.Context, client APIClient, namespace,
 	return "", &NotFoundError{Message: fmt.Sprintf("provider version not found: %s/%s@%s", namespace, provider, version)}
 }
 
-func listProviderDocs(ctx context.Context, client APIClient, providerVersionID, category string, page int) ([]struct {
-	ID         string `json:"id"`
-	Type       string `json:"type"`
-	Attributes struct {
-		Category string `json:"category"`
-		Slug     string `json:"slug"`
-		Title    string `json:"title"`
-	} `json:"attributes"`
-}, error) {
+func listProviderDocs(ctx context.Context, client APIClient, providerVersionID, category string, page, pageSize int) ([]providerDocListItem, error) {
+	path := "/v2/provider-docs?" + providerDocsListQuery(providerVersionID, category, page, pageSize).Encode()
+	var resp providerDocsListResponse
+	if err := client.GetJSON(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+func providerDocsListQuery(providerVersionID, category string, page, pageSize int) url.Values {
 	q := url.Values{}
 	q.Set("filter[provider-version]", providerVersionID)
 	q.Set("filter[category]", category)
 	q.Set("filter[language]", "hcl")
 	q.Set("page[number]", fmt.Sprintf("%d", page))
+	if pageSize > 0 {
+		q.Set("page[size]", fmt.Sprintf("%d", pageSize))
+	}
+	return q
+}
 
-	path := "/v2/provider-docs?" + q.Encode()
-	var resp providerDocsListResponse
-	if err := client.GetJSON(ctx, path, &resp); err != nil {
-		return nil, err
+// validatePageSize rejects page sizes outside 0..MaxPageSize; 0 keeps the
+// registry default.
+func validatePageSize(pageSize int) error {
+	if pageSize < 0 || pageSize > MaxPageSize {
+		return &ValidationError{Message: fmt.Sprintf("-page-size must be between 0 and %d", MaxPageSize)}
 	}
-	return resp.Data, nil
+	return nil
+}
+
+// httpStatusError is implemented by client errors that carry the HTTP
+// status of a failed request, such as *registry.APIError.
+type httpStatusError interface {
+	error
+	HTTPStatus() int
+}
+
+// isForbidden reports whether err is, or wraps, a 403 response.
+func isForbidden(err error) bool {
+	var statusErr httpStatusError
+	return errors.As(err, &statusErr) && statusErr.HTTPStatus() == http.StatusForbidden
+}
+
+// FreshJSONGetter is implemented by clients that can bypass their response
+// cache, such as *registry.Client. Listing retries use it so a cached empty
+// page is not replayed.
+type FreshJSONGetter interface {
+	GetJSONFresh(ctx context.Context, path string, dst any) error
+}
+
+// retryOnEmptyBackoff is the delay before the first empty-listing retry; it
+// doubles on each subsequent attempt.
+var retryOnEmptyBackoff = 500 * time.Millisecond
+
+// retryEmptyListing re-fetches the first listing page of category up to
+// attempts times, guarding against the registry transiently serving an empty
+// page for a valid provider version.
+func retryEmptyListing(ctx context.Context, client APIClient, providerVersionID, category string, pageSize, attempts int, progress func(string)) ([]providerDocListItem, error) {
+	delay := retryOnEmptyBackoff
+	for attempt := 1; attempt <= attempts; attempt++ {
+		progress(fmt.Sprintf("Listing %s returned no docs; retrying (%d/%d)", category, attempt, attempts))
+		if delay > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+			delay *= 2
+		}
+
+		path := "/v2/provider-docs?" + providerDocsListQuery(providerVersionID, category, 1, pageSize).Encode()
+		var resp providerDocsListResponse
+		var err error
+		if fresh, ok := client.(FreshJSONGetter); ok {
+			err = fresh.GetJSONFresh(ctx, path, &resp)
+		} else {
+			err = client.GetJSON(ctx, path, &resp)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Data) > 0 {
+			return resp.Data, nil
+		}
+	}
+	return nil, nil
+}
+
+// detailRetryBackoff is the delay before the first -detail-retries retry; it
+// doubles on each subsequent attempt.
+var detailRetryBackoff = 500 * time.Millisecond
+
+// retryDetail calls fetch and, while it fails, up to retries more times
+// with backoff. It stops early once ctx is done.
+func retryDetail(ctx context.Context, retries int, docID string, progress func(string), fetch func() error) error {
+	err := fetch()
+	delay := detailRetryBackoff
+	for attempt := 1; attempt <= retries && err != nil && ctx.Err() == nil; attempt++ {
+		progress(fmt.Sprintf("Fetching doc %s failed; retrying (%d/%d)", docID, attempt, retries))
+		if delay > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+			delay *= 2
+		}
+		err = fetch()
+	}
+	return err
 }
 
 func getProviderDocDetail(ctx context.Context, client APIClient, docID string, requireRaw bool) (providerDocDetailResponse, []byte, error) {
 	var detail providerDocDetailResponse
-	path := fmt.Sprintf("/v2/provider-docs/%s", url.PathEscape(docID))
+	path := providerDocDetailPath(docID)
 	raw, err := client.Get(ctx, path)
 	if err != nil {
 		return detail, nil, err
@@ -453,26 +1230,316 @@ func getProviderDocDetail(ctx context.Context, client APIClient, docID string, r
 	return detail, raw, nil
 }
 
-func renderContent(format string, detail providerDocDetailResponse, raw []byte) ([]byte, error) {
-	switch format {
+// newSlugFilter returns a predicate reporting whether a doc slug passes the
+// allow and deny lists. An empty allow list allows every slug.
+func newSlugFilter(allow, deny []string) func(slug string) bool {
+	toSet := func(slugs []string) map[string]struct{} {
+		set := make(map[string]struct{}, len(slugs))
+		for _, s := range slugs {
+			if s = strings.TrimSpace(s); s != "" {
+				set[s] = struct{}{}
+			}
+		}
+		return set
+	}
+	allowed, denied := toSet(allow), toSet(deny)
+	return func(slug string) bool {
+		if _, ok := denied[slug]; ok {
+			return false
+		}
+		if len(allowed) == 0 {
+			return true
+		}
+		_, ok := allowed[slug]
+		return ok
+	}
+}
+
+// ConditionalGetter is implemented by clients that can issue
+// If-Modified-Since requests, such as *registry.Client. SinceModified exports
+// use it to skip docs the registry reports as unchanged, and PreserveMtime
+// for the Last-Modified it returns. With an empty since, implementations
+// should serve the request as Get would, cache and mirror included.
+type ConditionalGetter interface {
+	GetIfModifiedSince(ctx context.Context, path, since string) (body []byte, lastModified string, notModified bool, err error)
+}
+
+// getProviderDocDetailIfModified fetches a doc detail, sending since as
+// If-Modified-Since when it is non-empty. On 304 it reports notModified and
+// returns no detail.
+func getProviderDocDetailIfModified(ctx context.Context, client ConditionalGetter, docID, since string) (providerDocDetailResponse, []byte, string, bool, error) {
+	var detail providerDocDetailResponse
+	raw, lastModified, notModified, err := client.GetIfModifiedSince(ctx, providerDocDetailPath(docID), since)
+	if err != nil || notModified {
+		return detail, nil, lastModified, notModified, err
+	}
+	if err := json.Unmarshal(raw, &detail); err != nil {
+		return detail, nil, "", false, fmt.Errorf("failed to decode provider doc %s: %w", docID, err)
+	}
+	return detail, raw, lastModified, false, nil
+}
+
+// readPreviousManifestDocs loads the docs recorded by an earlier export of
+// the same provider version, keyed by doc ID. A missing or undecodable
+// manifest yields no entries so every doc is fetched in full; other read
+// errors are returned.
+func readPreviousManifestDocs(opts ExportOptions) (map[string]manifestItem, error) {
+	manifestPath := manifestPathForOptions(opts)
+	b, err := os.ReadFile(manifestPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, &WriteError{Path: manifestPath, Err: err}
+	}
+	var m manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, nil
+	}
+	docs := make(map[string]manifestItem, len(m.Docs))
+	for _, doc := range m.Docs {
+		docs[doc.DocID] = doc
+	}
+	return docs, nil
+}
+
+// unchangedOnDisk reports whether pf's file already holds pf's content as
+// recorded for the same doc and path by the previous export.
+func unchangedOnDisk(previous map[string]manifestItem, algo string, pf plannedFile) bool {
+	prev, ok := previous[pf.item.DocID]
+	if !ok || prev.Path != pf.item.Path || prev.digest(algo) == "" || prev.digest(algo) != pf.item.digest(algo) {
+		return false
+	}
+	if info, err := os.Lstat(pf.path); err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	existing, err := os.ReadFile(pf.path)
+	return err == nil && bytes.Equal(existing, pf.content)
+}
+
+// readManifestFile reads a manifest named on the command line.
+func readManifestFile(path string) (*manifest, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, &NotFoundError{Message: fmt.Sprintf("manifest not found: %s", path)}
+		}
+		return nil, &ValidationError{Message: fmt.Sprintf("failed to read manifest: %v", err)}
+	}
+	var m manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, &ValidationError{Message: fmt.Sprintf("invalid manifest %s: %v", path, err)}
+	}
+	return &m, nil
+}
+
+// newDocFilter reports whether a doc is absent from old, matching on
+// category/slug or doc ID. Doc IDs differ between provider versions, so the
+// slug is what usually matches.
+func newDocFilter(old []manifestItem) func(category, slug, docID string) bool {
+	slugs := make(map[string]struct{}, len(old))
+	ids := make(map[string]struct{}, len(old))
+	for _, doc := range old {
+		slugs[doc.Category+"/"+doc.Slug] = struct{}{}
+		ids[doc.DocID] = struct{}{}
+	}
+	return func(category, slug, docID string) bool {
+		if _, ok := ids[docID]; ok {
+			return false
+		}
+		_, ok := slugs[category+"/"+slug]
+		return !ok
+	}
+}
+
+// newSlugsForOptions lists the category/slug of the planned manifest docs
+// when exporting with NewOnlyManifest.
+func newSlugsForOptions(opts ExportOptions, planned []plannedFile) []string {
+	if opts.NewOnlyManifest == "" {
+		return nil
+	}
+	slugs := make([]string, 0, len(planned))
+	for _, pf := range planned {
+		if !pf.unlisted {
+			slugs = append(slugs, pf.item.Category+"/"+pf.item.Slug)
+		}
+	}
+	sort.Strings(slugs)
+	return slugs
+}
+
+// CategoriesFromManifest returns the distinct categories of the docs listed
+// in a previous export manifest, sorted, for re-exporting the same set.
+func CategoriesFromManifest(path string) ([]string, error) {
+	m, err := readManifestFile(path)
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[string]struct{})
+	for _, doc := range m.Docs {
+		if doc.Category != "" {
+			set[doc.Category] = struct{}{}
+		}
+	}
+	if len(set) == 0 {
+		return nil, &ValidationError{Message: fmt.Sprintf("manifest %s lists no categories", path)}
+	}
+	cats := make([]string, 0, len(set))
+	for cat := range set {
+		cats = append(cats, cat)
+	}
+	sort.Strings(cats)
+	return cats, nil
+}
+
+// fetchOverviewReadme returns the markdown for the provider README: the
+// overview doc with slug "index" (or the first overview doc), else the
+// registry description. It returns nil when neither exists.
+func fetchOverviewReadme(ctx context.Context, client APIClient, opts ExportOptions, providerVersionID string) ([]byte, error) {
+	mdOpts := opts
+	mdOpts.Format = "markdown"
+
+	docs, err := listProviderDocs(ctx, client, providerVersionID, "overview", 1, opts.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) > 0 {
+		pick := docs[0]
+		for _, doc := range docs {
+			if doc.Attributes.Slug == "index" {
+				pick = doc
+				break
+			}
+		}
+		detail, _, err := getProviderDocDetail(ctx, client, pick.ID, false)
+		if err != nil {
+			return nil, err
+		}
+		return renderContent(mdOpts, detail, nil)
+	}
+
+	path := fmt.Sprintf("/v1/providers/%s/%s/%s", url.PathEscape(opts.Namespace), url.PathEscape(opts.Name), url.PathEscape(opts.Version))
+	var resp v1ProviderDocsResponse
+	if err := client.GetJSON(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+	description := strings.TrimSpace(resp.Description)
+	if description == "" {
+		return nil, nil
+	}
+	var detail providerDocDetailResponse
+	detail.Data.Attributes.Content = fmt.Sprintf("# %s/%s\n\n%s\n", opts.Namespace, opts.Name, description)
+	return renderContent(mdOpts, detail, nil)
+}
+
+func fileWritten(opts ExportOptions, path string) {
+	if opts.OnFileWritten == nil {
+		return
+	}
+	rel, err := filepath.Rel(opts.OutDir, path)
+	if err != nil {
+		rel = path
+	}
+	opts.OnFileWritten(filepath.ToSlash(rel))
+}
+
+// setModTime sets path's access and modification times to lastModified, an
+// HTTP date. An empty or unparseable value leaves the file untouched.
+func setModTime(path, lastModified string) error {
+	t, err := http.ParseTime(lastModified)
+	if err != nil {
+		return nil
+	}
+	return os.Chtimes(path, t, t)
+}
+
+func providerDocDetailPath(docID string) string {
+	return fmt.Sprintf("/v2/provider-docs/%s", url.PathEscape(docID))
+}
+
+// hasEmptyContent reports whether a doc has no content worth writing.
+func hasEmptyContent(format string, detail providerDocDetailResponse, rendered []byte) bool {
+	if format == "json" {
+		return strings.TrimSpace(detail.Data.Attributes.Content) == ""
+	}
+	return len(bytes.TrimSpace(rendered)) == 0
+}
+
+func renderContent(opts ExportOptions, detail providerDocDetailResponse, raw []byte) ([]byte, error) {
+	switch opts.Format {
 	case "markdown":
-		return []byte(detail.Data.Attributes.Content), nil
+		content := []byte(detail.Data.Attributes.Content)
+		if opts.StripBOM {
+			content = guide.StripBOM(content)
+		}
+		if opts.TrimTrailingWhitespace {
+			content = trimTrailingWhitespace(content)
+		}
+		return applyLineEndings(content, opts.LineEndings), nil
 	case "json":
+		if len(raw) == 0 {
+			return nil, &WriteError{Path: "", Err: errors.New("empty provider doc response")}
+		}
 		var anyDoc any
-		if err := json.Unmarshal(raw, &anyDoc); err != nil {
-			if len(raw) == 0 {
-				return nil, &WriteError{Path: "", Err: errors.New("empty provider doc response")}
-			}
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		if opts.JSONStyle == "canonical" {
+			// Keep numbers verbatim so content hashes don't depend on float64
+			// round-tripping.
+			dec.UseNumber()
+		}
+		if err := dec.Decode(&anyDoc); err != nil {
 			return nil, &WriteError{Path: "", Err: fmt.Errorf("failed to decode provider doc response as json: %w", err)}
 		}
+		if _, err := dec.Token(); err != io.EOF {
+			return nil, &WriteError{Path: "", Err: errors.New("failed to decode provider doc response as json: trailing data after document")}
+		}
+		switch opts.JSONStyle {
+		case "raw":
+			return raw, nil
+		case "canonical":
+			// encoding/json sorts map keys, so a compact re-marshal is canonical.
+			formatted, err := json.Marshal(anyDoc)
+			if err != nil {
+				return nil, &WriteError{Path: "", Err: err}
+			}
+			return append(formatted, '\n'), nil
+		}
 		formatted, err := json.MarshalIndent(anyDoc, "", "  ")
 		if err != nil {
 			return nil, &WriteError{Path: "", Err: err}
 		}
 		return append(formatted, '\n'), nil
 	default:
-		return nil, &ValidationError{Message: fmt.Sprintf("unsupported format: %s", format)}
+		return nil, &ValidationError{Message: fmt.Sprintf("unsupported format: %s", opts.Format)}
+	}
+}
+
+// trimTrailingWhitespace strips trailing spaces, tabs and carriage returns
+// from each line and collapses trailing blank lines into a single final
+// newline. Whitespace-only content becomes empty.
+func trimTrailingWhitespace(content []byte) []byte {
+	lines := bytes.Split(content, []byte("\n"))
+	for i, line := range lines {
+		lines[i] = bytes.TrimRight(line, " \t\r")
+	}
+	out := bytes.TrimRight(bytes.Join(lines, []byte("\n")), "\n")
+	if len(out) == 0 {
+		return out
+	}
+	return append(out, '\n')
+}
+
+// applyLineEndings converts content to the requested line ending style.
+// "lf" (and the empty default) leaves content untouched so existing exports
+// stay byte-identical; "native" resolves to crlf on Windows.
+func applyLineEndings(content []byte, mode string) []byte {
+	if mode == "native" {
+		mode = guide.NativeEOL()
 	}
+	if mode != "crlf" {
+		return content
+	}
+	return guide.NormalizeEOL(content, "crlf")
 }
 
 func writeManifest(opts ExportOptions, docs []manifestItem) (string, error) {
@@ -485,17 +1552,28 @@ func writeManifest(opts ExportOptions, docs []manifestItem) (string, error) {
 		return "", &WriteError{Path: docsRoot, Err: err}
 	}
 
+	now := time.Now
+	if opts.now != nil {
+		now = opts.now
+	}
 	m := manifest{
-		Provider:    sanitizeSegment(opts.Name),
-		Namespace:   sanitizeSegment(opts.Namespace),
-		Version:     opts.Version,
-		Format:      opts.Format,
-		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
-		Total:       len(docs),
-		Docs:        docs,
+		Provider:      sanitizeSegment(opts.Name),
+		Namespace:     sanitizeSegment(opts.Namespace),
+		Version:       opts.Version,
+		Format:        opts.Format,
+		GeneratedAt:   now().UTC().Format(time.RFC3339),
+		HashAlgorithm: opts.HashAlgorithm,
+		Total:         len(docs),
+		Docs:          docs,
 	}
 
-	b, err := json.MarshalIndent(m, "", "  ")
+	var b []byte
+	var err error
+	if opts.CompactManifest {
+		b, err = json.Marshal(m)
+	} else {
+		b, err = json.MarshalIndent(m, "", "  ")
+	}
 	if err != nil {
 		return "", &WriteError{Path: filepath.Join(docsRoot, "_manifest.json"), Err: err}
 	}
@@ -591,7 +1669,7 @@ func deriveTemplateRoot(opts ExportOptions, ext string) (string, error) {
 		"ext":       ext,
 	}
 
-	prefix, hasUnknown := substituteUntilUnknownPlaceholder(opts.PathTemplate, known)
+	prefix, hasUnknown := substituteUntilUnknownPlaceholder(pathTemplateForOptions(opts), known)
 	if !hasUnknown {
 		prefix = filepath.Dir(prefix)
 	} else if !strings.HasSuffix(prefix, "/") && !strings.HasSuffix(prefix, string(os.PathSeparator)) {
@@ -638,11 +1716,11 @@ func validatePathTemplate(opts ExportOptions, ext string) error {
 		"provider":  sanitizeSegment(opts.Name),
 		"version":   sanitizeSegment(opts.Version),
 		"category":  "validation",
-		"slug":      "validation",
+		"slug":      slugFilename(opts, "validation"),
 		"doc_id":    "validation",
 		"ext":       ext,
 	}
-	filePath, err := BuildOutputPath(opts.PathTemplate, vars, opts.OutDir)
+	filePath, err := BuildOutputPath(pathTemplateForOptions(opts), vars, opts.OutDir)
 	if err != nil {
 		return &ValidationError{Message: err.Error()}
 	}
@@ -652,6 +1730,40 @@ func validatePathTemplate(opts ExportOptions, ext string) error {
 	return nil
 }
 
+// CheckPathTemplate validates opts and its path template offline, as an
+// export would, and additionally rejects templates that map docs with
+// different categories, slugs and IDs to the same file. It returns the path,
+// relative to OutDir, of a sample doc.
+func CheckPathTemplate(opts ExportOptions) (string, error) {
+	ext, err := prepareExportOptions(&opts)
+	if err != nil {
+		return "", err
+	}
+	vars := map[string]string{
+		"out":       opts.OutDir,
+		"namespace": sanitizeSegment(opts.Namespace),
+		"provider":  sanitizeSegment(opts.Name),
+		"version":   sanitizeSegment(opts.Version),
+		"ext":       ext,
+	}
+	var paths [2]string
+	for i, sample := range [2][3]string{{"resources", "sample_resource", "1"}, {"guides", "sample-guide", "2"}} {
+		vars["category"], vars["slug"], vars["doc_id"] = sample[0], slugFilename(opts, sample[1]), sample[2]
+		paths[i], err = BuildOutputPath(pathTemplateForOptions(opts), vars, opts.OutDir)
+		if err != nil {
+			return "", &ValidationError{Message: err.Error()}
+		}
+	}
+	if paths[0] == paths[1] {
+		return "", &ValidationError{Message: fmt.Sprintf("path collision detected in -path-template: every doc maps to %s; include {slug} or {doc_id}", paths[0])}
+	}
+	rel, err := filepath.Rel(opts.OutDir, paths[0])
+	if err != nil {
+		rel = paths[0]
+	}
+	return filepath.ToSlash(rel), nil
+}
+
 func prepareExportOptions(opts *ExportOptions) (string, error) {
 	if err := validateExportOptions(opts); err != nil {
 		return "", err
@@ -667,8 +1779,50 @@ func prepareExportOptions(opts *ExportOptions) (string, error) {
 	return ext, nil
 }
 
+// normalizePathPrefix sanitizes each segment of a -prefix value and rejects
+// parent-directory references so the prefix can only nest deeper in -out-dir.
+func normalizePathPrefix(prefix string) (string, error) {
+	prefix = strings.TrimSpace(filepath.ToSlash(prefix))
+	if prefix == "" {
+		return "", nil
+	}
+	if strings.HasPrefix(prefix, "/") {
+		return "", &ValidationError{Message: fmt.Sprintf("invalid -prefix %q: must be a relative path", prefix)}
+	}
+	segments := make([]string, 0)
+	for _, segment := range strings.Split(prefix, "/") {
+		segment = strings.TrimSpace(segment)
+		if segment == "" || segment == "." {
+			continue
+		}
+		if segment == ".." {
+			return "", &ValidationError{Message: fmt.Sprintf("invalid -prefix %q: must not contain '..'", prefix)}
+		}
+		segments = append(segments, sanitizeSegment(segment))
+	}
+	return strings.Join(segments, "/"), nil
+}
+
+// pathTemplateForOptions returns the path template with opts.Prefix nested
+// directly under {out} (or prepended for relative templates).
+func pathTemplateForOptions(opts ExportOptions) string {
+	if opts.Prefix == "" {
+		return opts.PathTemplate
+	}
+	if rest, ok := strings.CutPrefix(opts.PathTemplate, "{out}"); ok {
+		return "{out}/" + opts.Prefix + "/" + strings.TrimLeft(rest, "/")
+	}
+	return opts.Prefix + "/" + opts.PathTemplate
+}
+
 func manifestRootForOptions(opts ExportOptions) string {
-	return filepath.Join(opts.OutDir, "terraform", sanitizeSegment(opts.Namespace), sanitizeSegment(opts.Name), sanitizeSegment(opts.Version), "docs")
+	return filepath.Join(opts.OutDir, filepath.FromSlash(opts.Prefix), "terraform", sanitizeSegment(opts.Namespace), sanitizeSegment(opts.Name), sanitizeSegment(opts.Version), "docs")
+}
+
+// readmePathForOptions is the provider/version root, one level above the
+// manifest's docs directory.
+func readmePathForOptions(opts ExportOptions) string {
+	return filepath.Join(filepath.Dir(manifestRootForOptions(opts)), "README.md")
 }
 
 func manifestPathForOptions(opts ExportOptions) string {
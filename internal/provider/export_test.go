@@ -1,15 +1,21 @@
 package provider
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 type fakeAPIClient struct{}
@@ -1042,6 +1048,86 @@ func TestExportDocs_CleanRejectsOutDirAncestorSymlink(t *testing.T) {
 	}
 }
 
+func TestExportDocs_AllowSymlinkRootPermitsSymlinkedOutDir(t *testing.T) {
+	realDir := t.TempDir()
+	outDir := filepath.Join(t.TempDir(), "docs-link")
+	if err := os.Symlink(realDir, outDir); err != nil {
+		t.Skipf("symlink is not supported on this platform: %v", err)
+	}
+	stale := filepath.Join(realDir, "terraform", "hashicorp", "aws", "6.31.0", "docs", "old", "stale.md")
+	if err := os.MkdirAll(filepath.Dir(stale), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(stale, []byte("stale"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	opts := ExportOptions{
+		Namespace:  "hashicorp",
+		Name:       "aws",
+		Version:    "6.31.0",
+		Format:     "markdown",
+		OutDir:     outDir,
+		Categories: []string{"guides"},
+		Clean:      true,
+	}
+
+	if _, err := ExportDocs(context.Background(), &fakeAPIClient{}, opts); err == nil {
+		t.Fatal("expected symlinked -out-dir to be rejected without AllowSymlinkRoot")
+	}
+
+	opts.AllowSymlinkRoot = true
+	summary, err := ExportDocs(context.Background(), &fakeAPIClient{}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary.Written == 0 {
+		t.Fatal("expected docs to be written through the symlinked -out-dir")
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Fatalf("expected -clean to remove stale doc under the resolved root, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(realDir, "terraform", "hashicorp", "aws", "6.31.0", "docs", "_manifest.json")); err != nil {
+		t.Fatalf("expected manifest under the symlink target: %v", err)
+	}
+}
+
+func TestExportDocs_AllowSymlinkRootStillRejectsNestedSymlinks(t *testing.T) {
+	realDir := t.TempDir()
+	externalDir := t.TempDir()
+	outDir := filepath.Join(t.TempDir(), "docs-link")
+	if err := os.Symlink(realDir, outDir); err != nil {
+		t.Skipf("symlink is not supported on this platform: %v", err)
+	}
+	if err := os.Symlink(externalDir, filepath.Join(realDir, "terraform")); err != nil {
+		t.Fatal(err)
+	}
+	externalVictim := filepath.Join(externalDir, "hashicorp", "aws", "6.31.0", "docs", "victim.txt")
+	if err := os.MkdirAll(filepath.Dir(externalVictim), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(externalVictim, []byte("do-not-delete"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := ExportDocs(context.Background(), &fakeAPIClient{}, ExportOptions{
+		Namespace:        "hashicorp",
+		Name:             "aws",
+		Version:          "6.31.0",
+		Format:           "markdown",
+		OutDir:           outDir,
+		Categories:       []string{"guides"},
+		Clean:            true,
+		AllowSymlinkRoot: true,
+	})
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) {
+		t.Fatalf("expected validation error for nested symlink, got %T (%v)", err, err)
+	}
+	if _, err := os.Stat(externalVictim); err != nil {
+		t.Fatalf("expected external file to remain untouched: %v", err)
+	}
+}
+
 func TestExportDocs_PathTemplateCollisionReturnsValidationError(t *testing.T) {
 	outDir := t.TempDir()
 	client := &fakeCollisionClient{}
@@ -1090,6 +1176,53 @@ func TestExportDocs_PathTemplateCollisionWithManifestReturnsValidationError(t *t
 	}
 }
 
+func TestExportDocs_FilenamePrefixAndSuffixWrapSlug(t *testing.T) {
+	outDir := t.TempDir()
+	client := &fakeCatalogClient{docs: []fakeCatalogDoc{
+		{ID: "1", Category: "resources", Slug: "s3_bucket", Title: "aws_s3_bucket", Content: "# S3"},
+	}}
+	_, err := ExportDocs(context.Background(), client, ExportOptions{
+		Namespace:      "hashicorp",
+		Name:           "aws",
+		Version:        "6.31.0",
+		Format:         "markdown",
+		OutDir:         outDir,
+		Categories:     []string{"resources"},
+		FilenamePrefix: "TF-",
+		FilenameSuffix: "/doc",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := filepath.Join(outDir, "terraform", "hashicorp", "aws", "6.31.0", "docs", "resources", "tf-s3_bucket-doc.md")
+	if _, err := os.Stat(want); err != nil {
+		t.Fatalf("expected prefixed and suffixed file: %v", err)
+	}
+}
+
+func TestExportDocs_FilenamePrefixCollisionIsDetected(t *testing.T) {
+	client := &fakeCatalogClient{docs: []fakeCatalogDoc{
+		{ID: "1", Category: "guides", Slug: "manifest", Title: "Manifest", Content: "# Manifest"},
+	}}
+	_, err := ExportDocs(context.Background(), client, ExportOptions{
+		Namespace:      "hashicorp",
+		Name:           "aws",
+		Version:        "6.31.0",
+		Format:         "json",
+		OutDir:         t.TempDir(),
+		Categories:     []string{"guides"},
+		PathTemplate:   "{out}/terraform/{namespace}/{provider}/{version}/docs/{slug}.{ext}",
+		FilenamePrefix: "_",
+	})
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) {
+		t.Fatalf("expected validation error, got %T (%v)", err, err)
+	}
+	if !strings.Contains(vErr.Error(), "reserved manifest path") {
+		t.Fatalf("unexpected error message: %s", vErr.Error())
+	}
+}
+
 func TestExportDocs_PathTemplateCollisionWithManifestFailsWhenNoDocsFound(t *testing.T) {
 	outDir := t.TempDir()
 	client := &fakeAPIClient{}
@@ -1191,6 +1324,43 @@ func TestExportDocs_PathTemplateOutsideOutDirFailsWhenNoDocsFound(t *testing.T)
 	}
 }
 
+func TestCheckPathTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{"default", "", "terraform/hashicorp/aws/6.31.0/docs/resources/sample_resource.md"},
+		{"outside out-dir", "{out}/../outside/{slug}.{ext}", "outside -out-dir"},
+		{"unknown placeholder", "{out}/{nope}/{slug}.{ext}", "{nope}"},
+		{"every doc collides", "{out}/{provider}/index.{ext}", "every doc maps to"},
+		{"manifest collision", "{out}/terraform/{namespace}/{provider}/{version}/docs/_manifest.json", "reserved manifest path"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rel, err := CheckPathTemplate(ExportOptions{
+				Name:         "aws",
+				Version:      "6.31.0",
+				OutDir:       t.TempDir(),
+				PathTemplate: tc.template,
+			})
+			if tc.template == "" {
+				if err != nil || rel != tc.want {
+					t.Fatalf("expected sample path %s, got %q (%v)", tc.want, rel, err)
+				}
+				return
+			}
+			var vErr *ValidationError
+			if !errors.As(err, &vErr) {
+				t.Fatalf("expected ValidationError, got %T (%v)", err, err)
+			}
+			if !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
 func TestNormalizeCategories_AllIncludesEphemeralResources(t *testing.T) {
 	cats, err := normalizeCategories([]string{"all"})
 	if err != nil {
@@ -1218,6 +1388,50 @@ func TestNormalizeCategories_EphemeralResourcesAllowed(t *testing.T) {
 	}
 }
 
+func TestNormalizeCategories_ResolvesAliases(t *testing.T) {
+	for alias, want := range map[string]string{
+		"datasource":          "data-sources",
+		"datasources":         "data-sources",
+		"data_sources":        "data-sources",
+		"data-source":         "data-sources",
+		"Data Sources":        "data-sources",
+		"resource":            "resources",
+		"Resources":           "resources",
+		"ephemeral_resource":  "ephemeral-resources",
+		"ephemeralresources":  "ephemeral-resources",
+		"function":            "functions",
+		"guide":               "guides",
+		"action":              "actions",
+		"list_resources":      "list-resources",
+		"listresource":        "list-resources",
+		"ephemeral-resources": "ephemeral-resources",
+	} {
+		cats, err := normalizeCategories([]string{alias})
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", alias, err)
+		}
+		if len(cats) != 1 || cats[0] != want {
+			t.Fatalf("%q: expected [%s], got %v", alias, want, cats)
+		}
+	}
+
+	cats, err := normalizeCategories([]string{"datasource,data-sources,resource"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cats) != 2 || cats[0] != "data-sources" || cats[1] != "resources" {
+		t.Fatalf("expected aliases to dedup with their canonical names, got %v", cats)
+	}
+
+	for _, unknown := range []string{"datasourcez", "widgets"} {
+		_, err := normalizeCategories([]string{unknown})
+		var vErr *ValidationError
+		if !errors.As(err, &vErr) || vErr.Message != "unsupported category: "+unknown {
+			t.Fatalf("%q: expected unsupported category error, got %v", unknown, err)
+		}
+	}
+}
+
 func TestExportDocs_CleanKeepsLegacySharedManifestWhenNamespaceDiffers(t *testing.T) {
 	outDir := t.TempDir()
 	legacyManifestPath := filepath.Join(outDir, "terraform", "aws", "6.31.0", "docs", "_manifest.json")
@@ -1257,3 +1471,1827 @@ func TestExportDocs_CleanKeepsLegacySharedManifestWhenNamespaceDiffers(t *testin
 		t.Fatalf("expected namespaced manifest to be written: %v", err)
 	}
 }
+
+func TestPreflightExportOptions_ValidatesProviderIdentifiers(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace string
+		provider  string
+		wantErr   string
+	}{
+		{name: "valid", namespace: "hashicorp", provider: "aws"},
+		{name: "name with punctuation", namespace: "hashicorp", provider: "aws!", wantErr: "invalid -name"},
+		{name: "namespace with space", namespace: "Hashi Corp", provider: "aws", wantErr: "invalid -namespace"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := ExportOptions{
+				Namespace: tc.namespace,
+				Name:      tc.provider,
+				Version:   "6.31.0",
+				OutDir:    t.TempDir(),
+			}
+			err := PreflightExportOptions(&opts)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var vErr *ValidationError
+			if !errors.As(err, &vErr) {
+				t.Fatalf("expected ValidationError, got %T (%v)", err, err)
+			}
+			if !strings.Contains(vErr.Error(), tc.wantErr) {
+				t.Fatalf("unexpected error message: %s", vErr.Error())
+			}
+		})
+	}
+}
+
+func TestApplyLineEndings(t *testing.T) {
+	input := []byte("# title\n\nbody\r\nend\n")
+
+	if got := applyLineEndings(input, "lf"); string(got) != string(input) {
+		t.Fatalf("expected lf to leave content unchanged, got %q", got)
+	}
+	if got := applyLineEndings(input, "crlf"); string(got) != "# title\r\n\r\nbody\r\nend\r\n" {
+		t.Fatalf("unexpected crlf conversion: %q", got)
+	}
+}
+
+func TestExportDocs_RejectsUnknownLineEndings(t *testing.T) {
+	_, err := ExportDocs(context.Background(), &fakeAPIClient{}, ExportOptions{
+		Name:        "aws",
+		Version:     "6.31.0",
+		OutDir:      t.TempDir(),
+		LineEndings: "cr",
+	})
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) {
+		t.Fatalf("expected validation error, got %T (%v)", err, err)
+	}
+}
+
+func TestExportDocs_PrefixNestsLayout(t *testing.T) {
+	outDir := t.TempDir()
+	summary, err := ExportDocs(context.Background(), &fakeAPIClient{}, ExportOptions{
+		Namespace:  "hashicorp",
+		Name:       "aws",
+		Version:    "6.31.0",
+		Format:     "markdown",
+		OutDir:     outDir,
+		Categories: []string{"guides"},
+		Prefix:     "mirror/2024",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	guidePath := filepath.Join(outDir, "mirror", "2024", "terraform", "hashicorp", "aws", "6.31.0", "docs", "guides", "tag-policy-compliance.md")
+	if _, err := os.Stat(guidePath); err != nil {
+		t.Fatalf("expected guide under prefixed path: %v", err)
+	}
+	if !strings.HasSuffix(summary.Manifest, "mirror/2024/terraform/hashicorp/aws/6.31.0/docs/_manifest.json") {
+		t.Fatalf("unexpected manifest path: %s", summary.Manifest)
+	}
+}
+
+func TestExportDocs_PrefixRejectsParentTraversal(t *testing.T) {
+	_, err := ExportDocs(context.Background(), &fakeAPIClient{}, ExportOptions{
+		Namespace:  "hashicorp",
+		Name:       "aws",
+		Version:    "6.31.0",
+		OutDir:     t.TempDir(),
+		Categories: []string{"guides"},
+		Prefix:     "../escape",
+	})
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) {
+		t.Fatalf("expected validation error, got %T (%v)", err, err)
+	}
+	if !strings.Contains(vErr.Error(), "..") {
+		t.Fatalf("unexpected error message: %s", vErr.Error())
+	}
+}
+
+type fakeMultiVersionClient struct {
+	// revised makes 6.32.0 serve different content than 6.31.0.
+	revised bool
+}
+
+func (f *fakeMultiVersionClient) GetJSON(_ context.Context, path string, dst any) error {
+	if strings.HasPrefix(path, "/v2/providers/hashicorp/aws") {
+		b, _ := json.Marshal(map[string]any{
+			"included": []any{
+				map[string]any{"type": "provider-versions", "id": "70800", "attributes": map[string]any{"version": "6.31.0"}},
+				map[string]any{"type": "provider-versions", "id": "70801", "attributes": map[string]any{"version": "6.32.0"}},
+			},
+		})
+		return json.Unmarshal(b, dst)
+	}
+
+	if strings.HasPrefix(path, "/v2/provider-docs?") {
+		u, err := url.Parse(path)
+		if err != nil {
+			return err
+		}
+		q := u.Query()
+		var data []map[string]any
+		if q.Get("filter[category]") == "guides" && q.Get("page[number]") == "1" {
+			id := "1"
+			if q.Get("filter[provider-version]") == "70801" {
+				id = "11"
+			}
+			data = []map[string]any{{
+				"id":         id,
+				"attributes": map[string]any{"category": "guides", "slug": "tag-policy-compliance", "title": "Tag Policy Compliance"},
+			}}
+		}
+		b, _ := json.Marshal(map[string]any{"data": data})
+		return json.Unmarshal(b, dst)
+	}
+
+	return fmt.Errorf("unexpected GetJSON path: %s", path)
+}
+
+func (f *fakeMultiVersionClient) Get(_ context.Context, path string) ([]byte, error) {
+	switch path {
+	case "/v2/provider-docs/1", "/v2/provider-docs/11":
+		id := strings.TrimPrefix(path, "/v2/provider-docs/")
+		content := "# identical guide"
+		if f.revised && id == "11" {
+			content = "# revised guide"
+		}
+		return []byte(`{"data":{"id":"` + id + `","attributes":{"category":"guides","slug":"tag-policy-compliance","title":"Tag Policy Compliance","content":"` + content + `"}}}`), nil
+	default:
+		return nil, fmt.Errorf("unexpected Get path: %s", path)
+	}
+}
+
+func TestExportDocs_HardlinkIdenticalAcrossVersions(t *testing.T) {
+	outDir := t.TempDir()
+	index := NewLinkIndex()
+
+	for _, version := range []string{"6.31.0", "6.32.0"} {
+		_, err := ExportDocs(context.Background(), &fakeMultiVersionClient{}, ExportOptions{
+			Namespace:         "hashicorp",
+			Name:              "aws",
+			Version:           version,
+			Format:            "markdown",
+			OutDir:            outDir,
+			Categories:        []string{"guides"},
+			HardlinkIdentical: true,
+			LinkIndex:         index,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	first := filepath.Join(outDir, "terraform", "hashicorp", "aws", "6.31.0", "docs", "guides", "tag-policy-compliance.md")
+	second := filepath.Join(outDir, "terraform", "hashicorp", "aws", "6.32.0", "docs", "guides", "tag-policy-compliance.md")
+	firstInfo, err := os.Stat(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secondInfo, err := os.Stat(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := os.ReadFile(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != "# identical guide" {
+		t.Fatalf("unexpected content: %q", body)
+	}
+	if !os.SameFile(firstInfo, secondInfo) {
+		t.Skip("hardlinks are not supported on this filesystem; content was copied instead")
+	}
+}
+
+func TestExportDocs_RewriteDoesNotTouchHardlinkedCopies(t *testing.T) {
+	outDir := t.TempDir()
+	index := NewLinkIndex()
+
+	for _, version := range []string{"6.31.0", "6.32.0"} {
+		_, err := ExportDocs(context.Background(), &fakeMultiVersionClient{}, ExportOptions{
+			Namespace:         "hashicorp",
+			Name:              "aws",
+			Version:           version,
+			Format:            "markdown",
+			OutDir:            outDir,
+			Categories:        []string{"guides"},
+			HardlinkIdentical: true,
+			LinkIndex:         index,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err := ExportDocs(context.Background(), &fakeMultiVersionClient{revised: true}, ExportOptions{
+		Namespace:  "hashicorp",
+		Name:       "aws",
+		Version:    "6.32.0",
+		Format:     "markdown",
+		OutDir:     outDir,
+		Categories: []string{"guides"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := filepath.Join(outDir, "terraform", "hashicorp", "aws", "6.31.0", "docs", "guides", "tag-policy-compliance.md")
+	second := filepath.Join(outDir, "terraform", "hashicorp", "aws", "6.32.0", "docs", "guides", "tag-policy-compliance.md")
+	body, err := os.ReadFile(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != "# identical guide" {
+		t.Fatalf("re-export modified the other version's file: %q", body)
+	}
+	body, err = os.ReadFile(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != "# revised guide" {
+		t.Fatalf("unexpected content: %q", body)
+	}
+}
+
+// fakeMultiVersionConditionalClient serves fakeMultiVersionClient's docs
+// with a per-doc Last-Modified.
+type fakeMultiVersionConditionalClient struct {
+	*fakeMultiVersionClient
+	lastModified map[string]string
+}
+
+func (f *fakeMultiVersionConditionalClient) GetIfModifiedSince(ctx context.Context, path, _ string) ([]byte, string, bool, error) {
+	body, err := f.Get(ctx, path)
+	return body, f.lastModified[strings.TrimPrefix(path, "/v2/provider-docs/")], false, err
+}
+
+func TestExportDocs_PreserveMtimeLeavesHardlinkedCopiesAlone(t *testing.T) {
+	outDir := t.TempDir()
+	index := NewLinkIndex()
+	client := &fakeMultiVersionConditionalClient{
+		fakeMultiVersionClient: &fakeMultiVersionClient{},
+		lastModified: map[string]string{
+			"1":  "Mon, 02 Jun 2025 10:00:00 GMT",
+			"11": "Tue, 01 Jul 2025 10:00:00 GMT",
+		},
+	}
+
+	for _, version := range []string{"6.31.0", "6.32.0"} {
+		_, err := ExportDocs(context.Background(), client, ExportOptions{
+			Namespace:         "hashicorp",
+			Name:              "aws",
+			Version:           version,
+			Format:            "markdown",
+			OutDir:            outDir,
+			Categories:        []string{"guides"},
+			HardlinkIdentical: true,
+			LinkIndex:         index,
+			PreserveMtime:     true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	first := filepath.Join(outDir, "terraform", "hashicorp", "aws", "6.31.0", "docs", "guides", "tag-policy-compliance.md")
+	info, err := os.Stat(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+	if !info.ModTime().Equal(want) {
+		t.Fatalf("expected 6.31.0 doc to keep mtime %v, got %v", want, info.ModTime())
+	}
+}
+
+func TestExportDocs_ExcludeDeprecated(t *testing.T) {
+	client := &fakeCatalogClient{docs: []fakeCatalogDoc{
+		{ID: "1", Category: "resources", Slug: "aws_s3_bucket", Title: "aws_s3_bucket", Content: "# current"},
+		{ID: "2", Category: "resources", Slug: "aws_s3_bucket_object", Title: "aws_s3_bucket_object", Content: "# old", Extra: map[string]any{"deprecated": true}},
+	}}
+
+	for _, exclude := range []bool{false, true} {
+		outDir := t.TempDir()
+		summary, err := ExportDocs(context.Background(), client, ExportOptions{
+			Name:              "aws",
+			Version:           "6.31.0",
+			OutDir:            outDir,
+			Categories:        []string{"resources"},
+			ExcludeDeprecated: exclude,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := 2
+		if exclude {
+			want = 1
+		}
+		if summary.Written != want {
+			t.Fatalf("exclude=%v: expected %d docs, got %d", exclude, want, summary.Written)
+		}
+		deprecatedPath := filepath.Join(outDir, "terraform", "hashicorp", "aws", "6.31.0", "docs", "resources", "aws_s3_bucket_object.md")
+		_, statErr := os.Stat(deprecatedPath)
+		if exclude && !os.IsNotExist(statErr) {
+			t.Fatalf("expected deprecated doc to be skipped, stat err: %v", statErr)
+		}
+	}
+}
+
+func TestExportDocs_NewOnlyExportsDocsMissingFromOldManifest(t *testing.T) {
+	client := &fakeCatalogClient{docs: []fakeCatalogDoc{
+		{ID: "11", Category: "resources", Slug: "aws_s3_bucket", Title: "aws_s3_bucket", Content: "# bucket"},
+		{ID: "12", Category: "resources", Slug: "aws_s3_directory_bucket", Title: "aws_s3_directory_bucket", Content: "# new"},
+		{ID: "13", Category: "data-sources", Slug: "aws_s3_bucket", Title: "aws_s3_bucket", Content: "# data"},
+	}}
+	oldManifest := filepath.Join(t.TempDir(), "_manifest.json")
+	old := `{"docs":[
+		{"doc_id":"1","category":"resources","slug":"aws_s3_bucket","path":"resources/aws_s3_bucket.md"},
+		{"doc_id":"3","category":"data-sources","slug":"aws_s3_bucket","path":"data-sources/aws_s3_bucket.md"}
+	]}`
+	if err := os.WriteFile(oldManifest, []byte(old), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	outDir := t.TempDir()
+	summary, err := ExportDocs(context.Background(), client, ExportOptions{
+		Name:            "aws",
+		Version:         "6.31.0",
+		OutDir:          outDir,
+		Categories:      []string{"resources", "data-sources"},
+		NewOnlyManifest: oldManifest,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary.Written != 1 {
+		t.Fatalf("expected only the new doc to be written, got %d", summary.Written)
+	}
+	if want := []string{"resources/aws_s3_directory_bucket"}; strings.Join(summary.NewSlugs, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected new slugs %v, got %v", want, summary.NewSlugs)
+	}
+	docsDir := filepath.Join(outDir, "terraform", "hashicorp", "aws", "6.31.0", "docs")
+	if _, err := os.Stat(filepath.Join(docsDir, "resources", "aws_s3_directory_bucket.md")); err != nil {
+		t.Fatalf("expected new doc to be exported: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(docsDir, "resources", "aws_s3_bucket.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected existing doc to be skipped, stat err: %v", err)
+	}
+	if n := client.callCount("/v2/provider-docs/"); n != 1 {
+		t.Fatalf("expected only the new doc's detail to be fetched, got %d fetches", n)
+	}
+}
+
+// fakeEventuallyConsistentClient serves an empty first listing page for the
+// first emptyLists requests, then delegates to the wrapped catalog. Retries
+// arrive through GetJSONFresh so the test can assert the cache is bypassed.
+type fakeEventuallyConsistentClient struct {
+	*fakeCatalogClient
+	emptyLists  int
+	freshCalls  int
+	listAttempt int
+}
+
+func (f *fakeEventuallyConsistentClient) GetJSON(ctx context.Context, path string, dst any) error {
+	if strings.HasPrefix(path, "/v2/provider-docs?") {
+		f.listAttempt++
+		if f.listAttempt <= f.emptyLists {
+			return json.Unmarshal([]byte(`{"data":[]}`), dst)
+		}
+	}
+	return f.fakeCatalogClient.GetJSON(ctx, path, dst)
+}
+
+func (f *fakeEventuallyConsistentClient) GetJSONFresh(ctx context.Context, path string, dst any) error {
+	f.freshCalls++
+	return f.GetJSON(ctx, path, dst)
+}
+
+func TestExportDocs_RetryOnEmptyEventuallyExports(t *testing.T) {
+	old := retryOnEmptyBackoff
+	retryOnEmptyBackoff = 0
+	defer func() { retryOnEmptyBackoff = old }()
+
+	client := &fakeEventuallyConsistentClient{
+		fakeCatalogClient: &fakeCatalogClient{docs: []fakeCatalogDoc{
+			{ID: "1", Category: "resources", Slug: "aws_s3_bucket", Title: "aws_s3_bucket", Content: "# bucket"},
+		}},
+		emptyLists: 2,
+	}
+
+	outDir := t.TempDir()
+	summary, err := ExportDocs(context.Background(), client, ExportOptions{
+		Name:         "aws",
+		Version:      "6.31.0",
+		OutDir:       outDir,
+		Categories:   []string{"resources"},
+		RetryOnEmpty: 3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Written != 1 {
+		t.Fatalf("expected 1 doc after retries, got %d", summary.Written)
+	}
+	if client.freshCalls != 2 {
+		t.Fatalf("expected 2 cache-bypassing retries, got %d", client.freshCalls)
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "terraform", "hashicorp", "aws", "6.31.0", "docs", "resources", "aws_s3_bucket.md")); err != nil {
+		t.Fatalf("expected doc to be written: %v", err)
+	}
+}
+
+func TestExportDocs_RetryOnEmptyGivesUpAfterN(t *testing.T) {
+	old := retryOnEmptyBackoff
+	retryOnEmptyBackoff = 0
+	defer func() { retryOnEmptyBackoff = old }()
+
+	client := &fakeEventuallyConsistentClient{
+		fakeCatalogClient: &fakeCatalogClient{docs: []fakeCatalogDoc{
+			{ID: "1", Category: "resources", Slug: "aws_s3_bucket", Title: "aws_s3_bucket", Content: "# bucket"},
+		}},
+		emptyLists: 5,
+	}
+
+	summary, err := ExportDocs(context.Background(), client, ExportOptions{
+		Name:         "aws",
+		Version:      "6.31.0",
+		OutDir:       t.TempDir(),
+		Categories:   []string{"resources"},
+		RetryOnEmpty: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Written != 0 {
+		t.Fatalf("expected no docs once retries are exhausted, got %d", summary.Written)
+	}
+	if client.listAttempt != 3 {
+		t.Fatalf("expected 1 listing plus 2 retries, got %d", client.listAttempt)
+	}
+}
+
+func TestExportDocs_RejectsNegativeRetryOnEmpty(t *testing.T) {
+	_, err := ExportDocs(context.Background(), &fakeCatalogClient{}, ExportOptions{
+		Name:         "aws",
+		Version:      "6.31.0",
+		OutDir:       t.TempDir(),
+		RetryOnEmpty: -1,
+	})
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || !strings.Contains(vErr.Message, "-retry-on-empty") {
+		t.Fatalf("expected -retry-on-empty validation error, got %v", err)
+	}
+}
+
+func TestExportDocs_OnFileWrittenReportsRelativePaths(t *testing.T) {
+	var written []string
+	_, err := ExportDocs(context.Background(), &fakeAPIClient{}, ExportOptions{
+		Name:          "aws",
+		Version:       "6.31.0",
+		OutDir:        t.TempDir(),
+		Categories:    []string{"guides"},
+		OnFileWritten: func(relPath string) { written = append(written, relPath) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"terraform/hashicorp/aws/6.31.0/docs/guides/tag-policy-compliance.md",
+		"terraform/hashicorp/aws/6.31.0/docs/_manifest.json",
+	}
+	if strings.Join(written, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected written paths:\n got %q\nwant %q", written, want)
+	}
+}
+
+func TestRenderContent_JSONStyles(t *testing.T) {
+	raw := []byte(`{"data": {"id": "1", "attributes": {"title": "t", "category": "guides"}}, "big": 12345678901234567890}`)
+
+	tests := []struct {
+		style string
+		want  string
+	}{
+		{style: "raw", want: string(raw)},
+		{style: "canonical", want: `{"big":12345678901234567890,"data":{"attributes":{"category":"guides","title":"t"},"id":"1"}}` + "\n"},
+	}
+	for _, tt := range tests {
+		got, err := renderContent(ExportOptions{Format: "json", JSONStyle: tt.style}, providerDocDetailResponse{}, raw)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.style, err)
+		}
+		if string(got) != tt.want {
+			t.Fatalf("%s: expected\n%s\ngot\n%s", tt.style, tt.want, got)
+		}
+	}
+
+	pretty, err := renderContent(ExportOptions{Format: "json", JSONStyle: "pretty"}, providerDocDetailResponse{}, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(pretty), "{\n  \"big\"") {
+		t.Fatalf("expected indented output with sorted keys, got\n%s", pretty)
+	}
+}
+
+func TestExportDocs_RejectsUnknownJSONStyle(t *testing.T) {
+	_, err := ExportDocs(context.Background(), &fakeAPIClient{}, ExportOptions{
+		Name:      "aws",
+		Version:   "6.31.0",
+		Format:    "json",
+		OutDir:    t.TempDir(),
+		JSONStyle: "minified",
+	})
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || !strings.Contains(vErr.Message, "-json-style") {
+		t.Fatalf("expected -json-style validation error, got %v", err)
+	}
+}
+
+func TestExportDocs_DocIDsExportsOnlyThoseDocs(t *testing.T) {
+	client := &fakeCatalogClient{docs: []fakeCatalogDoc{
+		{ID: "1", Category: "guides", Slug: "getting-started", Title: "Getting Started", Content: "# guide"},
+		{ID: "2", Category: "resources", Slug: "aws_s3_bucket", Title: "aws_s3_bucket", Content: "# bucket"},
+		{ID: "3", Category: "resources", Slug: "aws_instance", Title: "aws_instance", Content: "# instance"},
+	}}
+
+	outDir := t.TempDir()
+	summary, err := ExportDocs(context.Background(), client, ExportOptions{
+		Name:    "aws",
+		Version: "6.31.0",
+		OutDir:  outDir,
+		DocIDs:  []string{" 2", "1", "2"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Written != 2 {
+		t.Fatalf("expected 2 docs, got %d", summary.Written)
+	}
+	if n := client.callCount("/v2/provider-docs?"); n != 0 {
+		t.Fatalf("expected no listing requests, got %d", n)
+	}
+	if n := client.callCount("/v2/providers/"); n != 0 {
+		t.Fatalf("expected no version resolution, got %d", n)
+	}
+
+	docsRoot := filepath.Join(outDir, "terraform", "hashicorp", "aws", "6.31.0", "docs")
+	for _, rel := range []string{"guides/getting-started.md", "resources/aws_s3_bucket.md"} {
+		if _, err := os.Stat(filepath.Join(docsRoot, rel)); err != nil {
+			t.Fatalf("expected %s to be written: %v", rel, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(docsRoot, "resources", "aws_instance.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected unrequested doc to be absent, stat err: %v", err)
+	}
+}
+
+func TestExportDocs_DocIDsMustBeNumeric(t *testing.T) {
+	_, err := ExportDocs(context.Background(), &fakeCatalogClient{}, ExportOptions{
+		Name:    "aws",
+		Version: "6.31.0",
+		OutDir:  t.TempDir(),
+		DocIDs:  []string{"12", "abc"},
+	})
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || !strings.Contains(vErr.Message, "-doc-ids must be numeric: abc") {
+		t.Fatalf("expected numeric validation error, got %v", err)
+	}
+}
+
+func TestExportDocs_PageSizeSetOnListingQuery(t *testing.T) {
+	client := &fakeCatalogClient{docs: []fakeCatalogDoc{
+		{ID: "1", Category: "guides", Slug: "getting-started", Title: "Getting Started", Content: "# guide"},
+	}}
+	if _, err := ExportDocs(context.Background(), client, ExportOptions{
+		Name:       "aws",
+		Version:    "6.31.0",
+		OutDir:     t.TempDir(),
+		Categories: []string{"guides"},
+		PageSize:   100,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	listings := 0
+	for _, call := range client.calls {
+		if !strings.HasPrefix(call, "/v2/provider-docs?") {
+			continue
+		}
+		listings++
+		u, err := url.Parse(call)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := u.Query().Get("page[size]"); got != "100" {
+			t.Fatalf("expected page[size]=100 in %s, got %q", call, got)
+		}
+	}
+	if listings == 0 {
+		t.Fatalf("expected listing requests")
+	}
+}
+
+func TestExportDocs_RejectsPageSizeAboveMax(t *testing.T) {
+	_, err := ExportDocs(context.Background(), &fakeCatalogClient{}, ExportOptions{
+		Name:     "aws",
+		Version:  "6.31.0",
+		OutDir:   t.TempDir(),
+		PageSize: MaxPageSize + 1,
+	})
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || !strings.Contains(vErr.Message, "-page-size") {
+		t.Fatalf("expected -page-size validation error, got %v", err)
+	}
+}
+
+func TestExportDocs_ReportsLexicographicallyFirstCollision(t *testing.T) {
+	client := &fakeCatalogClient{docs: []fakeCatalogDoc{
+		{ID: "21", Category: "guides", Slug: "zeta", Title: "Zeta", Content: "# z1"},
+		{ID: "22", Category: "guides", Slug: "zeta", Title: "Zeta", Content: "# z2"},
+		{ID: "23", Category: "guides", Slug: "alpha", Title: "Alpha", Content: "# a1"},
+		{ID: "24", Category: "guides", Slug: "alpha", Title: "Alpha", Content: "# a2"},
+	}}
+	_, err := ExportDocs(context.Background(), client, ExportOptions{
+		Name:       "aws",
+		Version:    "6.31.0",
+		OutDir:     t.TempDir(),
+		Categories: []string{"guides"},
+	})
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || !strings.Contains(vErr.Message, `duplicate slug "alpha"`) || !strings.Contains(vErr.Message, "doc_id=23 and doc_id=24") {
+		t.Fatalf("expected the alpha collision to be reported, got %v", err)
+	}
+}
+
+func TestExportDocs_DuplicateSlugInCategoryNamesBothDocIDs(t *testing.T) {
+	client := &fakeCatalogClient{docs: []fakeCatalogDoc{
+		{ID: "11", Category: "guides", Slug: "upgrade", Title: "Upgrade", Content: "# v5"},
+		{ID: "12", Category: "guides", Slug: "upgrade", Title: "Upgrade", Content: "# v6"},
+	}}
+
+	outDir := t.TempDir()
+	_, err := ExportDocs(context.Background(), client, ExportOptions{
+		Name:       "aws",
+		Version:    "6.31.0",
+		OutDir:     outDir,
+		Categories: []string{"guides"},
+	})
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) {
+		t.Fatalf("expected validation error, got %T (%v)", err, err)
+	}
+	for _, want := range []string{`duplicate slug "upgrade" in category "guides"`, "doc_id=11", "doc_id=12", "{doc_id}"} {
+		if !strings.Contains(vErr.Message, want) {
+			t.Fatalf("expected %q in error, got: %s", want, vErr.Message)
+		}
+	}
+
+	// Including {doc_id} in the template keeps both docs.
+	summary, err := ExportDocs(context.Background(), client, ExportOptions{
+		Name:         "aws",
+		Version:      "6.31.0",
+		OutDir:       outDir,
+		Categories:   []string{"guides"},
+		PathTemplate: "{out}/{category}/{slug}-{doc_id}.{ext}",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Written != 2 {
+		t.Fatalf("expected 2 docs, got %d", summary.Written)
+	}
+}
+
+// fakeConditionalClient adds If-Modified-Since support to fakeCatalogClient,
+// answering not-modified when since matches the doc's lastModified value.
+type fakeConditionalClient struct {
+	*fakeCatalogClient
+	lastModified map[string]string
+
+	sinceMu   sync.Mutex
+	sinceSent map[string]string
+}
+
+func (f *fakeConditionalClient) GetIfModifiedSince(ctx context.Context, path, since string) ([]byte, string, bool, error) {
+	id := strings.TrimPrefix(path, "/v2/provider-docs/")
+	f.sinceMu.Lock()
+	f.sinceSent[id] = since
+	f.sinceMu.Unlock()
+	if since != "" && since == f.lastModified[id] {
+		return nil, since, true, nil
+	}
+	body, err := f.Get(ctx, path)
+	return body, f.lastModified[id], false, err
+}
+
+func TestExportDocs_PreserveMtimeUsesLastModified(t *testing.T) {
+	client := &fakeConditionalClient{
+		fakeCatalogClient: &fakeCatalogClient{docs: []fakeCatalogDoc{
+			{ID: "1", Category: "guides", Slug: "getting-started", Title: "Getting Started", Content: "# guide"},
+			{ID: "2", Category: "guides", Slug: "no-header", Title: "No Header", Content: "# none"},
+		}},
+		lastModified: map[string]string{"1": "Mon, 02 Jun 2025 10:00:00 GMT"},
+		sinceSent:    map[string]string{},
+	}
+	outDir := t.TempDir()
+	started := time.Now().Add(-time.Minute)
+	summary, err := ExportDocs(context.Background(), client, ExportOptions{
+		Name:          "aws",
+		Version:       "6.31.0",
+		OutDir:        outDir,
+		Categories:    []string{"guides"},
+		PreserveMtime: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	guides := filepath.Join(outDir, "terraform", "hashicorp", "aws", "6.31.0", "docs", "guides")
+	info, err := os.Stat(filepath.Join(guides, "getting-started.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+	if !info.ModTime().Equal(want) {
+		t.Fatalf("expected mtime %v, got %v", want, info.ModTime())
+	}
+	info, err = os.Stat(filepath.Join(guides, "no-header.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ModTime().Before(started) {
+		t.Fatalf("expected doc without Last-Modified to keep its write time, got %v", info.ModTime())
+	}
+	manifestBody, err := os.ReadFile(summary.Manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(manifestBody), "last_modified") {
+		t.Fatalf("expected no last_modified in manifest without -since-modified: %s", manifestBody)
+	}
+}
+
+func TestExportDocs_SinceModifiedSkipsUnchangedDocs(t *testing.T) {
+	client := &fakeConditionalClient{
+		fakeCatalogClient: &fakeCatalogClient{docs: []fakeCatalogDoc{
+			{ID: "1", Category: "guides", Slug: "getting-started", Title: "Getting Started", Content: "# guide"},
+			{ID: "2", Category: "resources", Slug: "aws_s3_bucket", Title: "aws_s3_bucket", Content: "# bucket"},
+		}},
+		lastModified: map[string]string{
+			"1": "Mon, 02 Jun 2025 10:00:00 GMT",
+			"2": "Mon, 02 Jun 2025 11:00:00 GMT",
+		},
+		sinceSent: map[string]string{},
+	}
+	opts := ExportOptions{
+		Name:          "aws",
+		Version:       "6.31.0",
+		OutDir:        t.TempDir(),
+		Categories:    []string{"guides", "resources"},
+		SinceModified: true,
+	}
+
+	summary, err := ExportDocs(context.Background(), client, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Written != 2 || summary.Unchanged != 0 {
+		t.Fatalf("first export: expected 2 written, 0 unchanged, got %+v", summary)
+	}
+	for id, since := range client.sinceSent {
+		if since != "" {
+			t.Fatalf("first export sent If-Modified-Since %q for doc %s", since, id)
+		}
+	}
+
+	// Doc 2 changes upstream; doc 1 should come back 304 and be kept.
+	client.lastModified["2"] = "Tue, 03 Jun 2025 09:00:00 GMT"
+	client.docs[1].Content = "# bucket v2"
+	var wrote []string
+	opts.OnFileWritten = func(rel string) { wrote = append(wrote, rel) }
+
+	summary, err = ExportDocs(context.Background(), client, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Written != 1 || summary.Unchanged != 1 {
+		t.Fatalf("second export: expected 1 written, 1 unchanged, got %+v", summary)
+	}
+	if client.sinceSent["1"] != "Mon, 02 Jun 2025 10:00:00 GMT" {
+		t.Fatalf("expected recorded Last-Modified to be sent for doc 1, got %q", client.sinceSent["1"])
+	}
+	for _, rel := range wrote {
+		if strings.HasSuffix(rel, "getting-started.md") {
+			t.Fatalf("unchanged doc was rewritten: %v", wrote)
+		}
+	}
+
+	docsRoot := filepath.Join(opts.OutDir, "terraform", "hashicorp", "aws", "6.31.0", "docs")
+	if b, err := os.ReadFile(filepath.Join(docsRoot, "resources", "aws_s3_bucket.md")); err != nil || !strings.Contains(string(b), "bucket v2") {
+		t.Fatalf("expected modified doc to be rewritten, got %q (%v)", b, err)
+	}
+	b, err := os.ReadFile(filepath.Join(docsRoot, "_manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Docs) != 2 {
+		t.Fatalf("expected both docs in manifest, got %+v", m.Docs)
+	}
+	for _, doc := range m.Docs {
+		if doc.LastModified != client.lastModified[doc.DocID] {
+			t.Fatalf("doc %s: expected last_modified %q, got %q", doc.DocID, client.lastModified[doc.DocID], doc.LastModified)
+		}
+	}
+}
+
+func TestExportDocs_SinceModifiedRejectsClean(t *testing.T) {
+	_, err := ExportDocs(context.Background(), &fakeCatalogClient{}, ExportOptions{
+		Name:          "aws",
+		Version:       "6.31.0",
+		OutDir:        t.TempDir(),
+		Clean:         true,
+		SinceModified: true,
+	})
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || !strings.Contains(vErr.Message, "-since-modified") {
+		t.Fatalf("expected -since-modified validation error, got %v", err)
+	}
+}
+
+func TestExportDocs_SlugAllowAndDenyLists(t *testing.T) {
+	client := &fakeCatalogClient{docs: []fakeCatalogDoc{
+		{ID: "1", Category: "resources", Slug: "aws_instance", Title: "aws_instance", Content: "# instance"},
+		{ID: "2", Category: "resources", Slug: "aws_s3_bucket", Title: "aws_s3_bucket", Content: "# bucket"},
+		{ID: "3", Category: "resources", Slug: "aws_iam_role", Title: "aws_iam_role", Content: "# role"},
+	}}
+	export := func(allow, deny []string) []string {
+		t.Helper()
+		outDir := t.TempDir()
+		_, err := ExportDocs(context.Background(), client, ExportOptions{
+			Name:       "aws",
+			Version:    "6.31.0",
+			OutDir:     outDir,
+			Categories: []string{"resources"},
+			AllowSlugs: allow,
+			DenySlugs:  deny,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries, err := os.ReadDir(filepath.Join(outDir, "terraform", "hashicorp", "aws", "6.31.0", "docs", "resources"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		return names
+	}
+
+	if got := strings.Join(export([]string{"aws_instance", "aws_s3_bucket"}, nil), ","); got != "aws_instance.md,aws_s3_bucket.md" {
+		t.Fatalf("allowlist: unexpected files %s", got)
+	}
+	if got := strings.Join(export(nil, []string{"aws_iam_role"}), ","); got != "aws_instance.md,aws_s3_bucket.md" {
+		t.Fatalf("denylist: unexpected files %s", got)
+	}
+	if got := strings.Join(export([]string{"aws_instance", "aws_s3_bucket"}, []string{"aws_s3_bucket"}), ","); got != "aws_instance.md" {
+		t.Fatalf("deny overrides allow: unexpected files %s", got)
+	}
+	if n := client.callCount("/v2/provider-docs/3"); n != 0 {
+		t.Fatalf("expected filtered slugs to skip the detail fetch, got %d fetches", n)
+	}
+}
+
+func TestExportDocs_TrimTrailingWhitespace(t *testing.T) {
+	client := &fakeCatalogClient{docs: []fakeCatalogDoc{
+		{ID: "1", Category: "guides", Slug: "messy", Title: "Messy", Content: "# Title  \n\nbody\t \r\nlast line   "},
+	}}
+	export := func(trim bool, format string) string {
+		t.Helper()
+		outDir := t.TempDir()
+		_, err := ExportDocs(context.Background(), client, ExportOptions{
+			Name:                   "aws",
+			Version:                "6.31.0",
+			Format:                 format,
+			OutDir:                 outDir,
+			Categories:             []string{"guides"},
+			TrimTrailingWhitespace: trim,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := os.ReadFile(filepath.Join(outDir, "terraform", "hashicorp", "aws", "6.31.0", "docs", "guides", "messy."+map[string]string{"markdown": "md", "json": "json"}[format]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+
+	if got, want := export(true, "markdown"), "# Title\n\nbody\nlast line\n"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	if got, want := export(false, "markdown"), "# Title  \n\nbody\t \r\nlast line   "; got != want {
+		t.Fatalf("expected content untouched without the option, got %q", got)
+	}
+	if got := export(true, "json"); !strings.Contains(got, `last line   `) {
+		t.Fatalf("expected JSON content untouched, got %q", got)
+	}
+}
+
+func TestTrimTrailingWhitespace_CollapsesFinalNewlines(t *testing.T) {
+	if got := string(trimTrailingWhitespace([]byte("body\n\n \n"))); got != "body\n" {
+		t.Fatalf("expected a single final newline, got %q", got)
+	}
+	if got := trimTrailingWhitespace([]byte(" \n\t")); len(got) != 0 {
+		t.Fatalf("expected whitespace-only content to become empty, got %q", got)
+	}
+}
+
+func TestResolveVersion_Latest(t *testing.T) {
+	res, err := ResolveVersion(context.Background(), &fakeSearchClient{}, "HashiCorp", "aws", "latest", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Version != "6.31.0" || res.ProviderVersionID != "70800" || res.Namespace != "hashicorp" {
+		t.Fatalf("unexpected resolution: %+v", res)
+	}
+}
+
+type categoryRecordingClient struct {
+	fakeAPIClient
+	listed map[string]bool
+}
+
+func (c *categoryRecordingClient) GetJSON(ctx context.Context, path string, dst any) error {
+	if strings.HasPrefix(path, "/v2/provider-docs?") {
+		u, err := url.Parse(path)
+		if err != nil {
+			return err
+		}
+		c.listed[u.Query().Get("filter[category]")] = true
+	}
+	return c.fakeAPIClient.GetJSON(ctx, path, dst)
+}
+
+func TestCategoriesFromManifest_RefreshExportsOnlyPriorCategories(t *testing.T) {
+	outDir := t.TempDir()
+	manifestPath := filepath.Join(outDir, "_manifest.json")
+	body := `{"provider":"aws","namespace":"hashicorp","version":"6.31.0","docs":[
+		{"doc_id":"2","category":"resources","slug":"aws_s3_bucket","path":"a.md"},
+		{"doc_id":"1","category":"guides","slug":"tag-policy-compliance","path":"b.md"},
+		{"doc_id":"3","category":"resources","slug":"aws_instance","path":"c.md"}
+	]}`
+	if err := os.WriteFile(manifestPath, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cats, err := CategoriesFromManifest(manifestPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(cats, ",") != "guides,resources" {
+		t.Fatalf("unexpected categories: %v", cats)
+	}
+
+	client := &categoryRecordingClient{listed: map[string]bool{}}
+	summary, err := ExportDocs(context.Background(), client, ExportOptions{
+		Namespace:  "hashicorp",
+		Name:       "aws",
+		Version:    "6.31.0",
+		Format:     "markdown",
+		OutDir:     outDir,
+		Categories: cats,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(client.listed) != 2 || !client.listed["guides"] || !client.listed["resources"] {
+		t.Fatalf("expected only guides and resources to be listed, got %v", client.listed)
+	}
+	if summary.Written != 2 {
+		t.Fatalf("unexpected written count: %d", summary.Written)
+	}
+}
+
+func TestCategoriesFromManifest_EmptyDocsRejected(t *testing.T) {
+	manifestPath := filepath.Join(t.TempDir(), "_manifest.json")
+	if err := os.WriteFile(manifestPath, []byte(`{"docs":[]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := CategoriesFromManifest(manifestPath)
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+}
+
+func TestExportDocs_ManifestIsDeterministicAcrossRegistryOrder(t *testing.T) {
+	docs := []fakeCatalogDoc{
+		{ID: "11", Category: "resources", Slug: "aws_vpc", Title: "aws_vpc", Content: "# vpc"},
+		{ID: "12", Category: "resources", Slug: "aws_subnet", Title: "aws_subnet", Content: "# subnet"},
+		{ID: "13", Category: "guides", Slug: "getting-started", Title: "Getting Started", Content: "# start"},
+		{ID: "14", Category: "resources", Slug: "aws_instance", Title: "aws_instance", Content: "# instance"},
+	}
+	reversed := make([]fakeCatalogDoc, len(docs))
+	for i, doc := range docs {
+		reversed[len(docs)-1-i] = doc
+	}
+	fixed := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	var manifests [][]byte
+	for _, catalog := range [][]fakeCatalogDoc{docs, reversed} {
+		outDir := t.TempDir()
+		summary, err := ExportDocs(context.Background(), &fakeCatalogClient{docs: catalog}, ExportOptions{
+			Namespace:  "hashicorp",
+			Name:       "aws",
+			Version:    "6.31.0",
+			Format:     "markdown",
+			OutDir:     outDir,
+			Categories: []string{"resources", "guides"},
+			now:        func() time.Time { return fixed },
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := os.ReadFile(summary.Manifest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		manifests = append(manifests, b)
+	}
+	if !bytes.Equal(manifests[0], manifests[1]) {
+		t.Fatalf("manifests differ:\n%s\n---\n%s", manifests[0], manifests[1])
+	}
+	if !bytes.Contains(manifests[0], []byte(`"generated_at": "2024-01-02T03:04:05Z"`)) {
+		t.Fatalf("expected fixed generated_at, got %s", manifests[0])
+	}
+}
+
+func TestExportDocs_IncludeOverviewReadme(t *testing.T) {
+	outDir := t.TempDir()
+	client := &fakeCatalogClient{docs: []fakeCatalogDoc{
+		{ID: "21", Category: "overview", Slug: "changelog", Title: "Changelog", Content: "# changes"},
+		{ID: "20", Category: "overview", Slug: "index", Title: "AWS Provider", Content: "# AWS Provider\n\nUse the AWS provider."},
+		{ID: "22", Category: "resources", Slug: "aws_vpc", Title: "aws_vpc", Content: "# vpc"},
+	}}
+	summary, err := ExportDocs(context.Background(), client, ExportOptions{
+		Namespace:             "hashicorp",
+		Name:                  "aws",
+		Version:               "6.31.0",
+		Format:                "markdown",
+		OutDir:                outDir,
+		Categories:            []string{"resources"},
+		IncludeOverviewReadme: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	readme, err := os.ReadFile(filepath.Join(outDir, "terraform", "hashicorp", "aws", "6.31.0", "README.md"))
+	if err != nil {
+		t.Fatalf("expected README.md: %v", err)
+	}
+	if string(readme) != "# AWS Provider\n\nUse the AWS provider." {
+		t.Fatalf("unexpected README content: %q", readme)
+	}
+	if summary.Written != 1 {
+		t.Fatalf("expected README not to count as a doc, got written=%d", summary.Written)
+	}
+	manifestBody, err := os.ReadFile(summary.Manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(manifestBody), "README.md") {
+		t.Fatalf("expected README to stay out of the manifest: %s", manifestBody)
+	}
+}
+
+func TestExportDocs_MaxFileSizeRefusesLargeDocs(t *testing.T) {
+	outDir := t.TempDir()
+	client := &fakeCatalogClient{docs: []fakeCatalogDoc{
+		{ID: "22", Category: "resources", Slug: "aws_vpc", Title: "aws_vpc", Content: "# vpc"},
+		{ID: "23", Category: "resources", Slug: "aws_huge", Title: "aws_huge", Content: strings.Repeat("x", 2048)},
+		{ID: "24", Category: "resources", Slug: "aws_subnet", Title: "aws_subnet", Content: "# subnet"},
+	}}
+	summary, err := ExportDocs(context.Background(), client, ExportOptions{
+		Name:        "aws",
+		Version:     "6.31.0",
+		OutDir:      outDir,
+		Categories:  []string{"resources"},
+		MaxFileSize: 1024,
+	})
+	var wErr *WriteError
+	if !errors.As(err, &wErr) || !strings.HasSuffix(wErr.Path, "aws_huge.md") {
+		t.Fatalf("expected WriteError for aws_huge.md, got %T: %v", err, err)
+	}
+	if !strings.Contains(err.Error(), "over -max-file-size of 1024 bytes") {
+		t.Fatalf("unexpected error message: %v", err)
+	}
+	if summary == nil || summary.Written != 2 {
+		t.Fatalf("expected the other 2 docs to be written, got %+v", summary)
+	}
+	docs := filepath.Join(outDir, "terraform", "hashicorp", "aws", "6.31.0", "docs", "resources")
+	if _, err := os.Stat(filepath.Join(docs, "aws_huge.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected oversized doc not to be written, stat err=%v", err)
+	}
+	for _, name := range []string{"aws_vpc.md", "aws_subnet.md"} {
+		if _, err := os.Stat(filepath.Join(docs, name)); err != nil {
+			t.Fatalf("expected %s to be exported: %v", name, err)
+		}
+	}
+	manifestBody, err := os.ReadFile(summary.Manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(manifestBody), "aws_huge") {
+		t.Fatalf("expected oversized doc to stay out of the manifest: %s", manifestBody)
+	}
+}
+
+func TestExportDocs_StripBOMWithCRLF(t *testing.T) {
+	outDir := t.TempDir()
+	client := &fakeCatalogClient{docs: []fakeCatalogDoc{
+		{ID: "22", Category: "resources", Slug: "aws_vpc", Title: "aws_vpc", Content: "\xEF\xBB\xBF# vpc\r\nbody\n"},
+	}}
+	_, err := ExportDocs(context.Background(), client, ExportOptions{
+		Name:        "aws",
+		Version:     "6.31.0",
+		OutDir:      outDir,
+		Categories:  []string{"resources"},
+		StripBOM:    true,
+		LineEndings: "crlf",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(filepath.Join(outDir, "terraform", "hashicorp", "aws", "6.31.0", "docs", "resources", "aws_vpc.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "# vpc\r\nbody\r\n" {
+		t.Fatalf("expected BOM stripped and CRLF endings, got %q", b)
+	}
+}
+
+func TestExportDocs_SitemapListsExportedDocs(t *testing.T) {
+	outDir := t.TempDir()
+	client := &fakeCatalogClient{docs: []fakeCatalogDoc{
+		{ID: "22", Category: "resources", Slug: "aws_vpc", Title: "aws_vpc", Content: "# vpc"},
+		{ID: "23", Category: "resources", Slug: "aws_subnet", Title: "aws_subnet", Content: "# subnet"},
+	}}
+	_, err := ExportDocs(context.Background(), client, ExportOptions{
+		Namespace:   "hashicorp",
+		Name:        "aws",
+		Version:     "6.31.0",
+		Format:      "markdown",
+		OutDir:      outDir,
+		Categories:  []string{"resources"},
+		SitemapBase: "https://docs.example.com/tf/",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := os.ReadFile(filepath.Join(outDir, "terraform", "hashicorp", "aws", "6.31.0", "docs", "sitemap.xml"))
+	if err != nil {
+		t.Fatalf("expected sitemap.xml: %v", err)
+	}
+	var set struct {
+		XMLName xml.Name `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+		URLs    []struct {
+			Loc string `xml:"loc"`
+		} `xml:"url"`
+	}
+	if err := xml.Unmarshal(body, &set); err != nil {
+		t.Fatalf("sitemap is not well-formed: %v\n%s", err, body)
+	}
+	want := []string{
+		"https://docs.example.com/tf/terraform/hashicorp/aws/6.31.0/docs/resources/aws_subnet.md",
+		"https://docs.example.com/tf/terraform/hashicorp/aws/6.31.0/docs/resources/aws_vpc.md",
+	}
+	if len(set.URLs) != len(want) {
+		t.Fatalf("expected %d urls, got %d:\n%s", len(want), len(set.URLs), body)
+	}
+	for i, u := range set.URLs {
+		if u.Loc != want[i] {
+			t.Fatalf("url %d: expected %s, got %s", i, want[i], u.Loc)
+		}
+	}
+}
+
+func TestExportDocs_SitemapRejectsInvalidBase(t *testing.T) {
+	for _, base := range []string{"docs.example.com", "ftp://docs.example.com", "https://docs.example.com/?q=1"} {
+		_, err := ExportDocs(context.Background(), &fakeAPIClient{}, ExportOptions{
+			Name:        "aws",
+			Version:     "6.31.0",
+			OutDir:      t.TempDir(),
+			SitemapBase: base,
+		})
+		var vErr *ValidationError
+		if !errors.As(err, &vErr) {
+			t.Fatalf("%s: expected ValidationError, got %T: %v", base, err, err)
+		}
+	}
+}
+
+func TestExportDocs_IncludeOverviewReadmeCollision(t *testing.T) {
+	client := &fakeCatalogClient{docs: []fakeCatalogDoc{
+		{ID: "20", Category: "overview", Slug: "index", Title: "AWS Provider", Content: "# AWS"},
+		{ID: "23", Category: "guides", Slug: "README", Title: "Readme", Content: "# clash"},
+	}}
+	_, err := ExportDocs(context.Background(), client, ExportOptions{
+		Namespace:             "hashicorp",
+		Name:                  "aws",
+		Version:               "6.31.0",
+		Format:                "markdown",
+		OutDir:                t.TempDir(),
+		Categories:            []string{"guides"},
+		PathTemplate:          "{out}/terraform/{namespace}/{provider}/{version}/README.{ext}",
+		IncludeOverviewReadme: true,
+	})
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || !strings.Contains(vErr.Message, "overview README") {
+		t.Fatalf("expected README collision error, got %v", err)
+	}
+}
+
+type pathRecordingClient struct {
+	paths []string
+}
+
+func (c *pathRecordingClient) GetJSON(_ context.Context, path string, dst any) error {
+	c.paths = append(c.paths, path)
+	if strings.HasPrefix(path, "/v2/providers/") {
+		return json.Unmarshal([]byte(`{"included":[{"type":"provider-versions","id":"1","attributes":{"version":"1.0.0"}}]}`), dst)
+	}
+	return json.Unmarshal([]byte(`{"data":[]}`), dst)
+}
+
+func (c *pathRecordingClient) Get(_ context.Context, path string) ([]byte, error) {
+	return nil, fmt.Errorf("unexpected Get path: %s", path)
+}
+
+func TestExportDocs_PreserveCaseKeepsNamespaceInRequestPath(t *testing.T) {
+	for _, tc := range []struct {
+		preserve bool
+		want     string
+	}{
+		{preserve: true, want: "/v2/providers/MyOrg/Widget?include=provider-versions"},
+		{preserve: false, want: "/v2/providers/myorg/widget?include=provider-versions"},
+	} {
+		client := &pathRecordingClient{}
+		outDir := t.TempDir()
+		_, err := ExportDocs(context.Background(), client, ExportOptions{
+			Namespace:    "MyOrg",
+			Name:         "Widget",
+			Version:      "1.0.0",
+			Format:       "markdown",
+			OutDir:       outDir,
+			Categories:   []string{"guides"},
+			PreserveCase: tc.preserve,
+		})
+		if err != nil {
+			t.Fatalf("preserve=%v: %v", tc.preserve, err)
+		}
+		if len(client.paths) == 0 || client.paths[0] != tc.want {
+			t.Fatalf("preserve=%v: expected first request %s, got %v", tc.preserve, tc.want, client.paths)
+		}
+		// Output path segments are sanitized regardless.
+		if _, err := os.Stat(filepath.Join(outDir, "terraform", "myorg", "widget", "1.0.0", "docs", "_manifest.json")); err != nil {
+			t.Fatalf("preserve=%v: expected lowercased output layout: %v", tc.preserve, err)
+		}
+
+		// -resolve-only goes through ResolveVersion and must match.
+		client = &pathRecordingClient{}
+		if _, err := ResolveVersion(context.Background(), client, "MyOrg", "Widget", "1.0.0", tc.preserve); err != nil {
+			t.Fatalf("preserve=%v: resolve: %v", tc.preserve, err)
+		}
+		if len(client.paths) == 0 || client.paths[0] != tc.want {
+			t.Fatalf("preserve=%v: expected resolve request %s, got %v", tc.preserve, tc.want, client.paths)
+		}
+	}
+}
+
+func TestExportDocs_PreserveCaseStillRejectsInvalidIdentifiers(t *testing.T) {
+	_, err := ExportDocs(context.Background(), &pathRecordingClient{}, ExportOptions{
+		Namespace:    "My_Org",
+		Name:         "aws",
+		Version:      "1.0.0",
+		OutDir:       t.TempDir(),
+		PreserveCase: true,
+	})
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+}
+
+func TestExportDocs_ErrorOnEmptyContent(t *testing.T) {
+	docs := []fakeCatalogDoc{
+		{ID: "31", Category: "resources", Slug: "aws_vpc", Title: "aws_vpc", Content: "# vpc"},
+		{ID: "32", Category: "resources", Slug: "aws_blank", Title: "aws_blank", Content: " \n"},
+	}
+	for _, format := range []string{"markdown", "json"} {
+		opts := ExportOptions{
+			Namespace:  "hashicorp",
+			Name:       "aws",
+			Version:    "6.31.0",
+			Format:     format,
+			OutDir:     t.TempDir(),
+			Categories: []string{"resources"},
+		}
+		if _, err := ExportDocs(context.Background(), &fakeCatalogClient{docs: docs}, opts); err != nil {
+			t.Fatalf("%s: expected empty content to be allowed by default: %v", format, err)
+		}
+
+		opts.OutDir = t.TempDir()
+		opts.ErrorOnEmptyContent = true
+		_, err := ExportDocs(context.Background(), &fakeCatalogClient{docs: docs}, opts)
+		var wErr *WriteError
+		if !errors.As(err, &wErr) {
+			t.Fatalf("%s: expected WriteError, got %v", format, err)
+		}
+		if !strings.Contains(err.Error(), "doc 32 (resources/aws_blank) has empty content") {
+			t.Fatalf("%s: expected error naming the doc, got %v", format, err)
+		}
+	}
+}
+
+func TestExportDocs_NoEmptyManifestSkipsManifestWhenNothingExported(t *testing.T) {
+	client := &fakeCatalogClient{docs: []fakeCatalogDoc{
+		{ID: "1", Category: "guides", Slug: "intro", Title: "Intro", Content: "# Intro"},
+	}}
+	for _, noEmpty := range []bool{false, true} {
+		outDir := t.TempDir()
+		opts := ExportOptions{
+			Namespace:       "hashicorp",
+			Name:            "aws",
+			Version:         "6.31.0",
+			Format:          "markdown",
+			OutDir:          outDir,
+			Categories:      []string{"functions"},
+			NoEmptyManifest: noEmpty,
+		}
+		summary, err := ExportDocs(context.Background(), client, opts)
+		if err != nil {
+			t.Fatalf("NoEmptyManifest=%v: unexpected error: %v", noEmpty, err)
+		}
+		_, statErr := os.Stat(manifestPathForOptions(opts))
+		if noEmpty {
+			if !os.IsNotExist(statErr) || summary.Manifest != "" {
+				t.Fatalf("expected no manifest, got stat error %v and summary manifest %q", statErr, summary.Manifest)
+			}
+			continue
+		}
+		if statErr != nil || summary.Manifest == "" {
+			t.Fatalf("expected the empty manifest to be written by default, got %v", statErr)
+		}
+	}
+}
+
+// flakyDetailClient fails the first failures detail fetches with a
+// transient error before serving them from fakeCatalogClient.
+type flakyDetailClient struct {
+	*fakeCatalogClient
+	failures int
+
+	attemptsMu sync.Mutex
+	attempts   int
+}
+
+func (f *flakyDetailClient) Get(ctx context.Context, path string) ([]byte, error) {
+	if strings.HasPrefix(path, "/v2/provider-docs/") {
+		f.attemptsMu.Lock()
+		f.attempts++
+		failed := f.attempts <= f.failures
+		f.attemptsMu.Unlock()
+		if failed {
+			return nil, fmt.Errorf("GET %s: 500 Internal Server Error", path)
+		}
+	}
+	return f.fakeCatalogClient.Get(ctx, path)
+}
+
+func TestExportDocs_DetailRetriesRecoverFromTransientFailures(t *testing.T) {
+	old := detailRetryBackoff
+	detailRetryBackoff = 0
+	defer func() { detailRetryBackoff = old }()
+
+	docs := []fakeCatalogDoc{{ID: "1", Category: "guides", Slug: "intro", Title: "Intro", Content: "# Intro"}}
+	opts := ExportOptions{
+		Name:          "aws",
+		Version:       "6.31.0",
+		OutDir:        t.TempDir(),
+		Categories:    []string{"guides"},
+		DetailRetries: 2,
+	}
+
+	client := &flakyDetailClient{fakeCatalogClient: &fakeCatalogClient{docs: docs}, failures: 2}
+	summary, err := ExportDocs(context.Background(), client, opts)
+	if err != nil {
+		t.Fatalf("expected the export to complete after retries: %v", err)
+	}
+	if summary.Written != 1 || client.attempts != 3 {
+		t.Fatalf("expected 1 doc after 3 detail attempts, got written=%d attempts=%d", summary.Written, client.attempts)
+	}
+
+	client = &flakyDetailClient{fakeCatalogClient: &fakeCatalogClient{docs: docs}, failures: 3}
+	if _, err := ExportDocs(context.Background(), client, opts); err == nil || client.attempts != 3 {
+		t.Fatalf("expected failure after exhausting 2 retries, got err=%v attempts=%d", err, client.attempts)
+	}
+}
+
+// fakeStatusError mimics *registry.APIError for a failed request.
+type fakeStatusError struct{ status int }
+
+func (e *fakeStatusError) Error() string {
+	return fmt.Sprintf("registry API error: status=%d", e.status)
+}
+func (e *fakeStatusError) HTTPStatus() int { return e.status }
+
+// forbiddenCategoryClient answers listings of one category with 403.
+type forbiddenCategoryClient struct {
+	*fakeCatalogClient
+	forbidden string
+}
+
+func (f *forbiddenCategoryClient) GetJSON(ctx context.Context, path string, dst any) error {
+	if strings.Contains(path, "filter%5Bcategory%5D="+f.forbidden+"&") {
+		return &fakeStatusError{status: http.StatusForbidden}
+	}
+	return f.fakeCatalogClient.GetJSON(ctx, path, dst)
+}
+
+func TestExportDocs_SkipForbiddenSkipsCategory(t *testing.T) {
+	client := &forbiddenCategoryClient{
+		fakeCatalogClient: &fakeCatalogClient{docs: []fakeCatalogDoc{
+			{ID: "1", Category: "resources", Slug: "aws_s3_bucket", Title: "aws_s3_bucket", Content: "# bucket"},
+			{ID: "2", Category: "guides", Slug: "intro", Title: "Intro", Content: "# Intro"},
+		}},
+		forbidden: "functions",
+	}
+	opts := ExportOptions{
+		Name:       "aws",
+		Version:    "6.31.0",
+		OutDir:     t.TempDir(),
+		Categories: []string{"resources", "functions", "guides"},
+	}
+
+	var fsErr *fakeStatusError
+	if _, err := ExportDocs(context.Background(), client, opts); !errors.As(err, &fsErr) {
+		t.Fatalf("expected the 403 to fail the export by default, got %v", err)
+	}
+
+	var warnings []string
+	opts.SkipForbidden = true
+	opts.OnWarning = func(msg string) { warnings = append(warnings, msg) }
+	summary, err := ExportDocs(context.Background(), client, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary.Written != 2 {
+		t.Fatalf("expected the other categories to export 2 docs, got %d", summary.Written)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "skipping category functions") {
+		t.Fatalf("expected one warning for functions, got %v", warnings)
+	}
+	if len(summary.SkippedCategories) != 1 || summary.SkippedCategories[0] != "functions" {
+		t.Fatalf("expected functions in skipped_categories, got %v", summary.SkippedCategories)
+	}
+}
+
+// concurrentDetailClient serves fakeCatalogClient docs, holding detail
+// fetches until hold of them have been in flight at once (or a timeout
+// passes) so tests can observe how many run concurrently. failID, when
+// set, fails that doc's fetch.
+type concurrentDetailClient struct {
+	*fakeCatalogClient
+	hold   int
+	failID string
+
+	inFlightMu  sync.Mutex
+	inFlight    int
+	maxInFlight int
+	canceled    int
+}
+
+func (c *concurrentDetailClient) Get(ctx context.Context, path string) ([]byte, error) {
+	if !strings.HasPrefix(path, "/v2/provider-docs/") {
+		return c.fakeCatalogClient.Get(ctx, path)
+	}
+	if strings.TrimPrefix(path, "/v2/provider-docs/") == c.failID {
+		return nil, fmt.Errorf("GET %s: 500 Internal Server Error", path)
+	}
+	c.inFlightMu.Lock()
+	c.inFlight++
+	c.maxInFlight = max(c.maxInFlight, c.inFlight)
+	c.inFlightMu.Unlock()
+	defer func() {
+		c.inFlightMu.Lock()
+		c.inFlight--
+		c.inFlightMu.Unlock()
+	}()
+
+	deadline := time.After(time.Second)
+	for {
+		c.inFlightMu.Lock()
+		reached := c.maxInFlight >= c.hold
+		c.inFlightMu.Unlock()
+		if reached {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			c.inFlightMu.Lock()
+			c.canceled++
+			c.inFlightMu.Unlock()
+			return nil, ctx.Err()
+		case <-deadline:
+			return c.fakeCatalogClient.Get(ctx, path)
+		case <-time.After(time.Millisecond):
+		}
+	}
+	return c.fakeCatalogClient.Get(ctx, path)
+}
+
+func concurrentExportDocs(n int) []fakeCatalogDoc {
+	docs := make([]fakeCatalogDoc, n)
+	for i := range docs {
+		docs[i] = fakeCatalogDoc{ID: fmt.Sprint(100 + i), Category: "resources", Slug: fmt.Sprintf("aws_r%02d", n-i), Title: "R", Content: fmt.Sprintf("# doc %d", i)}
+	}
+	return docs
+}
+
+func TestExportDocs_ConcurrencyBoundsFetchesAndKeepsManifestStable(t *testing.T) {
+	docs := concurrentExportDocs(20)
+	export := func(concurrency int) (*concurrentDetailClient, []manifestItem) {
+		t.Helper()
+		client := &concurrentDetailClient{fakeCatalogClient: &fakeCatalogClient{docs: docs}, hold: concurrency}
+		summary, err := ExportDocs(context.Background(), client, ExportOptions{
+			Name:        "aws",
+			Version:     "6.31.0",
+			OutDir:      t.TempDir(),
+			Categories:  []string{"resources"},
+			Concurrency: concurrency,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if summary.Written != len(docs) {
+			t.Fatalf("concurrency %d: expected %d docs, got %d", concurrency, len(docs), summary.Written)
+		}
+		m, err := readManifestFile(summary.Manifest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return client, m.Docs
+	}
+
+	parallel, parallelDocs := export(4)
+	if parallel.maxInFlight != 4 {
+		t.Fatalf("expected 4 detail fetches in flight at once, got %d", parallel.maxInFlight)
+	}
+	sequential, sequentialDocs := export(1)
+	if sequential.maxInFlight != 1 {
+		t.Fatalf("expected sequential fetches with concurrency 1, got %d in flight", sequential.maxInFlight)
+	}
+	if !reflect.DeepEqual(parallelDocs, sequentialDocs) {
+		t.Fatalf("expected the same manifest docs at any concurrency:\n%v\n%v", parallelDocs, sequentialDocs)
+	}
+}
+
+func TestExportDocs_ConcurrentFetchFailureCancelsTheRest(t *testing.T) {
+	outDir := t.TempDir()
+	client := &concurrentDetailClient{fakeCatalogClient: &fakeCatalogClient{docs: concurrentExportDocs(20)}, hold: 100, failID: "103"}
+	_, err := ExportDocs(context.Background(), client, ExportOptions{
+		Name:        "aws",
+		Version:     "6.31.0",
+		OutDir:      outDir,
+		Categories:  []string{"resources"},
+		Concurrency: 4,
+	})
+	if err == nil || !strings.Contains(err.Error(), "/v2/provider-docs/103: 500") {
+		t.Fatalf("expected the failing fetch's error, got %v", err)
+	}
+	if client.canceled == 0 {
+		t.Fatalf("expected in-flight fetches to be canceled")
+	}
+	if got := client.callCount("/v2/provider-docs/1"); got >= 20 {
+		t.Fatalf("expected later docs not to be fetched, got %d detail fetches", got)
+	}
+	if entries, _ := os.ReadDir(outDir); len(entries) != 0 {
+		t.Fatalf("expected nothing to be written, found %d entries", len(entries))
+	}
+}
+
+func TestExportDocs_RejectsNegativeConcurrency(t *testing.T) {
+	_, err := ExportDocs(context.Background(), &fakeAPIClient{}, ExportOptions{
+		Name:        "aws",
+		Version:     "6.31.0",
+		OutDir:      t.TempDir(),
+		Concurrency: -1,
+	})
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || vErr.Message != "-concurrency must be >= 1" {
+		t.Fatalf("expected concurrency validation error, got %v", err)
+	}
+}
+
+func TestExportDocs_CompactManifestIsSingleLineJSON(t *testing.T) {
+	fixed := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	client := &fakeCatalogClient{docs: []fakeCatalogDoc{
+		{ID: "1", Category: "guides", Slug: "intro", Title: "Intro", Content: "# Intro"},
+		{ID: "2", Category: "guides", Slug: "setup", Title: "Setup", Content: "# Setup"},
+	}}
+	export := func(compact bool) []byte {
+		t.Helper()
+		opts := ExportOptions{
+			Name:            "aws",
+			Version:         "6.31.0",
+			OutDir:          t.TempDir(),
+			Categories:      []string{"guides"},
+			CompactManifest: compact,
+			now:             func() time.Time { return fixed },
+		}
+		summary, err := ExportDocs(context.Background(), client, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := os.ReadFile(summary.Manifest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	compact := export(true)
+	if !json.Valid(compact) {
+		t.Fatalf("expected valid JSON, got %s", compact)
+	}
+	if bytes.Count(compact, []byte("\n")) != 1 || !bytes.HasSuffix(compact, []byte("}\n")) {
+		t.Fatalf("expected a single line ending in a newline, got %q", compact)
+	}
+
+	var want bytes.Buffer
+	if err := json.Compact(&want, export(false)); err != nil {
+		t.Fatal(err)
+	}
+	if got := bytes.TrimSuffix(compact, []byte("\n")); !bytes.Equal(got, want.Bytes()) {
+		t.Fatalf("expected the compact manifest to match the pretty one:\n%s\n%s", got, want.Bytes())
+	}
+	if !bytes.Equal(compact, export(true)) {
+		t.Fatalf("expected compact manifests to be reproducible")
+	}
+}
+
+// latestVersionClient adds the v1 provider endpoint to fakeCatalogClient,
+// reporting 6.31.0 as the latest aws version and 404 for other providers.
+type latestVersionClient struct {
+	*fakeCatalogClient
+}
+
+func (c *latestVersionClient) GetJSON(ctx context.Context, path string, dst any) error {
+	if strings.HasPrefix(path, "/v1/providers/") {
+		c.record(path)
+		if path != "/v1/providers/hashicorp/aws" {
+			return &fakeStatusError{status: http.StatusNotFound}
+		}
+		return json.Unmarshal([]byte(`{"version":"6.31.0"}`), dst)
+	}
+	return c.fakeCatalogClient.GetJSON(ctx, path, dst)
+}
+
+func TestExportDocs_ResolvesLatestVersionWhenVersionIsEmpty(t *testing.T) {
+	client := &latestVersionClient{&fakeCatalogClient{docs: []fakeCatalogDoc{
+		{ID: "1", Category: "guides", Slug: "intro", Title: "Intro", Content: "# Intro"},
+	}}}
+	outDir := t.TempDir()
+	summary, err := ExportDocs(context.Background(), client, ExportOptions{
+		Name:       "aws",
+		OutDir:     outDir,
+		Categories: []string{"guides"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Version != "6.31.0" || summary.Written != 1 {
+		t.Fatalf("expected 1 doc exported for 6.31.0, got %+v", summary)
+	}
+	if client.callCount("/v2/providers/hashicorp/aws") != 1 {
+		t.Fatalf("expected the provider-version ID to be resolved once, calls=%v", client.calls)
+	}
+	docsDir := filepath.Join(outDir, "terraform", "hashicorp", "aws", "6.31.0", "docs")
+	if _, err := os.Stat(filepath.Join(docsDir, "guides", "intro.md")); err != nil {
+		t.Fatalf("expected the doc under the resolved version: %v", err)
+	}
+	m, err := readManifestFile(filepath.Join(docsDir, "_manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Version != "6.31.0" {
+		t.Fatalf("expected manifest version 6.31.0, got %q", m.Version)
+	}
+
+	_, err = ExportDocs(context.Background(), client, ExportOptions{
+		Name:       "nope",
+		Version:    "latest",
+		OutDir:     t.TempDir(),
+		Categories: []string{"guides"},
+	})
+	var nfErr *NotFoundError
+	if !errors.As(err, &nfErr) || nfErr.Message != "no version found for hashicorp/nope" {
+		t.Fatalf("expected NotFoundError for an unknown provider, got %v", err)
+	}
+}
+
+func TestExportDocs_IncrementalSkipsUnchangedDocs(t *testing.T) {
+	docs := []fakeCatalogDoc{
+		{ID: "1", Category: "guides", Slug: "intro", Title: "Intro", Content: "# Intro"},
+		{ID: "2", Category: "guides", Slug: "setup", Title: "Setup", Content: "# Setup"},
+		{ID: "3", Category: "guides", Slug: "usage", Title: "Usage", Content: "# Usage"},
+	}
+	outDir := t.TempDir()
+	opts := ExportOptions{
+		Name:        "aws",
+		Version:     "6.31.0",
+		OutDir:      outDir,
+		Categories:  []string{"guides"},
+		Incremental: true,
+	}
+	first, err := ExportDocs(context.Background(), &fakeCatalogClient{docs: docs}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Written != 3 || first.Skipped != 0 {
+		t.Fatalf("expected a first export to write every doc, got %+v", first)
+	}
+
+	guidesDir := filepath.Join(outDir, "terraform", "hashicorp", "aws", "6.31.0", "docs", "guides")
+	old := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, slug := range []string{"intro", "setup", "usage"} {
+		if err := os.Chtimes(filepath.Join(guidesDir, slug+".md"), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// setup changes upstream; usage was edited locally since the last export.
+	docs[1].Content = "# Setup v2"
+	if err := os.WriteFile(filepath.Join(guidesDir, "usage.md"), []byte("local edit"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(filepath.Join(guidesDir, "usage.md"), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := ExportDocs(context.Background(), &fakeCatalogClient{docs: docs}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Written != 2 || second.Skipped != 1 {
+		t.Fatalf("expected 2 written and 1 skipped, got %+v", second)
+	}
+	for slug, wantKept := range map[string]bool{"intro": true, "setup": false, "usage": false} {
+		info, err := os.Stat(filepath.Join(guidesDir, slug+".md"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if kept := info.ModTime().Equal(old); kept != wantKept {
+			t.Fatalf("%s: expected mtime kept=%v, got %v", slug, wantKept, info.ModTime())
+		}
+	}
+	if b, _ := os.ReadFile(filepath.Join(guidesDir, "usage.md")); string(b) != "# Usage" {
+		t.Fatalf("expected the locally edited doc to be restored, got %q", b)
+	}
+	m, err := readManifestFile(second.Manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Docs) != 3 {
+		t.Fatalf("expected every doc in the manifest, got %d", len(m.Docs))
+	}
+	for _, doc := range m.Docs {
+		if doc.SHA256 == "" {
+			t.Fatalf("expected a sha256 for %s", doc.Slug)
+		}
+	}
+
+	opts.Clean = true
+	var vErr *ValidationError
+	if _, err := ExportDocs(context.Background(), &fakeCatalogClient{docs: docs}, opts); !errors.As(err, &vErr) {
+		t.Fatalf("expected -incremental with -clean to be rejected, got %v", err)
+	}
+}
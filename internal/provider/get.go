@@ -14,14 +14,34 @@ type GetResult struct {
 	ContentType string
 }
 
+// ContentTypeGetter is implemented by clients that can report the media
+// type of a fetched response, such as *registry.Client.
+type ContentTypeGetter interface {
+	GetWithContentType(ctx context.Context, path string) ([]byte, string, error)
+}
+
+// contentTypeRecorder routes Get through a ContentTypeGetter and keeps the
+// media type of the last successful response.
+type contentTypeRecorder struct {
+	APIClient
+	getter      ContentTypeGetter
+	contentType string
+}
+
+func (r *contentTypeRecorder) Get(ctx context.Context, path string) ([]byte, error) {
+	b, contentType, err := r.getter.GetWithContentType(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	r.contentType = contentType
+	return b, nil
+}
+
 // GetDoc fetches a single provider doc by numeric ID.
 func GetDoc(ctx context.Context, client APIClient, docID string) (*GetResult, error) {
-	docID = strings.TrimSpace(docID)
-	if docID == "" {
-		return nil, &ValidationError{Message: "-doc-id is required"}
-	}
-	if _, err := strconv.Atoi(docID); err != nil {
-		return nil, &ValidationError{Message: fmt.Sprintf("-doc-id must be numeric: %s", docID)}
+	docID, err := validateDocID(docID)
+	if err != nil {
+		return nil, err
 	}
 
 	detail, _, err := getProviderDocDetail(ctx, client, docID, false)
@@ -35,3 +55,57 @@ func GetDoc(ctx context.Context, client APIClient, docID string) (*GetResult, er
 		ContentType: "text/markdown",
 	}, nil
 }
+
+// GetDocRaw fetches a single provider doc by numeric ID and returns the raw
+// API document as content. The content type is taken from the response when
+// the client implements ContentTypeGetter and defaults to application/json
+// otherwise.
+func GetDocRaw(ctx context.Context, client APIClient, docID string) (*GetResult, error) {
+	docID, err := validateDocID(docID)
+	if err != nil {
+		return nil, err
+	}
+
+	var recorder *contentTypeRecorder
+	if getter, ok := client.(ContentTypeGetter); ok {
+		recorder = &contentTypeRecorder{APIClient: client, getter: getter}
+		client = recorder
+	}
+	detail, raw, err := getProviderDocDetail(ctx, client, docID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := "application/json"
+	if recorder != nil && recorder.contentType != "" {
+		contentType = recorder.contentType
+	}
+
+	return &GetResult{
+		ID:          detail.Data.ID,
+		Content:     string(raw),
+		ContentType: contentType,
+	}, nil
+}
+
+func validateDocID(docID string) (string, error) {
+	docID = bareDocID(strings.TrimSpace(docID))
+	if docID == "" {
+		return "", &ValidationError{Message: "-doc-id is required"}
+	}
+	if _, err := strconv.Atoi(docID); err != nil {
+		return "", &ValidationError{Message: fmt.Sprintf("-doc-id must be numeric: %s", docID)}
+	}
+	return docID, nil
+}
+
+// bareDocID strips the namespace/name/version# prefix of a qualified doc ID,
+// as printed by provider search -id-format qualified, leaving the numeric ID.
+// Other values are returned unchanged.
+func bareDocID(docID string) string {
+	prefix, id, ok := strings.Cut(docID, "#")
+	if !ok || strings.Count(prefix, "/") != 2 {
+		return docID
+	}
+	return id
+}
@@ -61,3 +61,22 @@ func TestGetDoc_NonNumericDocID(t *testing.T) {
 		t.Errorf("expected error about numeric, got: %v", err)
 	}
 }
+
+func TestGetDoc_AcceptsQualifiedDocID(t *testing.T) {
+	qualified := SearchResult{ProviderDocID: "8894603", Provider: "aws", Namespace: "hashicorp", Version: "6.31.0"}.QualifiedID()
+	if qualified != "hashicorp/aws/6.31.0#8894603" {
+		t.Fatalf("unexpected qualified ID %q", qualified)
+	}
+	result, err := GetDoc(context.Background(), &fakeGetClient{}, qualified)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ID != "8894603" {
+		t.Fatalf("expected id 8894603, got %s", result.ID)
+	}
+
+	var vErr *ValidationError
+	if _, err := GetDoc(context.Background(), &fakeGetClient{}, "aws#8894603"); !errors.As(err, &vErr) {
+		t.Fatalf("expected a malformed qualified ID to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Hash algorithms for the per-doc digests recorded in the manifest.
+const (
+	HashSHA256 = "sha256"
+	HashSHA512 = "sha512"
+)
+
+// normalizeHashAlgorithm validates a -hash-algo value. Empty means sha256,
+// which is also what manifests without a hash_algorithm field used.
+func normalizeHashAlgorithm(s string) (string, error) {
+	switch algo := strings.ToLower(strings.TrimSpace(s)); algo {
+	case "":
+		return HashSHA256, nil
+	case HashSHA256, HashSHA512:
+		return algo, nil
+	default:
+		return "", &ValidationError{Message: fmt.Sprintf("unsupported -hash-algo: %s (valid: sha256, sha512)", s)}
+	}
+}
+
+// hashHex returns the hex digest of b under algo, which must already be
+// normalized.
+func hashHex(algo string, b []byte) string {
+	if algo == HashSHA512 {
+		sum := sha512.Sum512(b)
+		return hex.EncodeToString(sum[:])
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// digest returns the recorded digest for algo, or "" when none was recorded.
+func (m manifestItem) digest(algo string) string {
+	if algo == HashSHA512 {
+		return m.SHA512
+	}
+	return m.SHA256
+}
+
+// setDigest records the digest of content under algo, clearing the other.
+func (m *manifestItem) setDigest(algo string, content []byte) {
+	m.SHA256, m.SHA512 = "", ""
+	if algo == HashSHA512 {
+		m.SHA512 = hashHex(algo, content)
+		return
+	}
+	m.SHA256 = hashHex(algo, content)
+}
@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"sync"
+)
+
+// LinkIndex remembers the first file written for each content digest so
+// later identical files can be hardlinked to it. A single index can be shared
+// across ExportDocs calls to deduplicate docs between provider versions.
+type LinkIndex struct {
+	mu    sync.Mutex
+	paths map[string]string
+}
+
+// NewLinkIndex returns an empty LinkIndex.
+func NewLinkIndex() *LinkIndex {
+	return &LinkIndex{paths: make(map[string]string)}
+}
+
+// lookupOrStore returns the previously recorded path for digest, or records
+// path as the canonical copy and returns "".
+func (idx *LinkIndex) lookupOrStore(digest, path string) string {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if existing, ok := idx.paths[digest]; ok {
+		return existing
+	}
+	idx.paths[digest] = path
+	return ""
+}
+
+// writeLinkedFile writes content to path, hardlinking to an earlier
+// byte-identical file from idx when possible. It falls back to a regular
+// write when linking fails (cross-device, unsupported filesystem, ...).
+// linked reports whether path now shares an inode with an earlier file.
+func writeLinkedFile(idx *LinkIndex, path string, content []byte) (linked bool, err error) {
+	if err := removeExisting(path); err != nil {
+		return false, err
+	}
+
+	source := idx.lookupOrStore(hashHex(HashSHA256, content), path)
+	if source != "" && source != path && sameContent(source, content) {
+		if err := os.Link(source, path); err == nil {
+			return true, nil
+		}
+	}
+	return false, os.WriteFile(path, content, 0o644)
+}
+
+// replaceFile writes content to a fresh file at path. The destination is
+// removed first instead of truncated in place because it may be a hardlink
+// left by an earlier -hardlink-identical run, and truncating it would
+// rewrite every linked copy.
+func replaceFile(path string, content []byte) error {
+	if err := removeExisting(path); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0o644)
+}
+
+func removeExisting(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func sameContent(path string, content []byte) bool {
+	existing, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(existing, content)
+}
@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ListOptions holds parameters for listing the providers of a namespace.
+type ListOptions struct {
+	Namespace string
+	Limit     int
+	Tier      string // official, partner, or community; empty lists every tier
+}
+
+// ProviderSummary describes one provider published under a namespace.
+type ProviderSummary struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+	Tier        string `json:"tier"`
+	Description string `json:"description"`
+	Downloads   int64  `json:"downloads"`
+}
+
+// providerListResponse is the response from GET /v2/providers?filter[namespace]=...
+type providerListResponse struct {
+	Data []struct {
+		ID         string `json:"id"`
+		Attributes struct {
+			Name        string `json:"name"`
+			Namespace   string `json:"namespace"`
+			Tier        string `json:"tier"`
+			Description string `json:"description"`
+			Downloads   int64  `json:"downloads"`
+		} `json:"attributes"`
+	} `json:"data"`
+	Meta struct {
+		Pagination *struct {
+			NextPage *int `json:"next-page"`
+		} `json:"pagination"`
+	} `json:"meta"`
+}
+
+// ListProviders lists the providers published under a namespace, following
+// pagination until opts.Limit providers are collected or pages run out.
+func ListProviders(ctx context.Context, client APIClient, opts ListOptions) ([]ProviderSummary, error) {
+	opts.Namespace = strings.ToLower(strings.TrimSpace(opts.Namespace))
+	if opts.Namespace == "" {
+		return nil, &ValidationError{Message: "-namespace is required"}
+	}
+	if err := validateProviderIdentifier("-namespace", opts.Namespace, false); err != nil {
+		return nil, err
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = 20
+	}
+	tier, err := normalizeTier(opts.Tier)
+	if err != nil {
+		return nil, err
+	}
+	opts.Tier = tier
+
+	seen := make(map[string]struct{})
+	var results []ProviderSummary
+	for page := 1; ; page++ {
+		q := url.Values{}
+		q.Set("filter[namespace]", opts.Namespace)
+		q.Set("page[number]", fmt.Sprintf("%d", page))
+
+		var resp providerListResponse
+		if err := client.GetJSON(ctx, "/v2/providers?"+q.Encode(), &resp); err != nil {
+			return nil, err
+		}
+		if len(resp.Data) == 0 {
+			break
+		}
+
+		newOnPage := 0
+		for _, p := range resp.Data {
+			if _, exists := seen[p.ID]; exists {
+				continue
+			}
+			seen[p.ID] = struct{}{}
+			newOnPage++
+			if opts.Tier != "" && !strings.EqualFold(p.Attributes.Tier, opts.Tier) {
+				continue
+			}
+			namespace := p.Attributes.Namespace
+			if namespace == "" {
+				namespace = opts.Namespace
+			}
+			results = append(results, ProviderSummary{
+				ID:          p.ID,
+				Name:        p.Attributes.Name,
+				Namespace:   namespace,
+				Tier:        p.Attributes.Tier,
+				Description: p.Attributes.Description,
+				Downloads:   p.Attributes.Downloads,
+			})
+			if len(results) >= opts.Limit {
+				return results, nil
+			}
+		}
+		// Stop when the registry says there is no next page, or when it
+		// ignores page[number] and keeps returning the same providers.
+		if newOnPage == 0 || (resp.Meta.Pagination != nil && resp.Meta.Pagination.NextPage == nil) {
+			break
+		}
+	}
+	return results, nil
+}
+
+// providerTiers are the registry's provider tiers accepted by -tier.
+var providerTiers = []string{"official", "partner", "community"}
+
+// normalizeTier lowercases a -tier value and checks it is a known tier.
+// An empty value means no tier filter.
+func normalizeTier(tier string) (string, error) {
+	tier = strings.ToLower(strings.TrimSpace(tier))
+	if tier == "" {
+		return "", nil
+	}
+	for _, t := range providerTiers {
+		if tier == t {
+			return tier, nil
+		}
+	}
+	return "", &ValidationError{Message: fmt.Sprintf("unsupported -tier: %s (valid: %s)", tier, strings.Join(providerTiers, ", "))}
+}
@@ -0,0 +1,125 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// fakeProviderListClient serves two pages of providers for the hashicorp
+// namespace, the second holding a partner-tier provider, and records the namespace filters it was asked for.
+type fakeProviderListClient struct {
+	namespaces []string
+}
+
+func (f *fakeProviderListClient) GetJSON(_ context.Context, path string, dst any) error {
+	if !strings.HasPrefix(path, "/v2/providers?") {
+		return fmt.Errorf("unexpected GetJSON path: %s", path)
+	}
+	u, err := url.Parse(path)
+	if err != nil {
+		return err
+	}
+	q := u.Query()
+	f.namespaces = append(f.namespaces, q.Get("filter[namespace]"))
+
+	var body string
+	switch q.Get("page[number]") {
+	case "1":
+		body = `{"data":[
+			{"id":"323","attributes":{"name":"aws","namespace":"hashicorp","tier":"official","downloads":100}},
+			{"id":"324","attributes":{"name":"azurerm","namespace":"hashicorp","tier":"official","downloads":50}}
+		],"meta":{"pagination":{"current-page":1,"next-page":2}}}`
+	case "2":
+		body = `{"data":[
+			{"id":"325","attributes":{"name":"google","namespace":"hashicorp","tier":"partner","downloads":75}}
+		],"meta":{"pagination":{"current-page":2,"next-page":null}}}`
+	default:
+		return fmt.Errorf("unexpected page: %s", q.Get("page[number]"))
+	}
+	return json.Unmarshal([]byte(body), dst)
+}
+
+func (f *fakeProviderListClient) Get(_ context.Context, path string) ([]byte, error) {
+	return nil, fmt.Errorf("unexpected Get path: %s", path)
+}
+
+func TestListProviders_FollowsPagination(t *testing.T) {
+	client := &fakeProviderListClient{}
+	results, err := ListProviders(context.Background(), client, ListOptions{Namespace: "HashiCorp"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, r := range results {
+		names = append(names, r.Name)
+	}
+	if got := strings.Join(names, ","); got != "aws,azurerm,google" {
+		t.Fatalf("unexpected providers: %s", got)
+	}
+	if results[0].Namespace != "hashicorp" || results[0].Tier != "official" || results[0].Downloads != 100 {
+		t.Fatalf("unexpected first provider: %+v", results[0])
+	}
+	if got := strings.Join(client.namespaces, ","); got != "hashicorp,hashicorp" {
+		t.Fatalf("expected two lowercase namespace-filtered requests, got %s", got)
+	}
+}
+
+func TestListProviders_StopsAtLimit(t *testing.T) {
+	client := &fakeProviderListClient{}
+	results, err := ListProviders(context.Background(), client, ListOptions{Namespace: "hashicorp", Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 providers, got %d", len(results))
+	}
+	if len(client.namespaces) != 1 {
+		t.Fatalf("expected a single page request, got %d", len(client.namespaces))
+	}
+}
+
+func TestListProviders_ValidatesNamespace(t *testing.T) {
+	for _, ns := range []string{"", "bad/ns"} {
+		_, err := ListProviders(context.Background(), &fakeProviderListClient{}, ListOptions{Namespace: ns})
+		var vErr *ValidationError
+		if !errors.As(err, &vErr) || !strings.Contains(vErr.Message, "-namespace") {
+			t.Fatalf("namespace %q: expected -namespace validation error, got %v", ns, err)
+		}
+	}
+}
+
+func TestListProviders_FiltersByTier(t *testing.T) {
+	tests := []struct {
+		tier string
+		want string
+	}{
+		{tier: "official", want: "aws,azurerm"},
+		{tier: "Partner", want: "google"},
+		{tier: "community", want: ""},
+	}
+	for _, tt := range tests {
+		results, err := ListProviders(context.Background(), &fakeProviderListClient{}, ListOptions{Namespace: "hashicorp", Tier: tt.tier})
+		if err != nil {
+			t.Fatalf("tier %s: %v", tt.tier, err)
+		}
+		var names []string
+		for _, r := range results {
+			names = append(names, r.Name)
+		}
+		if got := strings.Join(names, ","); got != tt.want {
+			t.Fatalf("tier %s: expected %q, got %q", tt.tier, tt.want, got)
+		}
+	}
+
+	_, err := ListProviders(context.Background(), &fakeProviderListClient{}, ListOptions{Namespace: "hashicorp", Tier: "builtin"})
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || !strings.Contains(vErr.Message, "unsupported -tier: builtin") {
+		t.Fatalf("expected -tier validation error, got %v", err)
+	}
+}
@@ -13,8 +13,12 @@ import (
 const DefaultPathTemplate = "{out}/terraform/{namespace}/{provider}/{version}/docs/{category}/{slug}.{ext}"
 
 var (
-	reInvalidSegment = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
-	rePlaceholder    = regexp.MustCompile(`\{[^{}]+\}`)
+	reInvalidSegment     = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+	rePlaceholder        = regexp.MustCompile(`\{[^{}]+\}`)
+	reProviderIdentifier = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+	// reProviderIdentifierAnyCase is used with PreserveCase, for registries
+	// whose namespaces are case-sensitive.
+	reProviderIdentifierAnyCase = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*$`)
 )
 
 func BuildOutputPath(template string, vars map[string]string, outDir string) (string, error) {
@@ -119,6 +123,26 @@ func ensureNoSymlinkTraversal(baseAbs, targetAbs string) error {
 	return nil
 }
 
+// resolveSymlinkRoot resolves dir when dir itself is a symlink, so that the
+// traversal checks run against its target. Symlinks above dir are still
+// rejected; only its last component may be one.
+func resolveSymlinkRoot(dir string) (string, error) {
+	info, err := os.Lstat(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return dir, nil
+		}
+		return "", err
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		return dir, nil
+	}
+	if err := rejectSymlinkInAncestors(filepath.Dir(dir)); err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(dir)
+}
+
 func rejectSymlinkInAncestors(path string) error {
 	p := filepath.Clean(path)
 	prefixes := make([]string, 0, 8)
@@ -168,6 +192,20 @@ func sanitizeSegment(s string) string {
 	return s
 }
 
+// sanitizeFilenameAffix cleans a -filename-prefix or -filename-suffix like
+// sanitizeSegment, but keeps leading and trailing separators such as the
+// dash in "tf-" and allows the result to be empty.
+func sanitizeFilenameAffix(s string) string {
+	s = strings.TrimSpace(strings.ToLower(s))
+	return reInvalidSegment.ReplaceAllString(s, "-")
+}
+
+// slugFilename returns the {slug} value for a doc: its sanitized slug
+// wrapped in the filename prefix and suffix.
+func slugFilename(opts ExportOptions, slug string) string {
+	return opts.FilenamePrefix + sanitizeSegment(slug) + opts.FilenameSuffix
+}
+
 func extensionForFormat(format string) (string, error) {
 	switch format {
 	case "markdown":
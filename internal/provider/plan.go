@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ExportPlan lists how an export would change the tree under OutDir.
+// Paths are slash-separated and relative to OutDir.
+type ExportPlan struct {
+	Added     []string `json:"added"`
+	Modified  []string `json:"modified"`
+	Unchanged []string `json:"unchanged"`
+	// Removed lists files under the provider/version template root, or
+	// listed in the previous manifest, that the export would not produce.
+	// Only -clean actually deletes them.
+	Removed []string `json:"removed"`
+}
+
+// buildExportPlan compares planned files with what is on disk without
+// writing anything.
+func buildExportPlan(opts ExportOptions, ext string, planned []plannedFile) (*ExportPlan, error) {
+	plan := &ExportPlan{Added: []string{}, Modified: []string{}, Unchanged: []string{}, Removed: []string{}}
+	manifestAbs, err := filepath.Abs(manifestPathForOptions(opts))
+	if err != nil {
+		return nil, &ValidationError{Message: err.Error()}
+	}
+
+	plannedAbs := make(map[string]struct{}, len(planned))
+	for _, pf := range planned {
+		abs, err := filepath.Abs(pf.path)
+		if err != nil {
+			return nil, &ValidationError{Message: err.Error()}
+		}
+		plannedAbs[abs] = struct{}{}
+		rel := planRelPath(opts, pf.path)
+
+		existing, err := os.ReadFile(pf.path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			plan.Added = append(plan.Added, rel)
+		case err != nil:
+			return nil, &WriteError{Path: pf.path, Err: err}
+		case pf.unchanged || hashHex(opts.HashAlgorithm, existing) == pf.item.digest(opts.HashAlgorithm):
+			plan.Unchanged = append(plan.Unchanged, rel)
+		default:
+			plan.Modified = append(plan.Modified, rel)
+		}
+	}
+
+	orphans := make(map[string]struct{})
+	addOrphan := func(path string) error {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return &ValidationError{Message: err.Error()}
+		}
+		if _, ok := plannedAbs[abs]; ok || abs == manifestAbs {
+			return nil
+		}
+		orphans[planRelPath(opts, path)] = struct{}{}
+		return nil
+	}
+
+	templateRoot, err := deriveTemplateRoot(opts, ext)
+	if err != nil {
+		return nil, err
+	}
+	if isCleanRootScopedToProviderVersion(templateRoot, opts) {
+		err := filepath.WalkDir(templateRoot, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					return nil
+				}
+				return err
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			return addOrphan(path)
+		})
+		if err != nil {
+			return nil, &WriteError{Path: templateRoot, Err: err}
+		}
+	}
+
+	previous, err := readPreviousManifestDocs(opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, prev := range previous {
+		if !filepath.IsLocal(filepath.FromSlash(prev.Path)) {
+			continue
+		}
+		path := filepath.Join(opts.OutDir, filepath.FromSlash(prev.Path))
+		if info, err := os.Lstat(path); err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		if err := addOrphan(path); err != nil {
+			return nil, err
+		}
+	}
+	for rel := range orphans {
+		plan.Removed = append(plan.Removed, rel)
+	}
+	sort.Strings(plan.Removed)
+	return plan, nil
+}
+
+func planRelPath(opts ExportOptions, path string) string {
+	absOut, err := filepath.Abs(opts.OutDir)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	rel, err := filepath.Rel(absOut, absPath)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
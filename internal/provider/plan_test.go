@@ -0,0 +1,106 @@
+package provider
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func snapshotTree(t *testing.T, root string) map[string]string {
+	t.Helper()
+	files := map[string]string{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files[path] = string(b)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestExportDocs_PlanReportsDiffWithoutWriting(t *testing.T) {
+	outDir := t.TempDir()
+	opts := ExportOptions{
+		Namespace:  "hashicorp",
+		Name:       "aws",
+		Version:    "6.31.0",
+		Format:     "markdown",
+		OutDir:     outDir,
+		Categories: []string{"guides", "resources"},
+	}
+	if _, err := ExportDocs(context.Background(), &fakeAPIClient{}, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	docsDir := filepath.Join(outDir, "terraform", "hashicorp", "aws", "6.31.0", "docs")
+	if err := os.Remove(filepath.Join(docsDir, "guides", "tag-policy-compliance.md")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(docsDir, "resources", "aws_s3_bucket.md"), []byte("stale"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(docsDir, "resources", "aws_old.md"), []byte("orphan"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	before := snapshotTree(t, outDir)
+
+	opts.Plan = true
+	summary, err := ExportDocs(context.Background(), &fakeAPIClient{}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Plan == nil {
+		t.Fatal("expected a plan in the summary")
+	}
+	prefix := "terraform/hashicorp/aws/6.31.0/docs/"
+	want := &ExportPlan{
+		Added:     []string{prefix + "guides/tag-policy-compliance.md"},
+		Modified:  []string{prefix + "resources/aws_s3_bucket.md"},
+		Unchanged: []string{},
+		Removed:   []string{prefix + "resources/aws_old.md"},
+	}
+	if !reflect.DeepEqual(summary.Plan, want) {
+		t.Fatalf("unexpected plan:\n got %+v\nwant %+v", summary.Plan, want)
+	}
+	if summary.Written != 0 {
+		t.Fatalf("expected nothing written, got %d", summary.Written)
+	}
+	if after := snapshotTree(t, outDir); !reflect.DeepEqual(before, after) {
+		t.Fatalf("plan mutated the tree:\nbefore %v\nafter  %v", before, after)
+	}
+}
+
+func TestExportDocs_PlanUnchangedTree(t *testing.T) {
+	outDir := t.TempDir()
+	opts := ExportOptions{
+		Namespace:  "hashicorp",
+		Name:       "aws",
+		Version:    "6.31.0",
+		Format:     "markdown",
+		OutDir:     outDir,
+		Categories: []string{"guides", "resources"},
+	}
+	if _, err := ExportDocs(context.Background(), &fakeAPIClient{}, opts); err != nil {
+		t.Fatal(err)
+	}
+	opts.Plan = true
+	summary, err := ExportDocs(context.Background(), &fakeAPIClient{}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := summary.Plan
+	if len(p.Added)+len(p.Modified)+len(p.Removed) != 0 || len(p.Unchanged) != 2 {
+		t.Fatalf("expected no changes, got %+v", p)
+	}
+}
@@ -0,0 +1,95 @@
+package provider
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultExportConcurrency is the number of doc details ExportDocs fetches
+// at once when ExportOptions.Concurrency is 0.
+const DefaultExportConcurrency = 4
+
+// fetchPool runs doc fetches on at most size goroutines. The first error
+// cancels the pool's context, so in-flight fetches stop early and later
+// submits are refused, matching the fail-fast behavior of a sequential
+// export.
+type fetchPool struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	slots  chan struct{}
+	wg     sync.WaitGroup
+
+	mu  sync.Mutex
+	err error
+}
+
+// newFetchPool returns a pool and the context its fetches run under.
+func newFetchPool(ctx context.Context, size int) (*fetchPool, context.Context) {
+	if size < 1 {
+		size = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	return &fetchPool{ctx: ctx, cancel: cancel, slots: make(chan struct{}, size)}, ctx
+}
+
+// submit runs fn once a worker is free. It returns the pool's first error
+// without running fn once any fetch has failed.
+func (p *fetchPool) submit(fn func() error) error {
+	select {
+	case p.slots <- struct{}{}:
+	case <-p.ctx.Done():
+		return p.failure(p.ctx.Err())
+	}
+	if err := p.firstErr(); err != nil {
+		<-p.slots
+		return err
+	}
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer func() { <-p.slots }()
+		if err := fn(); err != nil {
+			p.setErr(err)
+		}
+	}()
+	return nil
+}
+
+// wait blocks until every submitted fetch has finished and returns the
+// first error any of them reported.
+func (p *fetchPool) wait() error {
+	p.wg.Wait()
+	return p.firstErr()
+}
+
+// failure stops the pool after err ended the caller's own work, such as a
+// failed listing, and returns the error to report: a fetch error that
+// caused it takes precedence over err.
+func (p *fetchPool) failure(err error) error {
+	p.cancel()
+	if poolErr := p.wait(); poolErr != nil {
+		return poolErr
+	}
+	return err
+}
+
+// stop cancels outstanding fetches and waits for them to return.
+func (p *fetchPool) stop() {
+	p.cancel()
+	p.wg.Wait()
+}
+
+func (p *fetchPool) setErr(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err == nil {
+		p.err = err
+		p.cancel()
+	}
+}
+
+func (p *fetchPool) firstErr() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
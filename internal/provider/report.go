@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// reportHeader is the first row of a -report CSV. The digest column is
+// named after the manifests' hash algorithm.
+func reportHeader(algo string) []string {
+	return []string{"provider", "version", "category", "slug", "path", "size", algo}
+}
+
+// WriteReportCSV writes one CSV row per doc recorded in the manifests of
+// summaries, with the current size of each file. Paths are relative to the
+// export's out dir, as in the manifest. The path is rejected when it, or any
+// directory above it, is a symlink.
+func WriteReportCSV(path string, summaries []ExportSummary) error {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return &ValidationError{Message: "-report must not be empty"}
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return &ValidationError{Message: fmt.Sprintf("invalid -report: %v", err)}
+	}
+	if err := ensureNoSymlinkTraversal(filepath.Dir(abs), abs); err != nil {
+		return &ValidationError{Message: fmt.Sprintf("unsafe -report %s: %v", abs, err)}
+	}
+
+	// Exports that wrote no manifest (-no-empty-manifest with zero docs)
+	// contribute no rows.
+	manifests := make([]manifest, 0, len(summaries))
+	loaded := make([]ExportSummary, 0, len(summaries))
+	for _, s := range summaries {
+		if s.Manifest == "" {
+			continue
+		}
+		b, err := os.ReadFile(filepath.FromSlash(s.Manifest))
+		if err != nil {
+			return &WriteError{Path: s.Manifest, Err: err}
+		}
+		var m manifest
+		if err := json.Unmarshal(b, &m); err != nil {
+			return &WriteError{Path: s.Manifest, Err: err}
+		}
+		manifests = append(manifests, m)
+		loaded = append(loaded, s)
+	}
+	// Every manifest of one export run shares its hash algorithm.
+	algo := HashSHA256
+	if len(manifests) > 0 {
+		if algo, err = normalizeHashAlgorithm(manifests[0].HashAlgorithm); err != nil {
+			return &WriteError{Path: loaded[0].Manifest, Err: err}
+		}
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(reportHeader(algo)); err != nil {
+		return &WriteError{Path: abs, Err: err}
+	}
+	for i, m := range manifests {
+		for _, doc := range m.Docs {
+			size := ""
+			if info, err := os.Stat(filepath.Join(loaded[i].OutDir, filepath.FromSlash(doc.Path))); err == nil {
+				size = strconv.FormatInt(info.Size(), 10)
+			}
+			if err := w.Write([]string{m.Provider, m.Version, doc.Category, doc.Slug, doc.Path, size, doc.digest(algo)}); err != nil {
+				return &WriteError{Path: abs, Err: err}
+			}
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return &WriteError{Path: abs, Err: err}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(abs), 0o755); err != nil {
+		return &WriteError{Path: abs, Err: err}
+	}
+	if err := os.WriteFile(abs, buf.Bytes(), 0o644); err != nil {
+		return &WriteError{Path: abs, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"context"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestWriteReportCSV_OneRowPerDoc(t *testing.T) {
+	client := &fakeCatalogClient{docs: []fakeCatalogDoc{
+		{ID: "1", Category: "guides", Slug: "getting-started", Title: "Getting Started", Content: "# guide"},
+		{ID: "2", Category: "resources", Slug: "aws_s3_bucket", Title: "aws_s3_bucket", Content: "# bucket"},
+	}}
+	outDir := t.TempDir()
+	summary, err := ExportDocs(context.Background(), client, ExportOptions{
+		Name:       "aws",
+		Version:    "6.31.0",
+		OutDir:     outDir,
+		Categories: []string{"guides", "resources"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reportPath := filepath.Join(t.TempDir(), "report.csv")
+	if err := WriteReportCSV(reportPath, []ExportSummary{*summary}); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(reportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("expected header and 2 rows, got %v", rows)
+	}
+	if got := strings.Join(rows[0], ","); got != "provider,version,category,slug,path,size,sha256" {
+		t.Fatalf("unexpected header: %s", got)
+	}
+
+	wantPaths := []string{
+		"terraform/hashicorp/aws/6.31.0/docs/guides/getting-started.md",
+		"terraform/hashicorp/aws/6.31.0/docs/resources/aws_s3_bucket.md",
+	}
+	for i, row := range rows[1:] {
+		if row[0] != "aws" || row[1] != "6.31.0" {
+			t.Fatalf("row %d: unexpected provider/version: %v", i, row)
+		}
+		if row[4] != wantPaths[i] {
+			t.Fatalf("row %d: expected path %s, got %s", i, wantPaths[i], row[4])
+		}
+		info, err := os.Stat(filepath.Join(outDir, filepath.FromSlash(row[4])))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if row[5] != strconv.FormatInt(info.Size(), 10) {
+			t.Fatalf("row %d: expected size %d, got %s", i, info.Size(), row[5])
+		}
+		if len(row[6]) != 64 {
+			t.Fatalf("row %d: expected a sha256 hex digest, got %q", i, row[6])
+		}
+	}
+}
+
+func TestWriteReportCSV_SkipsExportsWithoutManifest(t *testing.T) {
+	outDir := t.TempDir()
+	summary, err := ExportDocs(context.Background(), &fakeCatalogClient{}, ExportOptions{
+		Name:            "aws",
+		Version:         "6.31.0",
+		OutDir:          outDir,
+		Categories:      []string{"guides"},
+		NoEmptyManifest: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Manifest != "" {
+		t.Fatalf("expected no manifest, got %s", summary.Manifest)
+	}
+
+	reportPath := filepath.Join(t.TempDir(), "report.csv")
+	if err := WriteReportCSV(reportPath, []ExportSummary{*summary}); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(b); got != "provider,version,category,slug,path,size,sha256\n" {
+		t.Fatalf("expected a header-only report, got %q", got)
+	}
+}
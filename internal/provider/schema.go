@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// ManifestSchema returns a JSON Schema (draft 2020-12) describing
+// _manifest.json. It is derived from the manifest types by reflection so it
+// cannot drift from what ExportDocs writes; fields tagged omitempty are
+// optional, all others are required.
+func ManifestSchema() ([]byte, error) {
+	schema := schemaForType(reflect.TypeOf(manifest{}))
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = "tfdc provider export manifest"
+
+	b, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+func schemaForType(t reflect.Type) map[string]any {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return schemaForType(t.Elem())
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": schemaForType(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaForType(t.Elem())}
+	case reflect.Struct:
+		properties := make(map[string]any)
+		required := make([]string, 0, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" || !f.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			properties[name] = schemaForType(f.Type)
+			if !strings.Contains(","+opts+",", ",omitempty,") {
+				required = append(required, name)
+			}
+		}
+		return map[string]any{
+			"type":                 "object",
+			"properties":           properties,
+			"required":             required,
+			"additionalProperties": false,
+		}
+	default:
+		return map[string]any{}
+	}
+}
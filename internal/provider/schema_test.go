@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestManifestSchema_DescribesManifest(t *testing.T) {
+	b, err := ManifestSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var schema struct {
+		Type       string   `json:"type"`
+		Required   []string `json:"required"`
+		Properties map[string]struct {
+			Type  string `json:"type"`
+			Items struct {
+				Type     string   `json:"type"`
+				Required []string `json:"required"`
+			} `json:"items"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(b, &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+	if schema.Type != "object" {
+		t.Fatalf("expected object schema, got %q", schema.Type)
+	}
+	for _, field := range []string{"provider", "namespace", "version", "format", "generated_at", "total", "docs"} {
+		if !slices.Contains(schema.Required, field) {
+			t.Fatalf("expected %q to be required, got %v", field, schema.Required)
+		}
+	}
+	if got := schema.Properties["total"].Type; got != "integer" {
+		t.Fatalf("expected total to be an integer, got %q", got)
+	}
+	docs := schema.Properties["docs"]
+	if docs.Type != "array" || docs.Items.Type != "object" {
+		t.Fatalf("expected docs to be an array of objects, got %+v", docs)
+	}
+	for _, field := range []string{"doc_id", "category", "slug", "title", "path"} {
+		if !slices.Contains(docs.Items.Required, field) {
+			t.Fatalf("expected docs[].%s to be required, got %v", field, docs.Items.Required)
+		}
+	}
+}
+
+func TestManifestSchema_CoversWrittenManifest(t *testing.T) {
+	outDir := t.TempDir()
+	summary, err := ExportDocs(context.Background(), &fakeAPIClient{}, ExportOptions{
+		Name:       "aws",
+		Version:    "6.31.0",
+		OutDir:     outDir,
+		Categories: []string{"guides"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := os.ReadFile(filepath.FromSlash(summary.Manifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var written map[string]any
+	if err := json.Unmarshal(raw, &written); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := ManifestSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema struct {
+		Properties map[string]any `json:"properties"`
+	}
+	if err := json.Unmarshal(b, &schema); err != nil {
+		t.Fatal(err)
+	}
+	for key := range written {
+		if _, ok := schema.Properties[key]; !ok {
+			t.Fatalf("manifest field %q is missing from the schema", key)
+		}
+	}
+}
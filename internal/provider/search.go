@@ -9,12 +9,17 @@ import (
 
 // SearchOptions holds parameters for provider doc search.
 type SearchOptions struct {
-	Name      string
-	Namespace string
-	Service   string // slug-like search token to match against doc slugs
-	Type      string // category: resources, data-sources, etc.
-	Version   string // semver or "latest"
-	Limit     int
+	Name              string
+	Namespace         string
+	Service           string // slug-like search token to match against doc slugs
+	Type              string // category: resources, data-sources, etc., or "all"
+	Version           string // semver or "latest"
+	Limit             int    // max results; 0 returns every match
+	API               string // auto (default), v1, or v2: which listing endpoint to use
+	ExcludeDeprecated bool
+	PageSize          int    // page[size] for v2 listing; 0 keeps the registry default
+	Tier              string // only search providers of this tier; empty allows any
+	PreserveCase      bool   // keep Namespace/Name case in requests instead of lowercasing
 }
 
 // SearchResult represents one matching provider doc.
@@ -26,24 +31,42 @@ type SearchResult struct {
 	Provider      string `json:"provider"`
 	Namespace     string `json:"namespace"`
 	Version       string `json:"version"`
+	// Link is the doc's links.self URL from the listing, when provided.
+	Link string `json:"link,omitempty"`
+}
+
+// QualifiedID returns the doc ID qualified by its provider version, as
+// namespace/name/version#id. GetDoc and export -doc-ids accept it as well
+// as the bare ID.
+func (r SearchResult) QualifiedID() string {
+	return fmt.Sprintf("%s/%s/%s#%s", r.Namespace, r.Provider, r.Version, r.ProviderDocID)
+}
+
+// docLinks is the JSON:API links object on registry doc entries.
+type docLinks struct {
+	Self string `json:"self"`
 }
 
 // v1ProviderLatestResponse is the response from GET /v1/providers/{ns}/{name}.
 type v1ProviderLatestResponse struct {
 	Version string `json:"version"`
+	Tier    string `json:"tier"`
 }
 
 // v1ProviderDocsResponse is the response from GET /v1/providers/{ns}/{name}/{ver}.
 type v1ProviderDocsResponse struct {
-	Docs []v1ProviderDoc `json:"docs"`
+	Description string          `json:"description"`
+	Docs        []v1ProviderDoc `json:"docs"`
 }
 
 type v1ProviderDoc struct {
-	ID       string `json:"id"`
-	Title    string `json:"title"`
-	Category string `json:"category"`
-	Slug     string `json:"slug"`
-	Language string `json:"language"`
+	ID         string   `json:"id"`
+	Title      string   `json:"title"`
+	Category   string   `json:"category"`
+	Slug       string   `json:"slug"`
+	Language   string   `json:"language"`
+	Deprecated bool     `json:"deprecated"`
+	Links      docLinks `json:"links"`
 }
 
 // v1DocCategories are categories served by the v1 provider docs endpoint.
@@ -52,30 +75,141 @@ var v1DocCategories = map[string]bool{
 	"data-sources": true,
 }
 
+// SearchPage is one limited page of search results.
+type SearchPage struct {
+	Results []SearchResult
+	// HasMore reports that more docs matched than opts.Limit allowed.
+	HasMore bool
+	// PagesFetched counts the doc listing pages requested: one per v1
+	// listing, and every v2 page including the empty one that ends it.
+	PagesFetched int
+	// ByProvider maps each provider name searched by SearchProvidersPage
+	// to the number of results it contributed; nil for single searches.
+	ByProvider map[string]int
+}
+
 // SearchDocs searches provider documentation by service slug.
 func SearchDocs(ctx context.Context, client APIClient, opts SearchOptions) ([]SearchResult, error) {
-	if err := validateSearchOptions(&opts); err != nil {
+	page, err := SearchDocsPage(ctx, client, opts)
+	if err != nil {
 		return nil, err
 	}
+	return page.Results, nil
+}
+
+// SearchDocsPage is like SearchDocs but also reports whether the limit
+// truncated the results. It looks for one match past the limit to tell.
+func SearchDocsPage(ctx context.Context, client APIClient, opts SearchOptions) (SearchPage, error) {
+	results, pages, err := searchDocs(ctx, client, opts)
+	if err != nil {
+		return SearchPage{}, err
+	}
+	if opts.Limit > 0 && len(results) > opts.Limit {
+		return SearchPage{Results: results[:opts.Limit], HasMore: true, PagesFetched: pages}, nil
+	}
+	return SearchPage{Results: results, PagesFetched: pages}, nil
+}
+
+// SearchProvidersPage runs SearchDocsPage for each provider name in turn,
+// with opts.Name replaced, and merges the results in that order. opts.Limit
+// applies to each provider; HasMore is set when any provider was truncated.
+// ByProvider reports every name's match count, including zero.
+func SearchProvidersPage(ctx context.Context, client APIClient, opts SearchOptions, names []string) (SearchPage, error) {
+	merged := SearchPage{ByProvider: make(map[string]int, len(names))}
+	for _, name := range names {
+		opts.Name = name
+		page, err := SearchDocsPage(ctx, client, opts)
+		if err != nil {
+			return SearchPage{}, err
+		}
+		merged.Results = append(merged.Results, page.Results...)
+		merged.HasMore = merged.HasMore || page.HasMore
+		merged.PagesFetched += page.PagesFetched
+		merged.ByProvider[name] = len(page.Results)
+	}
+	return merged, nil
+}
+
+// AllSearchTypes is the -type value that searches every category.
+const AllSearchTypes = "all"
+
+// searchDocs returns up to opts.Limit+1 matches, or every match when
+// opts.Limit is 0, and the number of listing pages fetched to find them.
+func searchDocs(ctx context.Context, client APIClient, opts SearchOptions) ([]SearchResult, int, error) {
+	if err := validateSearchOptions(&opts); err != nil {
+		return nil, 0, err
+	}
+	if opts.Limit > 0 {
+		opts.Limit++
+	}
+
+	if opts.Tier != "" {
+		tier, err := resolveProviderTier(ctx, client, opts.Namespace, opts.Name)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !strings.EqualFold(tier, opts.Tier) {
+			return nil, 0, nil
+		}
+	}
 
 	version := opts.Version
 	if strings.EqualFold(version, "latest") || version == "" {
 		resolved, err := resolveLatestVersion(ctx, client, opts.Namespace, opts.Name)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		version = resolved
 	}
 
-	if v1DocCategories[opts.Type] {
+	if opts.Type == AllSearchTypes {
+		return searchAllTypes(ctx, client, opts, version)
+	}
+	return searchType(ctx, client, opts, version)
+}
+
+func searchType(ctx context.Context, client APIClient, opts SearchOptions, version string) ([]SearchResult, int, error) {
+	if useV1Search(opts) {
 		return searchV1(ctx, client, opts, version)
 	}
 	return searchV2(ctx, client, opts, version)
 }
 
+// searchAllTypes runs the per-category search for every default category in
+// order, dropping docs already matched under another category, until
+// opts.Limit results are collected or, when it is 0, every category is done.
+func searchAllTypes(ctx context.Context, client APIClient, opts SearchOptions, version string) ([]SearchResult, int, error) {
+	var results []SearchResult
+	pages := 0
+	seen := make(map[string]struct{})
+	for _, category := range defaultCategories {
+		catOpts := opts
+		catOpts.Type = category
+		if opts.Limit > 0 {
+			catOpts.Limit = opts.Limit - len(results)
+		}
+		matches, n, err := searchType(ctx, client, catOpts, version)
+		if err != nil {
+			return nil, 0, err
+		}
+		pages += n
+		for _, r := range matches {
+			if _, ok := seen[r.ProviderDocID]; ok {
+				continue
+			}
+			seen[r.ProviderDocID] = struct{}{}
+			results = append(results, r)
+		}
+		if opts.Limit > 0 && len(results) >= opts.Limit {
+			return results[:opts.Limit], pages, nil
+		}
+	}
+	return results, pages, nil
+}
+
 func validateSearchOptions(opts *SearchOptions) error {
-	opts.Name = strings.ToLower(strings.TrimSpace(opts.Name))
-	opts.Namespace = strings.ToLower(strings.TrimSpace(opts.Namespace))
+	opts.Name = normalizeProviderIdentifier(opts.Name, opts.PreserveCase)
+	opts.Namespace = normalizeProviderIdentifier(opts.Namespace, opts.PreserveCase)
 	opts.Service = strings.ToLower(strings.TrimSpace(opts.Service))
 	opts.Type = strings.ToLower(strings.TrimSpace(opts.Type))
 	opts.Version = strings.TrimSpace(opts.Version)
@@ -86,6 +220,9 @@ func validateSearchOptions(opts *SearchOptions) error {
 	if opts.Name == "" {
 		return &ValidationError{Message: "-name is required"}
 	}
+	if err := validateProviderIdentifiers(opts.Namespace, opts.Name, opts.PreserveCase); err != nil {
+		return err
+	}
 	if opts.Service == "" {
 		return &ValidationError{Message: "-service is required"}
 	}
@@ -97,19 +234,51 @@ func validateSearchOptions(opts *SearchOptions) error {
 	for _, c := range defaultCategories {
 		allowed[c] = struct{}{}
 	}
-	if _, ok := allowed[opts.Type]; !ok {
+	if _, ok := allowed[opts.Type]; !ok && opts.Type != AllSearchTypes {
 		return &ValidationError{Message: fmt.Sprintf("unsupported -type: %s", opts.Type)}
 	}
 
 	if opts.Version == "" {
 		opts.Version = "latest"
 	}
-	if opts.Limit <= 0 {
-		opts.Limit = 20
+	if opts.Limit < 0 {
+		return &ValidationError{Message: "-limit must be >= 0 (0 = no limit)"}
+	}
+
+	if err := validatePageSize(opts.PageSize); err != nil {
+		return err
+	}
+	tier, err := normalizeTier(opts.Tier)
+	if err != nil {
+		return err
+	}
+	opts.Tier = tier
+
+	opts.API = strings.ToLower(strings.TrimSpace(opts.API))
+	switch opts.API {
+	case "":
+		opts.API = "auto"
+	case "auto", "v1", "v2":
+	default:
+		return &ValidationError{Message: fmt.Sprintf("unsupported -api: %s (valid: auto, v1, v2)", opts.API)}
 	}
 	return nil
 }
 
+// useV1Search reports whether the v1 docs listing should serve the search.
+// In auto mode the category decides; v1/v2 force one endpoint for
+// providers whose listing is incomplete on the other.
+func useV1Search(opts SearchOptions) bool {
+	switch opts.API {
+	case "v1":
+		return true
+	case "v2":
+		return false
+	default:
+		return v1DocCategories[opts.Type]
+	}
+}
+
 func resolveLatestVersion(ctx context.Context, client APIClient, namespace, name string) (string, error) {
 	path := fmt.Sprintf("/v1/providers/%s/%s", url.PathEscape(namespace), url.PathEscape(name))
 	var resp v1ProviderLatestResponse
@@ -122,13 +291,23 @@ func resolveLatestVersion(ctx context.Context, client APIClient, namespace, name
 	return resp.Version, nil
 }
 
+// resolveProviderTier returns the tier the registry reports for a provider.
+func resolveProviderTier(ctx context.Context, client APIClient, namespace, name string) (string, error) {
+	path := fmt.Sprintf("/v1/providers/%s/%s", url.PathEscape(namespace), url.PathEscape(name))
+	var resp v1ProviderLatestResponse
+	if err := client.GetJSON(ctx, path, &resp); err != nil {
+		return "", err
+	}
+	return resp.Tier, nil
+}
+
 // searchV1 uses the v1 provider docs endpoint for resources/data-sources.
-func searchV1(ctx context.Context, client APIClient, opts SearchOptions, version string) ([]SearchResult, error) {
+func searchV1(ctx context.Context, client APIClient, opts SearchOptions, version string) ([]SearchResult, int, error) {
 	path := fmt.Sprintf("/v1/providers/%s/%s/%s",
 		url.PathEscape(opts.Namespace), url.PathEscape(opts.Name), url.PathEscape(version))
 	var resp v1ProviderDocsResponse
 	if err := client.GetJSON(ctx, path, &resp); err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	var results []SearchResult
@@ -142,6 +321,9 @@ func searchV1(ctx context.Context, client APIClient, opts SearchOptions, version
 		if !containsSlug(doc.Slug, opts.Service) {
 			continue
 		}
+		if opts.ExcludeDeprecated && doc.Deprecated {
+			continue
+		}
 		results = append(results, SearchResult{
 			ProviderDocID: doc.ID,
 			Title:         doc.Title,
@@ -150,26 +332,28 @@ func searchV1(ctx context.Context, client APIClient, opts SearchOptions, version
 			Provider:      opts.Name,
 			Namespace:     opts.Namespace,
 			Version:       version,
+			Link:          doc.Links.Self,
 		})
-		if len(results) >= opts.Limit {
+		if opts.Limit > 0 && len(results) >= opts.Limit {
 			break
 		}
 	}
-	return results, nil
+	return results, 1, nil
 }
 
 // searchV2 uses the v2 provider-docs endpoint for guides, functions, overview, etc.
-func searchV2(ctx context.Context, client APIClient, opts SearchOptions, version string) ([]SearchResult, error) {
+func searchV2(ctx context.Context, client APIClient, opts SearchOptions, version string) ([]SearchResult, int, error) {
 	providerVersionID, err := resolveProviderVersionID(ctx, client, opts.Namespace, opts.Name, version)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	var results []SearchResult
-	for page := 1; ; page++ {
-		docs, listErr := listProviderDocs(ctx, client, providerVersionID, opts.Type, page)
+	page := 1
+	for ; ; page++ {
+		docs, listErr := listProviderDocs(ctx, client, providerVersionID, opts.Type, page, opts.PageSize)
 		if listErr != nil {
-			return nil, listErr
+			return nil, 0, listErr
 		}
 		if len(docs) == 0 {
 			break
@@ -179,6 +363,9 @@ func searchV2(ctx context.Context, client APIClient, opts SearchOptions, version
 			if !containsSlug(doc.Attributes.Slug, opts.Service) {
 				continue
 			}
+			if opts.ExcludeDeprecated && doc.Attributes.Deprecated {
+				continue
+			}
 			results = append(results, SearchResult{
 				ProviderDocID: doc.ID,
 				Title:         doc.Attributes.Title,
@@ -187,13 +374,14 @@ func searchV2(ctx context.Context, client APIClient, opts SearchOptions, version
 				Provider:      opts.Name,
 				Namespace:     opts.Namespace,
 				Version:       version,
+				Link:          doc.Links.Self,
 			})
-			if len(results) >= opts.Limit {
-				return results, nil
+			if opts.Limit > 0 && len(results) >= opts.Limit {
+				return results, page, nil
 			}
 		}
 	}
-	return results, nil
+	return results, page, nil
 }
 
 // containsSlug checks if the doc slug contains the service token.
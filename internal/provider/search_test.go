@@ -15,7 +15,7 @@ type fakeSearchClient struct{}
 func (f *fakeSearchClient) GetJSON(_ context.Context, path string, dst any) error {
 	// GET /v1/providers/hashicorp/aws → latest version
 	if path == "/v1/providers/hashicorp/aws" {
-		b, _ := json.Marshal(map[string]any{"version": "6.31.0"})
+		b, _ := json.Marshal(map[string]any{"version": "6.31.0", "tier": "official"})
 		return json.Unmarshal(b, dst)
 	}
 
@@ -23,7 +23,7 @@ func (f *fakeSearchClient) GetJSON(_ context.Context, path string, dst any) erro
 	if path == "/v1/providers/hashicorp/aws/6.31.0" {
 		b, _ := json.Marshal(map[string]any{
 			"docs": []map[string]any{
-				{"id": "100", "title": "aws_ec2_instance", "category": "resources", "slug": "aws_ec2_instance", "language": "hcl"},
+				{"id": "100", "title": "aws_ec2_instance", "category": "resources", "slug": "aws_ec2_instance", "language": "hcl", "links": map[string]any{"self": "/v1/provider-docs/100"}},
 				{"id": "101", "title": "aws_s3_bucket", "category": "resources", "slug": "aws_s3_bucket", "language": "hcl"},
 				{"id": "102", "title": "aws_ec2_network_interface", "category": "resources", "slug": "aws_ec2_network_interface", "language": "hcl"},
 				{"id": "200", "title": "aws_ec2_instance", "category": "data-sources", "slug": "aws_ec2_instance", "language": "hcl"},
@@ -53,11 +53,16 @@ func (f *fakeSearchClient) GetJSON(_ context.Context, path string, dst any) erro
 		page := q.Get("page[number]")
 
 		var data []map[string]any
-		if cat == "guides" && page == "1" {
+		switch {
+		case cat == "guides" && page == "1":
 			data = []map[string]any{
-				{"id": "300", "attributes": map[string]any{"category": "guides", "slug": "ec2-guide", "title": "EC2 Guide"}},
+				{"id": "300", "attributes": map[string]any{"category": "guides", "slug": "ec2-guide", "title": "EC2 Guide"}, "links": map[string]any{"self": "/v2/provider-docs/300"}},
 				{"id": "301", "attributes": map[string]any{"category": "guides", "slug": "s3-guide", "title": "S3 Guide"}},
 			}
+		case cat == "resources" && page == "1":
+			data = []map[string]any{
+				{"id": "400", "attributes": map[string]any{"category": "resources", "slug": "aws_ec2_v2_only", "title": "aws_ec2_v2_only"}},
+			}
 		}
 		b, _ := json.Marshal(map[string]any{"data": data})
 		return json.Unmarshal(b, dst)
@@ -132,6 +137,23 @@ func TestSearchDocs_V2_Guides(t *testing.T) {
 	}
 }
 
+func TestSearchDocsPage_CountsPagesFetched(t *testing.T) {
+	for typ, want := range map[string]int{"resources": 1, "guides": 2} {
+		page, err := SearchDocsPage(context.Background(), &fakeSearchClient{}, SearchOptions{
+			Name:    "aws",
+			Service: "ec2",
+			Type:    typ,
+			Version: "6.31.0",
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", typ, err)
+		}
+		if page.PagesFetched != want {
+			t.Fatalf("%s: expected %d pages fetched, got %d", typ, want, page.PagesFetched)
+		}
+	}
+}
+
 func TestSearchDocs_LatestVersion(t *testing.T) {
 	results, err := SearchDocs(context.Background(), &fakeSearchClient{}, SearchOptions{
 		Name:    "aws",
@@ -166,6 +188,111 @@ func TestSearchDocs_Limit(t *testing.T) {
 	}
 }
 
+// manyGuidesClient lists 30 matching guides, 10 per page, and defers
+// everything else to fakeSearchClient.
+type manyGuidesClient struct {
+	fakeSearchClient
+}
+
+func (f *manyGuidesClient) GetJSON(ctx context.Context, path string, dst any) error {
+	if !strings.HasPrefix(path, "/v2/provider-docs?") {
+		return f.fakeSearchClient.GetJSON(ctx, path, dst)
+	}
+	u, err := url.Parse(path)
+	if err != nil {
+		return err
+	}
+	var page int
+	_, _ = fmt.Sscan(u.Query().Get("page[number]"), &page)
+	var data []map[string]any
+	if page >= 1 && page <= 3 {
+		for i := 0; i < 10; i++ {
+			id := fmt.Sprintf("%d", page*100+i)
+			data = append(data, map[string]any{"id": id, "attributes": map[string]any{"category": "guides", "slug": "ec2-guide-" + id, "title": "EC2 " + id}})
+		}
+	}
+	b, _ := json.Marshal(map[string]any{"data": data})
+	return json.Unmarshal(b, dst)
+}
+
+func TestSearchDocsPage_ZeroLimitReturnsEveryMatch(t *testing.T) {
+	page, err := SearchDocsPage(context.Background(), &manyGuidesClient{}, SearchOptions{
+		Name:    "aws",
+		Service: "ec2",
+		Type:    "guides",
+		Version: "6.31.0",
+		Limit:   0,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Results) != 30 || page.HasMore {
+		t.Fatalf("expected all 30 results without has_more, got %d (has_more=%v)", len(page.Results), page.HasMore)
+	}
+	if page.PagesFetched != 4 {
+		t.Fatalf("expected every listing page to be fetched, got %d", page.PagesFetched)
+	}
+}
+
+func TestSearchDocs_AllTypesMergesCategories(t *testing.T) {
+	results, err := SearchDocs(context.Background(), &fakeSearchClient{}, SearchOptions{
+		Name:    "aws",
+		Service: "ec2",
+		Type:    "all",
+		Version: "6.31.0",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []string
+	for _, r := range results {
+		got = append(got, r.Category+"/"+r.ProviderDocID)
+	}
+	want := "resources/100,resources/102,data-sources/200,guides/300"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("expected %s, got %s", want, strings.Join(got, ","))
+	}
+
+	page, err := SearchDocsPage(context.Background(), &fakeSearchClient{}, SearchOptions{
+		Name:    "aws",
+		Service: "ec2",
+		Type:    "all",
+		Limit:   3,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Results) != 3 || !page.HasMore {
+		t.Fatalf("expected 3 results with has_more across categories, got %d (has_more=%v)", len(page.Results), page.HasMore)
+	}
+}
+
+func TestSearchDocs_PopulatesLinkFromLinksSelf(t *testing.T) {
+	results, err := SearchDocs(context.Background(), &fakeSearchClient{}, SearchOptions{
+		Name:    "aws",
+		Service: "ec2",
+		Type:    "all",
+		Version: "6.31.0",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	links := make(map[string]string)
+	for _, r := range results {
+		links[r.ProviderDocID] = r.Link
+	}
+	want := map[string]string{
+		"100": "/v1/provider-docs/100",
+		"102": "",
+		"300": "/v2/provider-docs/300",
+	}
+	for id, link := range want {
+		if links[id] != link {
+			t.Fatalf("doc %s: expected link %q, got %q", id, link, links[id])
+		}
+	}
+}
+
 func TestSearchDocs_ValidationErrors(t *testing.T) {
 	tests := []struct {
 		name string
@@ -176,6 +303,7 @@ func TestSearchDocs_ValidationErrors(t *testing.T) {
 		{"missing service", SearchOptions{Name: "aws", Type: "resources"}, "-service is required"},
 		{"missing type", SearchOptions{Name: "aws", Service: "ec2"}, "-type is required"},
 		{"invalid type", SearchOptions{Name: "aws", Service: "ec2", Type: "invalid"}, "unsupported -type"},
+		{"negative limit", SearchOptions{Name: "aws", Service: "ec2", Type: "resources", Limit: -1}, "-limit must be >= 0"},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -193,3 +321,122 @@ func TestSearchDocs_ValidationErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestSearchDocs_RejectsInvalidProviderIdentifiers(t *testing.T) {
+	tests := []struct {
+		name string
+		opts SearchOptions
+		want string
+	}{
+		{"name with punctuation", SearchOptions{Name: "aws!", Service: "ec2", Type: "resources"}, "invalid -name"},
+		{"namespace with space", SearchOptions{Name: "aws", Namespace: "Hashi Corp", Service: "ec2", Type: "resources"}, "invalid -namespace"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := SearchDocs(context.Background(), &fakeSearchClient{}, tc.opts)
+			var vErr *ValidationError
+			if !errors.As(err, &vErr) {
+				t.Fatalf("expected ValidationError, got %T: %v", err, err)
+			}
+			if !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("expected error containing %q, got: %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestSearchDocs_AcceptsValidProviderIdentifiers(t *testing.T) {
+	_, err := SearchDocs(context.Background(), &fakeSearchClient{}, SearchOptions{
+		Name:      "aws",
+		Namespace: "hashicorp",
+		Service:   "ec2",
+		Type:      "resources",
+		Version:   "6.31.0",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSearchDocs_ForcedV2ListingForResources(t *testing.T) {
+	results, err := SearchDocs(context.Background(), &fakeSearchClient{}, SearchOptions{
+		Name:    "aws",
+		Service: "ec2",
+		Type:    "resources",
+		Version: "6.31.0",
+		API:     "v2",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].ProviderDocID != "400" {
+		t.Fatalf("expected v2-only resource doc 400, got %+v", results)
+	}
+}
+
+func TestSearchDocs_ForcedV1ListingForGuides(t *testing.T) {
+	results, err := SearchDocs(context.Background(), &fakeSearchClient{}, SearchOptions{
+		Name:    "aws",
+		Service: "ec2",
+		Type:    "guides",
+		Version: "6.31.0",
+		API:     "v1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The fake v1 listing has no guides; the v2 listing would return doc 300.
+	if len(results) != 0 {
+		t.Fatalf("expected v1 listing to be used, got %+v", results)
+	}
+}
+
+func TestSearchDocs_RejectsUnknownAPI(t *testing.T) {
+	_, err := SearchDocs(context.Background(), &fakeSearchClient{}, SearchOptions{
+		Name:    "aws",
+		Service: "ec2",
+		Type:    "resources",
+		API:     "v3",
+	})
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError, got %T: %v", err, err)
+	}
+}
+
+func TestSearchDocs_TierFilter(t *testing.T) {
+	for tier, want := range map[string]int{"official": 2, "community": 0} {
+		results, err := SearchDocs(context.Background(), &fakeSearchClient{}, SearchOptions{
+			Name:    "aws",
+			Service: "ec2",
+			Type:    "resources",
+			Tier:    tier,
+		})
+		if err != nil {
+			t.Fatalf("tier %s: %v", tier, err)
+		}
+		if len(results) != want {
+			t.Fatalf("tier %s: expected %d results, got %d", tier, want, len(results))
+		}
+	}
+}
+
+func TestSearchDocsPage_HasMoreWhenLimitTruncates(t *testing.T) {
+	for limit, wantMore := range map[int]bool{1: true, 2: false, 5: false} {
+		page, err := SearchDocsPage(context.Background(), &fakeSearchClient{}, SearchOptions{
+			Name:    "aws",
+			Service: "ec2",
+			Type:    "resources",
+			Limit:   limit,
+		})
+		if err != nil {
+			t.Fatalf("limit %d: %v", limit, err)
+		}
+		if page.HasMore != wantMore {
+			t.Fatalf("limit %d: expected HasMore=%v, got %v", limit, wantMore, page.HasMore)
+		}
+		if want := min(limit, 2); len(page.Results) != want {
+			t.Fatalf("limit %d: expected %d results, got %d", limit, want, len(page.Results))
+		}
+	}
+}
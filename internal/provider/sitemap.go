@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const sitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc string `xml:"loc"`
+}
+
+// normalizeSitemapBase validates a -sitemap base URL: it must be absolute
+// http(s) without a query or fragment. The trailing slash is dropped so
+// doc paths can be appended with a single separator.
+func normalizeSitemapBase(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", nil
+	}
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", &ValidationError{Message: fmt.Sprintf("invalid -sitemap base URL %q: must be an absolute http or https URL", s)}
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return "", &ValidationError{Message: fmt.Sprintf("invalid -sitemap base URL %q: must not have a query or fragment", s)}
+	}
+	return strings.TrimRight(s, "/"), nil
+}
+
+// sitemapPathForOptions places sitemap.xml next to the manifest.
+func sitemapPathForOptions(opts ExportOptions) string {
+	return filepath.Join(manifestRootForOptions(opts), "sitemap.xml")
+}
+
+// sitemapLoc joins base and a slash-separated path relative to OutDir,
+// escaping each path segment.
+func sitemapLoc(base, relPath string) string {
+	segments := strings.Split(relPath, "/")
+	for i, seg := range segments {
+		segments[i] = url.PathEscape(seg)
+	}
+	return base + "/" + strings.Join(segments, "/")
+}
+
+// writeSitemap writes one <url> per manifest doc, in manifest order.
+func writeSitemap(opts ExportOptions, docs []manifestItem) (string, error) {
+	sitemapPath := sitemapPathForOptions(opts)
+	if err := ensureNoSymlinkTraversal(opts.OutDir, sitemapPath); err != nil {
+		return "", &ValidationError{Message: fmt.Sprintf("unsafe sitemap path %s: %v", sitemapPath, err)}
+	}
+
+	set := sitemapURLSet{XMLNS: sitemapNamespace, URLs: make([]sitemapURL, 0, len(docs))}
+	for _, doc := range docs {
+		set.URLs = append(set.URLs, sitemapURL{Loc: sitemapLoc(opts.SitemapBase, doc.Path)})
+	}
+	b, err := xml.MarshalIndent(set, "", "  ")
+	if err != nil {
+		return "", &WriteError{Path: sitemapPath, Err: err}
+	}
+	content := append([]byte(xml.Header), b...)
+	if err := os.MkdirAll(filepath.Dir(sitemapPath), 0o755); err != nil {
+		return "", &WriteError{Path: sitemapPath, Err: err}
+	}
+	if err := os.WriteFile(sitemapPath, append(content, '\n'), 0o644); err != nil {
+		return "", &WriteError{Path: sitemapPath, Err: err}
+	}
+	return sitemapPath, nil
+}
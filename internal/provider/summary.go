@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WriteSummaryFile writes summaries as an indented JSON array to path. The
+// path is rejected when it, or any directory above it, is a symlink.
+func WriteSummaryFile(path string, summaries []ExportSummary) error {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return &ValidationError{Message: "-summary-file must not be empty"}
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return &ValidationError{Message: fmt.Sprintf("invalid -summary-file: %v", err)}
+	}
+	if err := ensureNoSymlinkTraversal(filepath.Dir(abs), abs); err != nil {
+		return &ValidationError{Message: fmt.Sprintf("unsafe -summary-file %s: %v", abs, err)}
+	}
+	if summaries == nil {
+		summaries = []ExportSummary{}
+	}
+
+	b, err := json.MarshalIndent(summaries, "", "  ")
+	if err != nil {
+		return &WriteError{Path: abs, Err: err}
+	}
+	if err := os.MkdirAll(filepath.Dir(abs), 0o755); err != nil {
+		return &WriteError{Path: abs, Err: err}
+	}
+	if err := os.WriteFile(abs, append(b, '\n'), 0o644); err != nil {
+		return &WriteError{Path: abs, Err: err}
+	}
+	return nil
+}
+
+// RunLogEntry is one line of an export run log.
+type RunLogEntry struct {
+	Timestamp  string `json:"timestamp"`
+	Provider   string `json:"provider"`
+	Version    string `json:"version"`
+	Written    int    `json:"written"`
+	Manifest   string `json:"manifest,omitempty"`
+	ExitStatus int    `json:"exit_status"`
+	Error      string `json:"error,omitempty"`
+}
+
+// AppendRunLog appends entries to path as NDJSON, creating it if needed.
+// All lines go out in a single O_APPEND write so concurrent runs sharing a
+// log do not interleave within a run.
+func AppendRunLog(path string, entries []RunLogEntry) error {
+	var buf []byte
+	for _, e := range entries {
+		b, err := json.Marshal(e)
+		if err != nil {
+			return &WriteError{Path: path, Err: err}
+		}
+		buf = append(append(buf, b...), '\n')
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return &WriteError{Path: path, Err: err}
+	}
+	if _, err := f.Write(buf); err != nil {
+		_ = f.Close()
+		return &WriteError{Path: path, Err: err}
+	}
+	if err := f.Close(); err != nil {
+		return &WriteError{Path: path, Err: err}
+	}
+	return nil
+}
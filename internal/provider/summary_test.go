@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteSummaryFile_WritesExportSummaryJSON(t *testing.T) {
+	outDir := t.TempDir()
+	summary, err := ExportDocs(context.Background(), &fakeAPIClient{}, ExportOptions{
+		Name:       "aws",
+		Version:    "6.31.0",
+		OutDir:     outDir,
+		Categories: []string{"guides", "resources"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	summaryPath := filepath.Join(t.TempDir(), "ci", "summary.json")
+	if err := WriteSummaryFile(summaryPath, []ExportSummary{*summary}); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(summaryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []ExportSummary
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("summary file is not valid JSON: %v\n%s", err, b)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected 1 summary, got %d", len(got))
+	}
+	if got[0].Written != 2 {
+		t.Fatalf("expected written=2, got %d", got[0].Written)
+	}
+	if got[0].Manifest != summary.Manifest || !strings.HasSuffix(got[0].Manifest, "/_manifest.json") {
+		t.Fatalf("unexpected manifest path: %q", got[0].Manifest)
+	}
+}
+
+func TestWriteSummaryFile_RejectsSymlink(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "elsewhere.json")
+	link := filepath.Join(dir, "summary.json")
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+
+	err := WriteSummaryFile(link, nil)
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+	if _, statErr := os.Stat(target); !os.IsNotExist(statErr) {
+		t.Fatalf("expected symlink target to stay untouched, stat err: %v", statErr)
+	}
+}
@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mkusaka/tfdc/internal/lockfile"
+)
+
+// LockCheck is the outcome of resolving one lockfile provider against the
+// registry. Err is nil when ProviderVersionID was resolved.
+type LockCheck struct {
+	Namespace         string
+	Name              string
+	Version           string
+	ProviderVersionID string
+	Err               error
+}
+
+// LockfileValidationError is returned by ValidateLocks when any provider
+// failed to resolve. It unwraps to the individual failures, so the exit
+// code follows their kind (not found, API error, ...).
+type LockfileValidationError struct {
+	Failed int
+	Total  int
+	Errors []error
+}
+
+func (e *LockfileValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "lockfile validation failed: %d of %d providers did not resolve", e.Failed, e.Total)
+	for _, err := range e.Errors {
+		fmt.Fprintf(&b, "\n  %v", err)
+	}
+	return b.String()
+}
+
+func (e *LockfileValidationError) Unwrap() []error { return e.Errors }
+
+// ValidateLocks resolves every locked provider to its provider-version ID
+// without listing or fetching any docs. Every lock is checked, so one
+// failure does not hide another; the returned checks are in lock order.
+func ValidateLocks(ctx context.Context, client APIClient, locks []lockfile.ProviderLock) ([]LockCheck, error) {
+	checks := make([]LockCheck, 0, len(locks))
+	var failures []error
+	for _, lock := range locks {
+		check := LockCheck{Namespace: lock.Namespace, Name: lock.Name, Version: lock.Version}
+		check.ProviderVersionID, check.Err = resolveProviderVersionID(ctx, client, strings.ToLower(lock.Namespace), strings.ToLower(lock.Name), lock.Version)
+		if check.Err != nil {
+			if ctx.Err() != nil {
+				return checks, ctx.Err()
+			}
+			failures = append(failures, fmt.Errorf("%s/%s@%s: %w", lock.Namespace, lock.Name, lock.Version, check.Err))
+		}
+		checks = append(checks, check)
+	}
+	if len(failures) > 0 {
+		return checks, &LockfileValidationError{Failed: len(failures), Total: len(locks), Errors: failures}
+	}
+	return checks, nil
+}
@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mkusaka/tfdc/internal/lockfile"
+)
+
+// versionsClient serves the provider-versions listing of each provider from
+// versions, keyed by "namespace/name" and mapping version to its ID.
+type versionsClient struct {
+	versions map[string]map[string]string
+}
+
+func (c *versionsClient) GetJSON(_ context.Context, path string, dst any) error {
+	key := strings.TrimSuffix(strings.TrimPrefix(path, "/v2/providers/"), "?include=provider-versions")
+	versions, ok := c.versions[key]
+	if !ok {
+		return fmt.Errorf("unexpected path: %s", path)
+	}
+	var included []any
+	for version, id := range versions {
+		included = append(included, map[string]any{
+			"type":       "provider-versions",
+			"id":         id,
+			"attributes": map[string]any{"version": version},
+		})
+	}
+	b, _ := json.Marshal(map[string]any{"included": included})
+	return json.Unmarshal(b, dst)
+}
+
+func (c *versionsClient) Get(_ context.Context, path string) ([]byte, error) {
+	return nil, fmt.Errorf("unexpected path: %s", path)
+}
+
+func TestValidateLocks_ReportsUnresolvedProvider(t *testing.T) {
+	client := &versionsClient{versions: map[string]map[string]string{
+		"hashicorp/aws":    {"6.31.0": "70800"},
+		"hashicorp/google": {"6.0.0": "60000"},
+	}}
+	locks := []lockfile.ProviderLock{
+		{Namespace: "hashicorp", Name: "aws", Version: "6.31.0"},
+		{Namespace: "hashicorp", Name: "google", Version: "9.9.9"},
+	}
+
+	checks, err := ValidateLocks(context.Background(), client, locks)
+	var valErr *LockfileValidationError
+	if !errors.As(err, &valErr) {
+		t.Fatalf("expected LockfileValidationError, got %v", err)
+	}
+	if valErr.Failed != 1 || valErr.Total != 2 {
+		t.Fatalf("expected 1 of 2 failed, got %d of %d", valErr.Failed, valErr.Total)
+	}
+	var nfErr *NotFoundError
+	if !errors.As(err, &nfErr) {
+		t.Fatalf("expected the failure to unwrap to NotFoundError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "hashicorp/google@9.9.9") {
+		t.Fatalf("expected the failing provider to be named, got %v", err)
+	}
+
+	if len(checks) != 2 {
+		t.Fatalf("expected a check per lock, got %d", len(checks))
+	}
+	if checks[0].Err != nil || checks[0].ProviderVersionID != "70800" {
+		t.Fatalf("expected aws to resolve to 70800, got %+v", checks[0])
+	}
+	if checks[1].Err == nil || checks[1].ProviderVersionID != "" {
+		t.Fatalf("expected google to fail, got %+v", checks[1])
+	}
+}
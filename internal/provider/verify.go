@@ -0,0 +1,150 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// VerifyOptions selects the manifest to check and, optionally, the provider
+// it must describe.
+type VerifyOptions struct {
+	ManifestPath string
+	// OutDir is the export root that manifest paths are relative to. When
+	// empty it is derived from a manifest in the default layout.
+	OutDir    string
+	Namespace string
+	Name      string
+	Version   string
+}
+
+// VerifyResult reports how many manifest entries were checked.
+type VerifyResult struct {
+	Manifest string `json:"manifest"`
+	Checked  int    `json:"checked"`
+}
+
+// VerifyProblem describes one manifest entry whose file does not match.
+type VerifyProblem struct {
+	Path   string
+	Reason string
+}
+
+// VerifyError is returned when files on disk do not match their manifest.
+type VerifyError struct {
+	Manifest string
+	Checked  int
+	Problems []VerifyProblem
+}
+
+func (e *VerifyError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "manifest verification failed: %d of %d files differ from %s", len(e.Problems), e.Checked, e.Manifest)
+	for _, p := range e.Problems {
+		fmt.Fprintf(&b, "\n  %s: %s", p.Path, p.Reason)
+	}
+	return b.String()
+}
+
+// VerifyManifest recomputes the SHA-256 of every file listed in a manifest
+// and reports files that are missing or whose digest differs.
+func VerifyManifest(opts VerifyOptions) (*VerifyResult, error) {
+	manifestPath := strings.TrimSpace(opts.ManifestPath)
+	if manifestPath == "" {
+		return nil, &ValidationError{Message: "-manifest is required"}
+	}
+	b, err := os.ReadFile(manifestPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, &NotFoundError{Message: fmt.Sprintf("manifest not found: %s", manifestPath)}
+		}
+		return nil, &ValidationError{Message: fmt.Sprintf("failed to read manifest: %v", err)}
+	}
+	var m manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, &ValidationError{Message: fmt.Sprintf("invalid manifest %s: %v", manifestPath, err)}
+	}
+	if err := checkManifestIdentity(m, opts); err != nil {
+		return nil, err
+	}
+
+	outDir := strings.TrimSpace(opts.OutDir)
+	if outDir == "" {
+		outDir, err = deriveOutDirFromManifest(manifestPath, m)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	algo, err := normalizeHashAlgorithm(m.HashAlgorithm)
+	if err != nil {
+		return nil, &ValidationError{Message: fmt.Sprintf("manifest %s: unsupported hash_algorithm %q", manifestPath, m.HashAlgorithm)}
+	}
+
+	var problems []VerifyProblem
+	for _, doc := range m.Docs {
+		rel := filepath.FromSlash(doc.Path)
+		if !filepath.IsLocal(rel) {
+			problems = append(problems, VerifyProblem{Path: doc.Path, Reason: "path escapes the export root"})
+			continue
+		}
+		want := doc.digest(algo)
+		if want == "" {
+			problems = append(problems, VerifyProblem{Path: doc.Path, Reason: fmt.Sprintf("no %s recorded in manifest; re-export to add checksums", algo)})
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(outDir, rel))
+		if err != nil {
+			reason := err.Error()
+			if errors.Is(err, fs.ErrNotExist) {
+				reason = "missing"
+			}
+			problems = append(problems, VerifyProblem{Path: doc.Path, Reason: reason})
+			continue
+		}
+		if got := hashHex(algo, content); got != want {
+			problems = append(problems, VerifyProblem{Path: doc.Path, Reason: fmt.Sprintf("checksum mismatch (manifest %s, disk %s)", want, got)})
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, &VerifyError{Manifest: manifestPath, Checked: len(m.Docs), Problems: problems}
+	}
+	return &VerifyResult{Manifest: manifestPath, Checked: len(m.Docs)}, nil
+}
+
+// checkManifestIdentity rejects a manifest for a different provider than the
+// one named by opts. Empty fields are not checked.
+func checkManifestIdentity(m manifest, opts VerifyOptions) error {
+	for _, f := range []struct{ flag, want, got string }{
+		{"-namespace", opts.Namespace, m.Namespace},
+		{"-name", opts.Name, m.Provider},
+		{"-version", opts.Version, m.Version},
+	} {
+		want := strings.TrimSpace(f.want)
+		if want != "" && !strings.EqualFold(want, f.got) {
+			return &ValidationError{Message: fmt.Sprintf("%s %s does not match manifest (%s)", f.flag, want, f.got)}
+		}
+	}
+	return nil
+}
+
+// deriveOutDirFromManifest strips the default
+// terraform/{namespace}/{provider}/{version}/docs layout from the manifest's
+// directory to find the export root.
+func deriveOutDirFromManifest(manifestPath string, m manifest) (string, error) {
+	abs, err := filepath.Abs(manifestPath)
+	if err != nil {
+		return "", &ValidationError{Message: fmt.Sprintf("failed to resolve -manifest: %v", err)}
+	}
+	dir := filepath.Dir(abs)
+	layout := filepath.Join("terraform", sanitizeSegment(m.Namespace), sanitizeSegment(m.Provider), sanitizeSegment(m.Version), "docs")
+	if !strings.HasSuffix(dir, string(os.PathSeparator)+layout) {
+		return "", &ValidationError{Message: "-out-dir is required when the manifest is not in the default export layout"}
+	}
+	return strings.TrimSuffix(dir, string(os.PathSeparator)+layout), nil
+}
@@ -0,0 +1,142 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVerifyManifest_DetectsModifiedAndMissingFiles(t *testing.T) {
+	client := &fakeCatalogClient{docs: []fakeCatalogDoc{
+		{ID: "1", Category: "guides", Slug: "getting-started", Title: "Getting Started", Content: "# guide"},
+		{ID: "2", Category: "resources", Slug: "aws_s3_bucket", Title: "aws_s3_bucket", Content: "# bucket"},
+	}}
+	outDir := t.TempDir()
+	summary, err := ExportDocs(context.Background(), client, ExportOptions{
+		Name:       "aws",
+		Version:    "6.31.0",
+		OutDir:     outDir,
+		Categories: []string{"guides", "resources"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := VerifyManifest(VerifyOptions{ManifestPath: summary.Manifest, Name: "aws", Version: "6.31.0"})
+	if err != nil {
+		t.Fatalf("expected matching tree to verify, got %v", err)
+	}
+	if result.Checked != 2 {
+		t.Fatalf("expected 2 files checked, got %d", result.Checked)
+	}
+
+	docsRoot := filepath.Join(outDir, "terraform", "hashicorp", "aws", "6.31.0", "docs")
+	if err := os.WriteFile(filepath.Join(docsRoot, "guides", "getting-started.md"), []byte("# tampered"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(docsRoot, "resources", "aws_s3_bucket.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = VerifyManifest(VerifyOptions{ManifestPath: summary.Manifest, OutDir: outDir})
+	var vErr *VerifyError
+	if !errors.As(err, &vErr) {
+		t.Fatalf("expected VerifyError, got %T (%v)", err, err)
+	}
+	msg := vErr.Error()
+	for _, want := range []string{
+		"2 of 2 files differ",
+		"guides/getting-started.md: checksum mismatch",
+		"resources/aws_s3_bucket.md: missing",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Fatalf("expected %q in error, got:\n%s", want, msg)
+		}
+	}
+}
+
+func TestVerifyManifest_RejectsOtherProvider(t *testing.T) {
+	client := &fakeCatalogClient{docs: []fakeCatalogDoc{
+		{ID: "1", Category: "guides", Slug: "getting-started", Title: "Getting Started", Content: "# guide"},
+	}}
+	summary, err := ExportDocs(context.Background(), client, ExportOptions{
+		Name:       "aws",
+		Version:    "6.31.0",
+		OutDir:     t.TempDir(),
+		Categories: []string{"guides"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = VerifyManifest(VerifyOptions{ManifestPath: summary.Manifest, Version: "6.30.0"})
+	var valErr *ValidationError
+	if !errors.As(err, &valErr) || !strings.Contains(valErr.Message, "-version 6.30.0 does not match manifest (6.31.0)") {
+		t.Fatalf("expected version mismatch error, got %v", err)
+	}
+}
+
+func TestExportDocs_SHA512ManifestRecordsAlgorithmAndVerifies(t *testing.T) {
+	client := &fakeCatalogClient{docs: []fakeCatalogDoc{
+		{ID: "1", Category: "guides", Slug: "getting-started", Title: "Getting Started", Content: "# guide"},
+	}}
+	outDir := t.TempDir()
+	summary, err := ExportDocs(context.Background(), client, ExportOptions{
+		Name:          "aws",
+		Version:       "6.31.0",
+		OutDir:        outDir,
+		Categories:    []string{"guides"},
+		HashAlgorithm: "SHA512",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(summary.Manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.HashAlgorithm != "sha512" {
+		t.Fatalf("expected hash_algorithm sha512, got %q", m.HashAlgorithm)
+	}
+	doc := m.Docs[0]
+	if len(doc.SHA512) != 128 || strings.Trim(doc.SHA512, "0123456789abcdef") != "" {
+		t.Fatalf("expected a 128-hex-char sha512 digest, got %q", doc.SHA512)
+	}
+	if doc.SHA256 != "" {
+		t.Fatalf("expected no sha256 alongside sha512, got %q", doc.SHA256)
+	}
+
+	if _, err := VerifyManifest(VerifyOptions{ManifestPath: summary.Manifest}); err != nil {
+		t.Fatalf("expected sha512 manifest to verify, got %v", err)
+	}
+	path := filepath.Join(outDir, filepath.FromSlash(doc.Path))
+	if err := os.WriteFile(path, []byte("# tampered"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var vErr *VerifyError
+	if _, err := VerifyManifest(VerifyOptions{ManifestPath: summary.Manifest}); !errors.As(err, &vErr) {
+		t.Fatalf("expected VerifyError after tampering, got %v", err)
+	}
+}
+
+func TestExportDocs_RejectsUnknownHashAlgorithm(t *testing.T) {
+	_, err := ExportDocs(context.Background(), &fakeCatalogClient{}, ExportOptions{
+		Name:          "aws",
+		Version:       "6.31.0",
+		OutDir:        t.TempDir(),
+		HashAlgorithm: "md5",
+	})
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+}
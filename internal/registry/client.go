@@ -1,15 +1,20 @@
 package registry
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand"
+	"mime"
 	"net/http"
 	"net/url"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/mkusaka/tfdc/internal/cache"
@@ -25,46 +30,121 @@ func (e *APIError) Error() string {
 	return fmt.Sprintf("registry API error: status=%d url=%s", e.StatusCode, e.URL)
 }
 
+// HTTPStatus returns the response status code, letting packages that do not
+// import registry tell statuses apart.
+func (e *APIError) HTTPStatus() int { return e.StatusCode }
+
+// ServiceUnavailableError reports a 503 with a non-JSON (typically HTML)
+// body, which the registry serves during maintenance. It unwraps to the
+// underlying *APIError; the body is only printed with -debug.
+type ServiceUnavailableError struct {
+	APIError *APIError
+}
+
+func (e *ServiceUnavailableError) Error() string {
+	return fmt.Sprintf("registry is temporarily unavailable (status=503, likely maintenance); try again later: url=%s", e.APIError.URL)
+}
+
+func (e *ServiceUnavailableError) Unwrap() error { return e.APIError }
+
+// RetryDeadlineError reports that retries were abandoned because the
+// context deadline would pass before the next attempt could start. It
+// unwraps to the error of the last attempt.
+type RetryDeadlineError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryDeadlineError) Error() string {
+	return fmt.Sprintf("retries abandoned due to deadline after %d attempt(s): %v", e.Attempts, e.Err)
+}
+
+func (e *RetryDeadlineError) Unwrap() error { return e.Err }
+
 type ConfigError struct {
 	Message string
 }
 
 func (e *ConfigError) Error() string { return e.Message }
 
+// DefaultMaxIdleConnsPerHost is the idle connection pool size per host used
+// when Config.MaxIdleConnsPerHost is zero. It is larger than the net/http
+// default (2) so concurrent fetches against a single registry reuse
+// connections instead of repeatedly dialing.
+const DefaultMaxIdleConnsPerHost = 16
+
+// DefaultRetryBackoff is the base delay before the first retry when
+// Config.RetryBackoff is zero. Later retries double it, up to maxRetryBackoff.
+const DefaultRetryBackoff = 250 * time.Millisecond
+
+const maxRetryBackoff = 10 * time.Second
+
 type Config struct {
-	BaseURL   string
-	Timeout   time.Duration
-	Retry     int
-	Insecure  bool
-	UserAgent string
-	Debug     bool
+	BaseURL             string
+	Timeout             time.Duration // per request; 0 disables it, leaving only the context deadline
+	Retry               int
+	Insecure            bool
+	UserAgent           string
+	Debug               bool
+	MaxIdleConnsPerHost int
+	DisableKeepAlives   bool
+	// PerHostConcurrency caps in-flight requests per URL host; 0 is unlimited.
+	PerHostConcurrency int
+	// RetryBackoff is the base retry delay; see DefaultRetryBackoff.
+	RetryBackoff time.Duration
+	// MirrorURL is a secondary registry base URL tried once when a request
+	// to BaseURL still fails with a network error or 5xx after retries.
+	MirrorURL string
+	// JitterSeed seeds the backoff jitter source. 0 seeds from the clock;
+	// a fixed value makes the backoff sequence reproducible.
+	JitterSeed int64
+	// NoRedirects returns 3xx responses as-is instead of following them,
+	// so they surface as an APIError with the redirect status.
+	NoRedirects bool
+	// Transport, when non-nil, is used instead of the default transport;
+	// Insecure, TLSPin, MaxIdleConnsPerHost and DisableKeepAlives are then
+	// ignored. It lets tests script responses without a server.
+	Transport http.RoundTripper
+	// RetryStats, when non-nil, records every retried request by URL.
+	RetryStats *RetryStats
+	// Explain, when non-nil, receives one "GET <url>" line per request
+	// before it is made, whether the cache or the network then serves it.
+	Explain io.Writer
+	// TLSPin, when set, is the hex SHA-256 of the server certificate's
+	// public key (SubjectPublicKeyInfo). Connections presenting any other
+	// key fail with a TLSPinError. It cannot be combined with Insecure.
+	TLSPin string
+	// MinTLSVersion is the lowest TLS version offered, tls.VersionTLS12 or
+	// tls.VersionTLS13; 0 keeps the crypto/tls default. See ParseTLSVersion.
+	MinTLSVersion uint16
+	// Refresh skips cache reads so every request goes to the network; the
+	// fresh responses are still written back to the cache.
+	Refresh bool
 }
 
 type Client struct {
 	baseURL    *url.URL
+	mirrorURL  *url.URL // nil when no mirror is configured
 	httpClient *http.Client
 	retry      int
 	cache      *cache.Store
 	userAgent  string
 	debug      bool
-}
+	hosts      *hostLimiter
+	backoff    time.Duration
+	retryStats *RetryStats
+	explain    io.Writer
+	refresh    bool
 
-func NewClient(cfg Config, cacheStore *cache.Store) (*Client, error) {
-	if cfg.BaseURL == "" {
-		cfg.BaseURL = "https://registry.terraform.io"
-	}
-	base, err := url.Parse(cfg.BaseURL)
-	if err != nil {
-		return nil, &ConfigError{Message: fmt.Sprintf("invalid base url: %v", err)}
-	}
-	if strings.TrimSpace(base.Scheme) == "" || strings.TrimSpace(base.Host) == "" {
-		return nil, &ConfigError{Message: fmt.Sprintf("invalid base url: scheme and host are required (%s)", cfg.BaseURL)}
-	}
-	scheme := strings.ToLower(strings.TrimSpace(base.Scheme))
-	if scheme != "http" && scheme != "https" {
-		return nil, &ConfigError{Message: fmt.Sprintf("invalid base url: scheme must be http or https (%s)", cfg.BaseURL)}
-	}
+	randMu sync.Mutex
+	rand   *rand.Rand
 
+	mu sync.Mutex
+}
+
+// newTransport clones the default transport with cfg's TLS, proxy and
+// connection pool settings.
+func newTransport(cfg Config) (*http.Transport, error) {
 	transport, ok := http.DefaultTransport.(*http.Transport)
 	if !ok {
 		return nil, &ConfigError{Message: "unexpected default transport type"}
@@ -76,30 +156,132 @@ func NewClient(cfg Config, cacheStore *cache.Store) (*Client, error) {
 		transport.TLSClientConfig = transport.TLSClientConfig.Clone()
 	}
 	transport.TLSClientConfig.InsecureSkipVerify = cfg.Insecure
+	if cfg.MinTLSVersion != 0 {
+		transport.TLSClientConfig.MinVersion = cfg.MinTLSVersion
+	}
+	if strings.TrimSpace(cfg.TLSPin) != "" {
+		pin, err := parseTLSPin(cfg.TLSPin)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig.VerifyPeerCertificate = verifyTLSPin(pin)
+	}
 	transport.Proxy = http.ProxyFromEnvironment
+	if cfg.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	} else {
+		transport.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	}
+	transport.DisableKeepAlives = cfg.DisableKeepAlives
+	return transport, nil
+}
+
+func NewClient(cfg Config, cacheStore *cache.Store) (*Client, error) {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "https://registry.terraform.io"
+	}
+	base, err := parseBaseURL("base url", cfg.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Insecure && strings.TrimSpace(cfg.TLSPin) != "" {
+		return nil, &ConfigError{Message: "tls pin cannot be combined with insecure TLS"}
+	}
+	switch cfg.MinTLSVersion {
+	case 0, tls.VersionTLS12, tls.VersionTLS13:
+	default:
+		return nil, &ConfigError{Message: fmt.Sprintf("unsupported minimum TLS version %#04x: use TLS 1.2 or 1.3", cfg.MinTLSVersion)}
+	}
+	var mirror *url.URL
+	if strings.TrimSpace(cfg.MirrorURL) != "" {
+		mirror, err = parseBaseURL("mirror url", strings.TrimSpace(cfg.MirrorURL))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	transport := cfg.Transport
+	if transport == nil {
+		transport, err = newTransport(cfg)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	client := &http.Client{
 		Timeout:   cfg.Timeout,
 		Transport: transport,
 	}
+	if cfg.NoRedirects {
+		client.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
 
 	userAgent := cfg.UserAgent
 	if userAgent == "" {
 		userAgent = "tfdc/dev"
 	}
 
+	retryBackoff := cfg.RetryBackoff
+	if retryBackoff <= 0 {
+		retryBackoff = DefaultRetryBackoff
+	}
+	seed := cfg.JitterSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
 	return &Client{
 		baseURL:    base,
+		mirrorURL:  mirror,
 		httpClient: client,
 		retry:      cfg.Retry,
 		cache:      cacheStore,
 		userAgent:  userAgent,
 		debug:      cfg.Debug,
+		hosts:      newHostLimiter(cfg.PerHostConcurrency),
+		backoff:    retryBackoff,
+		retryStats: cfg.RetryStats,
+		explain:    cfg.Explain,
+		refresh:    cfg.Refresh,
+		rand:       rand.New(rand.NewSource(seed)),
 	}, nil
 }
 
+// ParseTLSVersion maps "1.2" or "1.3" to its crypto/tls version constant.
+// Older versions are rejected; the empty string returns 0, the default.
+func ParseTLSVersion(s string) (uint16, error) {
+	switch strings.TrimSpace(s) {
+	case "":
+		return 0, nil
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, &ConfigError{Message: fmt.Sprintf("unsupported TLS version %q (valid: 1.2, 1.3)", s)}
+	}
+}
+
+// parseBaseURL validates a registry base URL; what names it in errors.
+func parseBaseURL(what, raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, &ConfigError{Message: fmt.Sprintf("invalid %s: %v", what, err)}
+	}
+	if strings.TrimSpace(u.Scheme) == "" || strings.TrimSpace(u.Host) == "" {
+		return nil, &ConfigError{Message: fmt.Sprintf("invalid %s: scheme and host are required (%s)", what, raw)}
+	}
+	scheme := strings.ToLower(strings.TrimSpace(u.Scheme))
+	if scheme != "http" && scheme != "https" {
+		return nil, &ConfigError{Message: fmt.Sprintf("invalid %s: scheme must be http or https (%s)", what, raw)}
+	}
+	return u, nil
+}
+
 func (c *Client) GetJSON(ctx context.Context, path string, dst any) error {
-	b, fromCache, err := c.get(ctx, path, true)
+	b, _, fromCache, err := c.get(ctx, path, true)
 	if err != nil {
 		return err
 	}
@@ -108,7 +290,7 @@ func (c *Client) GetJSON(ctx context.Context, path string, dst any) error {
 			return fmt.Errorf("failed to decode json response: %w", err)
 		}
 		// If cached payload is undecodable, treat it as cache miss and refetch.
-		fresh, _, refetchErr := c.get(ctx, path, false)
+		fresh, _, _, refetchErr := c.get(ctx, path, false)
 		if refetchErr != nil {
 			return refetchErr
 		}
@@ -120,87 +302,326 @@ func (c *Client) GetJSON(ctx context.Context, path string, dst any) error {
 	return nil
 }
 
+// GetJSONFresh is like GetJSON but always fetches from the network. The
+// response still refreshes the cache.
+func (c *Client) GetJSONFresh(ctx context.Context, path string, dst any) error {
+	b, _, _, err := c.get(ctx, path, false)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, dst); err != nil {
+		return fmt.Errorf("failed to decode json response: %w", err)
+	}
+	return nil
+}
+
 func (c *Client) Get(ctx context.Context, path string) ([]byte, error) {
-	b, _, err := c.get(ctx, path, true)
+	b, _, _, err := c.get(ctx, path, true)
 	if err != nil {
 		return nil, err
 	}
 	return b, nil
 }
 
-func (c *Client) get(ctx context.Context, path string, readCache bool) ([]byte, bool, error) {
+// GetWithContentType is Get that also returns the response's media type
+// (without parameters), from the network or the cache. The media type is ""
+// when the response carried no Content-Type.
+func (c *Client) GetWithContentType(ctx context.Context, path string) ([]byte, string, error) {
+	b, meta, _, err := c.get(ctx, path, true)
+	if err != nil {
+		return nil, "", err
+	}
+	contentType := meta.ContentType
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		contentType = mediaType
+	}
+	return b, contentType, nil
+}
+
+// get fetches path through the cache and the mirror fallback. It returns
+// the body, its stored or received headers, and whether it came from the
+// cache.
+func (c *Client) get(ctx context.Context, path string, readCache bool) ([]byte, cache.Meta, bool, error) {
 	fullURL, err := c.resolve(path)
 	if err != nil {
-		return nil, false, err
+		return nil, cache.Meta{}, false, err
+	}
+	c.explainRequest(fullURL)
+
+	// Responses served by the mirror are cached under the mirror URL.
+	mirrorURL := ""
+	if c.mirrorURL != nil && !isAbsoluteURL(path) {
+		if mirrorURL, err = resolveAgainst(c.mirrorURL, path); err != nil {
+			return nil, cache.Meta{}, false, err
+		}
 	}
 
-	if readCache && c.cache != nil {
-		if b, ok, err := c.cache.Get(http.MethodGet, fullURL); err == nil && ok {
-			if c.debug {
-				fmt.Fprintf(os.Stderr, "cache hit: %s\n", fullURL)
+	if readCache && !c.refresh && c.cache != nil {
+		for _, key := range []string{fullURL, mirrorURL} {
+			if key == "" {
+				continue
+			}
+			if b, meta, ok, err := c.cache.GetWithMeta(http.MethodGet, key); err == nil && ok {
+				if c.debug {
+					fmt.Fprintf(os.Stderr, "cache hit: %s\n", key)
+				}
+				return b, meta, true, nil
 			}
-			return b, true, nil
 		}
 	}
 
+	usedURL := fullURL
+	body, header, err := c.do(ctx, http.MethodGet, fullURL, nil)
+	if err != nil && mirrorURL != "" && shouldTryMirror(ctx, err) {
+		if c.debug {
+			fmt.Fprintf(os.Stderr, "primary failed (%v); trying mirror: %s\n", err, mirrorURL)
+		}
+		usedURL = mirrorURL
+		body, header, err = c.doWithRetries(ctx, http.MethodGet, mirrorURL, nil, 0)
+	}
+	if err != nil {
+		return nil, cache.Meta{}, false, err
+	}
+	meta := responseMeta(header)
+	if c.cache != nil {
+		_ = c.cache.SetWithMeta(http.MethodGet, usedURL, http.StatusOK, meta, body)
+	}
+	return body, meta, false, nil
+}
+
+// explainRequest writes the request about to be made to c.explain.
+func (c *Client) explainRequest(fullURL string) {
+	if c.explain == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, _ = fmt.Fprintf(c.explain, "GET %s\n", fullURL)
+}
+
+// shouldTryMirror reports whether a failed primary request may be retried
+// against the mirror: network errors and 5xx qualify, other statuses and
+// cancellation do not.
+func shouldTryMirror(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	return true
+}
+
+// responseMeta picks the headers the cache keeps from a response.
+func responseMeta(header http.Header) cache.Meta {
+	return cache.Meta{ContentType: header.Get("Content-Type"), LastModified: header.Get("Last-Modified")}
+}
+
+// GetIfModifiedSince fetches path, sending since as If-Modified-Since when it
+// is non-empty. A 304 reports notModified with no body; otherwise the body
+// and its Last-Modified header are returned and the cache is refreshed.
+// Without since no conditional request is needed, so path goes through the
+// cache and the mirror like Get; a cached entry with no recorded
+// Last-Modified is refetched.
+func (c *Client) GetIfModifiedSince(ctx context.Context, path, since string) (body []byte, lastModified string, notModified bool, err error) {
+	if since == "" {
+		b, meta, fromCache, err := c.get(ctx, path, true)
+		if err != nil {
+			return nil, "", false, err
+		}
+		if !fromCache || meta.LastModified != "" {
+			return b, meta.LastModified, false, nil
+		}
+		if b, meta, _, err = c.get(ctx, path, false); err != nil {
+			return nil, "", false, err
+		}
+		return b, meta.LastModified, false, nil
+	}
+
+	fullURL, err := c.resolve(path)
+	if err != nil {
+		return nil, "", false, err
+	}
+	c.explainRequest(fullURL)
+
+	var reqHeader http.Header
+	if since != "" {
+		reqHeader = http.Header{"If-Modified-Since": []string{since}}
+	}
+	body, header, err := c.do(ctx, http.MethodGet, fullURL, reqHeader)
+	if errors.Is(err, errNotModified) {
+		return nil, since, true, nil
+	}
+	if err != nil {
+		return nil, "", false, err
+	}
+	if c.cache != nil {
+		_ = c.cache.SetWithMeta(http.MethodGet, fullURL, http.StatusOK, responseMeta(header), body)
+	}
+	return body, header.Get("Last-Modified"), false, nil
+}
+
+// looksLikeJSON reports whether a response is JSON by its media type or,
+// failing that, by its first non-space byte.
+func looksLikeJSON(contentType string, body []byte) bool {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+			return true
+		}
+	}
+	trimmed := bytes.TrimSpace(body)
+	return len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[')
+}
+
+// retryDelay returns the wait before retry number attempt (1-based):
+// exponential growth from c.backoff, capped at maxRetryBackoff, with "equal
+// jitter" so the delay lies in [d/2, d].
+func (c *Client) retryDelay(attempt int) time.Duration {
+	d := c.backoff
+	for i := 1; i < attempt && d < maxRetryBackoff; i++ {
+		d *= 2
+	}
+	if d > maxRetryBackoff {
+		d = maxRetryBackoff
+	}
+	half := d / 2
+	c.randMu.Lock()
+	defer c.randMu.Unlock()
+	return half + time.Duration(c.rand.Int63n(int64(half)+1))
+}
+
+// waitRetry sleeps before retry number attempt, returning early with the
+// context error if ctx is done first. When ctx has a deadline that would
+// pass during the backoff, it gives up immediately with a
+// RetryDeadlineError wrapping lastErr instead of sleeping.
+func (c *Client) waitRetry(ctx context.Context, attempt int, lastErr error) error {
+	delay := c.retryDelay(attempt)
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= delay {
+		return &RetryDeadlineError{Attempts: attempt, Err: lastErr}
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// isIdempotentMethod reports whether requests with method may be retried
+// automatically. Only GET and HEAD are safe to repeat.
+func isIdempotentMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+// errNotModified is returned by do for a 304 to a conditional request.
+var errNotModified = errors.New("not modified")
+
+// do sends a request to fullURL with any extra reqHeader values and returns
+// the body of a 200 response. Transport errors, 429 and 5xx are retried up to
+// c.retry times, but only for idempotent methods.
+func (c *Client) do(ctx context.Context, method, fullURL string, reqHeader http.Header) ([]byte, http.Header, error) {
+	return c.doWithRetries(ctx, method, fullURL, reqHeader, c.retry)
+}
+
+// doWithRetries is do with an explicit retry budget.
+func (c *Client) doWithRetries(ctx context.Context, method, fullURL string, reqHeader http.Header, retries int) ([]byte, http.Header, error) {
+	if !isIdempotentMethod(method) {
+		retries = 0
+	}
+
 	var lastErr error
-	for attempt := 0; attempt <= c.retry; attempt++ {
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			if err := c.waitRetry(ctx, attempt, lastErr); err != nil {
+				return nil, nil, err
+			}
+			c.retryStats.record(fullURL)
+		}
 		if c.debug {
-			fmt.Fprintf(os.Stderr, "http get attempt=%d url=%s\n", attempt+1, fullURL)
+			fmt.Fprintf(os.Stderr, "http %s attempt=%d url=%s\n", strings.ToLower(method), attempt+1, fullURL)
 		}
 
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
+		req, err := http.NewRequestWithContext(ctx, method, fullURL, nil)
 		if err != nil {
-			return nil, false, err
+			return nil, nil, err
+		}
+		for key, values := range reqHeader {
+			req.Header[key] = values
 		}
 		req.Header.Set("User-Agent", c.userAgent)
 
+		release, err := c.hosts.acquire(ctx, req.URL.Host)
+		if err != nil {
+			return nil, nil, err
+		}
 		resp, err := c.httpClient.Do(req)
 		if err != nil {
+			release()
 			lastErr = err
-			if attempt < c.retry {
+			var pinErr *TLSPinError
+			if attempt < retries && !errors.As(err, &pinErr) {
 				continue
 			}
-			return nil, false, err
+			return nil, nil, err
 		}
 
 		body, readErr := io.ReadAll(resp.Body)
 		closeErr := resp.Body.Close()
+		release()
 		if readErr == nil && closeErr != nil {
 			readErr = closeErr
 		}
 		if readErr != nil {
 			lastErr = readErr
-			if attempt < c.retry {
+			if attempt < retries {
 				continue
 			}
-			return nil, false, readErr
+			return nil, nil, readErr
 		}
 
+		if resp.StatusCode == http.StatusNotModified && reqHeader.Get("If-Modified-Since") != "" {
+			return nil, resp.Header, errNotModified
+		}
 		if resp.StatusCode != http.StatusOK {
 			apiErr := &APIError{StatusCode: resp.StatusCode, URL: fullURL, Body: string(body)}
-			lastErr = apiErr
-			if (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError) && attempt < c.retry {
+			var statusErr error = apiErr
+			if resp.StatusCode == http.StatusServiceUnavailable && !looksLikeJSON(resp.Header.Get("Content-Type"), body) {
+				if c.debug {
+					fmt.Fprintf(os.Stderr, "service unavailable body: %s\n", body)
+				}
+				statusErr = &ServiceUnavailableError{APIError: apiErr}
+			}
+			lastErr = statusErr
+			if (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError) && attempt < retries {
 				continue
 			}
-			return nil, false, apiErr
-		}
-
-		if c.cache != nil {
-			_ = c.cache.Set(http.MethodGet, fullURL, resp.StatusCode, resp.Header.Get("Content-Type"), body)
+			return nil, nil, statusErr
 		}
 
-		return body, false, nil
+		return body, resp.Header, nil
 	}
 
 	if lastErr != nil {
-		return nil, false, lastErr
+		return nil, nil, lastErr
 	}
-	return nil, false, fmt.Errorf("unexpected error in get request")
+	return nil, nil, fmt.Errorf("unexpected error in %s request", strings.ToLower(method))
 }
 
 func (c *Client) resolve(path string) (string, error) {
-	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+	return resolveAgainst(c.baseURL, path)
+}
+
+func isAbsoluteURL(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+// resolveAgainst resolves path against base. Absolute URLs are returned
+// unchanged.
+func resolveAgainst(base *url.URL, path string) (string, error) {
+	if isAbsoluteURL(path) {
 		return path, nil
 	}
 	ref, err := url.Parse(path)
@@ -210,14 +631,14 @@ func (c *Client) resolve(path string) (string, error) {
 
 	// Keep a configured base path prefix (e.g. https://host/registry) for
 	// API paths that start with "/" so reverse-proxy deployments work.
-	if strings.HasPrefix(path, "/") && c.baseURL.Path != "" && c.baseURL.Path != "/" {
-		basePath := "/" + strings.Trim(strings.TrimSpace(c.baseURL.Path), "/")
+	if strings.HasPrefix(path, "/") && base.Path != "" && base.Path != "/" {
+		basePath := "/" + strings.Trim(strings.TrimSpace(base.Path), "/")
 		ref.Path = basePath + "/" + strings.TrimLeft(ref.Path, "/")
 		if ref.RawPath != "" {
-			baseRawPath := "/" + strings.Trim(strings.TrimSpace(c.baseURL.EscapedPath()), "/")
+			baseRawPath := "/" + strings.Trim(strings.TrimSpace(base.EscapedPath()), "/")
 			ref.RawPath = baseRawPath + "/" + strings.TrimLeft(ref.RawPath, "/")
 		}
 	}
 
-	return c.baseURL.ResolveReference(ref).String(), nil
+	return base.ResolveReference(ref).String(), nil
 }
@@ -2,11 +2,18 @@ package registry
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -176,3 +183,710 @@ func TestGetJSON_RefetchesWhenCachedPayloadIsInvalidJSON(t *testing.T) {
 		t.Fatalf("expected no additional network request on second call, got %d", requestCount.Load())
 	}
 }
+
+func TestGetJSON_RefreshSkipsCacheReadsButStoresFreshResponse(t *testing.T) {
+	var requestCount atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"v":"fresh"}`))
+	}))
+	defer srv.Close()
+
+	store, err := cache.NewStore(t.TempDir(), time.Hour, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := "/v2/provider-docs/1"
+	if err := store.Set(http.MethodGet, srv.URL+path, http.StatusOK, "application/json", []byte(`{"v":"stale"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := NewClient(Config{BaseURL: srv.URL, Timeout: 5 * time.Second, Refresh: true}, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dst map[string]string
+	if err := c.GetJSON(context.Background(), path, &dst); err != nil {
+		t.Fatal(err)
+	}
+	if dst["v"] != "fresh" || requestCount.Load() != 1 {
+		t.Fatalf("expected a fresh network response, got %v after %d requests", dst, requestCount.Load())
+	}
+
+	b, ok, err := store.Get(http.MethodGet, srv.URL+path)
+	if err != nil || !ok {
+		t.Fatalf("expected the fresh response to be cached, ok=%v err=%v", ok, err)
+	}
+	if string(b) != `{"v":"fresh"}` {
+		t.Fatalf("expected cache to hold the fresh response, got %s", b)
+	}
+}
+
+func TestNewClient_AppliesMaxIdleConnsPerHost(t *testing.T) {
+	c, err := NewClient(Config{BaseURL: "https://registry.terraform.io", Timeout: 5 * time.Second, MaxIdleConnsPerHost: 32}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport, ok := c.httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("unexpected transport type: %T", c.httpClient.Transport)
+	}
+	if transport.MaxIdleConnsPerHost != 32 {
+		t.Fatalf("expected MaxIdleConnsPerHost=32, got %d", transport.MaxIdleConnsPerHost)
+	}
+}
+
+func TestNewClient_DefaultsMaxIdleConnsPerHost(t *testing.T) {
+	c, err := NewClient(Config{BaseURL: "https://registry.terraform.io", Timeout: 5 * time.Second}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport, ok := c.httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("unexpected transport type: %T", c.httpClient.Transport)
+	}
+	if transport.MaxIdleConnsPerHost != DefaultMaxIdleConnsPerHost {
+		t.Fatalf("expected default MaxIdleConnsPerHost=%d, got %d", DefaultMaxIdleConnsPerHost, transport.MaxIdleConnsPerHost)
+	}
+}
+
+func TestNewClient_DisableKeepAlives(t *testing.T) {
+	c, err := NewClient(Config{BaseURL: "https://registry.terraform.io", Timeout: 5 * time.Second, DisableKeepAlives: true}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport, ok := c.httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("unexpected transport type: %T", c.httpClient.Transport)
+	}
+	if !transport.DisableKeepAlives {
+		t.Fatalf("expected keep-alives to be disabled")
+	}
+}
+
+func TestNewClient_AppliesMinTLSVersion(t *testing.T) {
+	for in, want := range map[string]uint16{"1.2": tls.VersionTLS12, "1.3": tls.VersionTLS13} {
+		version, err := ParseTLSVersion(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := NewClient(Config{BaseURL: "https://registry.terraform.io", MinTLSVersion: version}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		transport, ok := c.httpClient.Transport.(*http.Transport)
+		if !ok {
+			t.Fatalf("unexpected transport type: %T", c.httpClient.Transport)
+		}
+		if got := transport.TLSClientConfig.MinVersion; got != want {
+			t.Fatalf("%s: expected MinVersion %#04x, got %#04x", in, want, got)
+		}
+	}
+
+	for _, in := range []string{"1.0", "1.1", "tls1.3"} {
+		var cfgErr *ConfigError
+		if _, err := ParseTLSVersion(in); !errors.As(err, &cfgErr) {
+			t.Fatalf("%s: expected ConfigError, got %v", in, err)
+		}
+	}
+	var cfgErr *ConfigError
+	if _, err := NewClient(Config{MinTLSVersion: tls.VersionTLS11}, nil); !errors.As(err, &cfgErr) {
+		t.Fatalf("expected TLS 1.1 to be rejected, got %v", err)
+	}
+}
+
+func TestNewClient_TLSPin(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+	sum := sha256.Sum256(srv.Certificate().RawSubjectPublicKeyInfo)
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+
+	newPinnedClient := func(pin string) *Client {
+		t.Helper()
+		c, err := NewClient(Config{BaseURL: srv.URL, Timeout: 5 * time.Second, Retry: 2, RetryBackoff: time.Millisecond, TLSPin: pin}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.httpClient.Transport.(*http.Transport).TLSClientConfig.RootCAs = roots
+		return c
+	}
+
+	body, err := newPinnedClient(hex.EncodeToString(sum[:])).Get(context.Background(), "/")
+	if err != nil || string(body) != "ok" {
+		t.Fatalf("expected the matching pin to connect, got %q, %v", body, err)
+	}
+
+	wrong := strings.Repeat("ab:", sha256.Size-1) + "ab"
+	_, err = newPinnedClient(wrong).Get(context.Background(), "/")
+	var pinErr *TLSPinError
+	if !errors.As(err, &pinErr) {
+		t.Fatalf("expected TLSPinError, got %T: %v", err, err)
+	}
+	if pinErr.Got != hex.EncodeToString(sum[:]) {
+		t.Fatalf("expected the server key hash in the error, got %q", pinErr.Got)
+	}
+}
+
+func TestNewClient_TLSPinValidation(t *testing.T) {
+	for _, cfg := range []Config{
+		{TLSPin: "not-hex"},
+		{TLSPin: "abcd"},
+		{TLSPin: strings.Repeat("00", sha256.Size), Insecure: true},
+	} {
+		var cfgErr *ConfigError
+		if _, err := NewClient(cfg, nil); !errors.As(err, &cfgErr) {
+			t.Fatalf("%+v: expected ConfigError, got %v", cfg, err)
+		}
+	}
+}
+
+func TestGetWithContentType_ReturnsNetworkAndCacheMediaType(t *testing.T) {
+	var requestCount atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount.Add(1)
+		w.Header().Set("Content-Type", "application/vnd.api+json; charset=utf-8")
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	}))
+	defer srv.Close()
+
+	store, err := cache.NewStore(t.TempDir(), time.Hour, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := "/v2/provider-docs/1"
+	for i := 0; i < 2; i++ {
+		// The second pass must recover the content type from the cache
+		// entry.
+		c, err := NewClient(Config{BaseURL: srv.URL, Timeout: 5 * time.Second}, store)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, got, err := c.GetWithContentType(context.Background(), path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != "application/vnd.api+json" {
+			t.Fatalf("pass %d: expected application/vnd.api+json, got %q", i, got)
+		}
+	}
+	if requestCount.Load() != 1 {
+		t.Fatalf("expected second pass to be served from cache, got %d requests", requestCount.Load())
+	}
+}
+
+func TestGet_PerHostConcurrencyLimitsEachHostIndependently(t *testing.T) {
+	type hostStats struct {
+		inFlight atomic.Int32
+		peak     atomic.Int32
+	}
+	newServer := func(stats *hostStats) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := stats.inFlight.Add(1)
+			defer stats.inFlight.Add(-1)
+			for {
+				peak := stats.peak.Load()
+				if n <= peak || stats.peak.CompareAndSwap(peak, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			_, _ = w.Write([]byte(`{}`))
+		}))
+	}
+
+	var statsA, statsB hostStats
+	srvA := newServer(&statsA)
+	defer srvA.Close()
+	srvB := newServer(&statsB)
+	defer srvB.Close()
+
+	const limit = 2
+	c, err := NewClient(Config{BaseURL: srvA.URL, Timeout: 5 * time.Second, PerHostConcurrency: limit}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+	for i := 0; i < 8; i++ {
+		for _, base := range []string{srvA.URL, srvB.URL} {
+			wg.Add(1)
+			go func(u string) {
+				defer wg.Done()
+				if _, err := c.Get(context.Background(), u); err != nil {
+					errs <- err
+				}
+			}(fmt.Sprintf("%s/doc/%d", base, i))
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+
+	if got := statsA.peak.Load(); got > limit {
+		t.Fatalf("host A exceeded limit: peak %d > %d", got, limit)
+	}
+	if got := statsB.peak.Load(); got > limit {
+		t.Fatalf("host B exceeded limit: peak %d > %d", got, limit)
+	}
+	// Each host has its own semaphore, so both should reach the cap.
+	if statsA.peak.Load() != limit || statsB.peak.Load() != limit {
+		t.Fatalf("expected both hosts to reach %d in-flight requests, got A=%d B=%d", limit, statsA.peak.Load(), statsB.peak.Load())
+	}
+}
+
+func TestRetryStats_CountsRetriesPerURL(t *testing.T) {
+	// /flaky fails twice before succeeding, /once fails once, /ok never.
+	var mu sync.Mutex
+	failures := map[string]int{"/flaky": 2, "/once": 1}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if failures[r.URL.Path] > 0 {
+			failures[r.URL.Path]--
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	stats := NewRetryStats()
+	c, err := NewClient(Config{BaseURL: srv.URL, Timeout: 5 * time.Second, Retry: 3, RetryBackoff: time.Millisecond, RetryStats: stats}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"/flaky", "/once", "/ok"} {
+		if _, err := c.Get(context.Background(), path); err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+	}
+
+	if got := stats.Total(); got != 3 {
+		t.Fatalf("expected 3 retries, got %d", got)
+	}
+	if got := stats.ByURL()[srv.URL+"/flaky"]; got != 2 {
+		t.Fatalf("expected 2 retries of /flaky, got %d", got)
+	}
+	want := fmt.Sprintf("3 retries across 2 URLs\n  2 %s/flaky\n  1 %s/once\n", srv.URL, srv.URL)
+	if got := stats.Summary(); got != want {
+		t.Fatalf("unexpected summary:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDo_RetriesOnlyIdempotentMethods(t *testing.T) {
+	var requestCount atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Config{BaseURL: srv.URL, Timeout: 5 * time.Second, Retry: 2, RetryBackoff: time.Millisecond}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		method string
+		want   int32
+	}{
+		{method: http.MethodGet, want: 3},
+		{method: http.MethodHead, want: 3},
+		{method: http.MethodPost, want: 1},
+	}
+	for _, tt := range tests {
+		requestCount.Store(0)
+		_, _, err := c.do(context.Background(), tt.method, srv.URL+"/v1/anything", nil)
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("%s: expected 503 APIError, got %v", tt.method, err)
+		}
+		if got := requestCount.Load(); got != tt.want {
+			t.Fatalf("%s: expected %d requests, got %d", tt.method, tt.want, got)
+		}
+	}
+}
+
+// scriptedTransport answers each request with the next scripted status,
+// repeating the last one, and records the requests it saw.
+type scriptedTransport struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+}
+
+func (s *scriptedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := s.statuses[min(len(s.requests), len(s.statuses)-1)]
+	s.requests = append(s.requests, req)
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(fmt.Sprintf(`{"status":%d}`, status))),
+		Request:    req,
+	}, nil
+}
+
+func TestNewClient_UsesInjectedTransportForRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		statuses  []int
+		wantCalls int
+		wantErr   bool
+	}{
+		{name: "succeeds after two 503s", statuses: []int{503, 503, 200}, wantCalls: 3},
+		{name: "gives up after retries", statuses: []int{502}, wantCalls: 3, wantErr: true},
+		{name: "does not retry 404", statuses: []int{404, 200}, wantCalls: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := &scriptedTransport{statuses: tt.statuses}
+			c, err := NewClient(Config{
+				BaseURL:      "https://registry.example.test",
+				Retry:        2,
+				RetryBackoff: time.Millisecond,
+				UserAgent:    "tfdc/test",
+				Transport:    rt,
+			}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			body, err := c.Get(context.Background(), "/v1/providers/hashicorp/aws")
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("expected error=%v, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && string(body) != `{"status":200}` {
+				t.Fatalf("unexpected body: %s", body)
+			}
+			if len(rt.requests) != tt.wantCalls {
+				t.Fatalf("expected %d round trips, got %d", tt.wantCalls, len(rt.requests))
+			}
+			for _, req := range rt.requests {
+				if req.URL.String() != "https://registry.example.test/v1/providers/hashicorp/aws" {
+					t.Fatalf("unexpected request URL: %s", req.URL)
+				}
+				if got := req.Header.Get("User-Agent"); got != "tfdc/test" {
+					t.Fatalf("expected User-Agent tfdc/test, got %q", got)
+				}
+			}
+		})
+	}
+}
+
+func TestDo_AbandonsRetriesThatWouldOutliveDeadline(t *testing.T) {
+	var requestCount atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Config{BaseURL: srv.URL, Timeout: 5 * time.Second, Retry: 10, RetryBackoff: 200 * time.Millisecond, JitterSeed: 1}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	_, _, err = c.do(ctx, http.MethodGet, srv.URL+"/v1/flaky", nil)
+
+	var deadlineErr *RetryDeadlineError
+	if !errors.As(err, &deadlineErr) {
+		t.Fatalf("expected RetryDeadlineError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "retries abandoned due to deadline") {
+		t.Fatalf("unexpected message: %v", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected the last 502 to be wrapped, got %v", err)
+	}
+	if got := requestCount.Load(); got < 2 || got > 3 {
+		t.Fatalf("expected 2-3 attempts within the deadline, got %d", got)
+	}
+	if int(requestCount.Load()) != deadlineErr.Attempts {
+		t.Fatalf("expected Attempts=%d, got %d", requestCount.Load(), deadlineErr.Attempts)
+	}
+}
+
+func TestGet_NoRedirectsReturnsRedirectStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/moved" {
+			_, _ = w.Write([]byte(`{"ok":true}`))
+			return
+		}
+		http.Redirect(w, r, "/moved", http.StatusFound)
+	}))
+	defer srv.Close()
+
+	follow, err := NewClient(Config{BaseURL: srv.URL, Timeout: 5 * time.Second}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body, err := follow.Get(context.Background(), "/v1/providers"); err != nil || string(body) != `{"ok":true}` {
+		t.Fatalf("expected redirect to be followed by default, got %q, %v", body, err)
+	}
+
+	strict, err := NewClient(Config{BaseURL: srv.URL, Timeout: 5 * time.Second, NoRedirects: true}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = strict.Get(context.Background(), "/v1/providers")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusFound {
+		t.Fatalf("expected 302 APIError, got %v", err)
+	}
+}
+
+func TestRetryDelay_FixedJitterSeedIsReproducible(t *testing.T) {
+	newClient := func() *Client {
+		c, err := NewClient(Config{BaseURL: "https://registry.terraform.io", RetryBackoff: 100 * time.Millisecond, JitterSeed: 42}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	sequence := func(c *Client) []time.Duration {
+		var out []time.Duration
+		for attempt := 1; attempt <= 8; attempt++ {
+			out = append(out, c.retryDelay(attempt))
+		}
+		return out
+	}
+
+	first := sequence(newClient())
+	second := sequence(newClient())
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("attempt %d: delays differ with the same seed: %v vs %v", i+1, first, second)
+		}
+		base := 100 * time.Millisecond << i
+		if base > maxRetryBackoff {
+			base = maxRetryBackoff
+		}
+		if first[i] < base/2 || first[i] > base {
+			t.Fatalf("attempt %d: delay %v outside [%v, %v]", i+1, first[i], base/2, base)
+		}
+	}
+}
+
+func TestNewClient_ZeroTimeoutIsBoundedOnlyByContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(100 * time.Millisecond):
+		case <-r.Context().Done():
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Config{BaseURL: srv.URL, Timeout: 0}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.httpClient.Timeout != 0 {
+		t.Fatalf("expected no client timeout, got %s", c.httpClient.Timeout)
+	}
+
+	if _, err := c.Get(context.Background(), "/slow"); err != nil {
+		t.Fatalf("expected slow request to succeed without a timeout, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := c.Get(ctx, "/slow"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context deadline to bound the request, got %v", err)
+	}
+}
+
+func TestGet_ServiceUnavailableHTMLReturnsFriendlyError(t *testing.T) {
+	var requestCount atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount.Add(1)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte("<html><body>Down for maintenance</body></html>"))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Config{BaseURL: srv.URL, Timeout: 5 * time.Second, Retry: 1}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = c.Get(context.Background(), "/v1/providers/hashicorp/aws")
+	var unavailableErr *ServiceUnavailableError
+	if !errors.As(err, &unavailableErr) {
+		t.Fatalf("expected ServiceUnavailableError, got %T: %v", err, err)
+	}
+	if !strings.Contains(err.Error(), "temporarily unavailable") || !strings.Contains(err.Error(), "try again later") {
+		t.Fatalf("unexpected message: %v", err)
+	}
+	if strings.Contains(err.Error(), "<html>") {
+		t.Fatalf("expected HTML body to be kept out of the message: %v", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable || !strings.Contains(apiErr.Body, "maintenance") {
+		t.Fatalf("expected the raw APIError to stay reachable, got %+v", apiErr)
+	}
+	if got := requestCount.Load(); got != 2 {
+		t.Fatalf("expected 503 to still be retried, got %d requests", got)
+	}
+}
+
+func TestGet_ServiceUnavailableJSONStaysAPIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"errors":["overloaded"]}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Config{BaseURL: srv.URL, Timeout: 5 * time.Second}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.Get(context.Background(), "/v1/providers/hashicorp/aws")
+	var unavailableErr *ServiceUnavailableError
+	if errors.As(err, &unavailableErr) {
+		t.Fatalf("expected plain APIError for JSON 503, got %v", err)
+	}
+}
+
+func TestGetIfModifiedSince_NotModifiedAndLastModified(t *testing.T) {
+	const lastModified = "Mon, 02 Jun 2025 10:00:00 GMT"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-Modified-Since") == lastModified {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Last-Modified", lastModified)
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Config{BaseURL: srv.URL, Timeout: 5 * time.Second}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, got, notModified, err := c.GetIfModifiedSince(context.Background(), "/v2/provider-docs/1", "")
+	if err != nil || notModified || string(body) != `{"data":{}}` || got != lastModified {
+		t.Fatalf("unconditional fetch: body=%q lastModified=%q notModified=%v err=%v", body, got, notModified, err)
+	}
+
+	body, _, notModified, err = c.GetIfModifiedSince(context.Background(), "/v2/provider-docs/1", lastModified)
+	if err != nil || !notModified || body != nil {
+		t.Fatalf("conditional fetch: body=%q notModified=%v err=%v", body, notModified, err)
+	}
+}
+
+func TestGetIfModifiedSince_UnconditionalUsesCacheAndMirror(t *testing.T) {
+	const lastModified = "Mon, 02 Jun 2025 10:00:00 GMT"
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	primaryURL := primary.URL
+	primary.Close()
+
+	var mirrorHits atomic.Int32
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mirrorHits.Add(1)
+		w.Header().Set("Last-Modified", lastModified)
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	}))
+	defer mirror.Close()
+
+	store, err := cache.NewStore(t.TempDir(), time.Hour, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		c, err := NewClient(Config{BaseURL: primaryURL, MirrorURL: mirror.URL, Timeout: 5 * time.Second, Retry: 1, RetryBackoff: time.Millisecond}, store)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, got, notModified, err := c.GetIfModifiedSince(context.Background(), "/v2/provider-docs/1", "")
+		if err != nil || notModified || string(body) != `{"data":{}}` || got != lastModified {
+			t.Fatalf("pass %d: body=%q lastModified=%q notModified=%v err=%v", i, body, got, notModified, err)
+		}
+	}
+	if got := mirrorHits.Load(); got != 1 {
+		t.Fatalf("expected the second pass to be served from the cache, got %d mirror requests", got)
+	}
+}
+
+func TestGet_FallsBackToMirrorWhenPrimaryIsDown(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	primaryURL := primary.URL
+	primary.Close() // connection refused from here on
+
+	var mirrorHits atomic.Int32
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mirrorHits.Add(1)
+		if r.URL.Path != "/v2/provider-docs/1" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"from":"mirror"}`))
+	}))
+	defer mirror.Close()
+
+	store, err := cache.NewStore(t.TempDir(), time.Hour, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewClient(Config{BaseURL: primaryURL, MirrorURL: mirror.URL, Timeout: 5 * time.Second, Retry: 1, RetryBackoff: time.Millisecond}, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := c.Get(context.Background(), "/v2/provider-docs/1")
+	if err != nil {
+		t.Fatalf("expected mirror fallback to succeed, got %v", err)
+	}
+	if string(body) != `{"from":"mirror"}` {
+		t.Fatalf("unexpected body: %s", body)
+	}
+	if got := mirrorHits.Load(); got != 1 {
+		t.Fatalf("expected the mirror to be tried exactly once, got %d", got)
+	}
+
+	// The response is cached under the mirror URL and served from there.
+	if _, ok, _ := store.Get(http.MethodGet, mirror.URL+"/v2/provider-docs/1"); !ok {
+		t.Fatal("expected response cached under the mirror URL")
+	}
+	if _, ok, _ := store.Get(http.MethodGet, primaryURL+"/v2/provider-docs/1"); ok {
+		t.Fatal("expected nothing cached under the primary URL")
+	}
+	if _, err := c.Get(context.Background(), "/v2/provider-docs/1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := mirrorHits.Load(); got != 1 {
+		t.Fatalf("expected second read to hit the cache, got %d mirror requests", got)
+	}
+
+	// Client errors from the primary are not retried on the mirror.
+	notFound := httptest.NewServer(http.NotFoundHandler())
+	defer notFound.Close()
+	c, err = NewClient(Config{BaseURL: notFound.URL, MirrorURL: mirror.URL, Timeout: 5 * time.Second}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Get(context.Background(), "/v2/provider-docs/1"); err == nil {
+		t.Fatal("expected primary 404 to be returned")
+	}
+	if got := mirrorHits.Load(); got != 1 {
+		t.Fatalf("expected no mirror request after a 404, got %d", got)
+	}
+}
@@ -0,0 +1,47 @@
+package registry
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// hostLimiter caps the number of in-flight requests per URL host so that
+// fetches against different origins (e.g. the registry and raw GitHub) are
+// throttled independently. A zero limit disables throttling.
+type hostLimiter struct {
+	limit int
+
+	mu   sync.Mutex
+	sems map[string]chan struct{}
+}
+
+func newHostLimiter(limit int) *hostLimiter {
+	return &hostLimiter{limit: limit, sems: make(map[string]chan struct{})}
+}
+
+// acquire blocks until a slot for host is free or ctx is done. The returned
+// release func must be called exactly once when acquire succeeds.
+func (h *hostLimiter) acquire(ctx context.Context, host string) (func(), error) {
+	if h == nil || h.limit <= 0 {
+		return func() {}, nil
+	}
+	sem := h.semaphore(strings.ToLower(host))
+	select {
+	case sem <- struct{}{}:
+		return func() { <-sem }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (h *hostLimiter) semaphore(host string) chan struct{} {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	sem, ok := h.sems[host]
+	if !ok {
+		sem = make(chan struct{}, h.limit)
+		h.sems[host] = sem
+	}
+	return sem
+}
@@ -0,0 +1,80 @@
+package registry
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// RetryStats counts retried requests per URL. One value may be shared by
+// every client a command builds; it is safe for concurrent use.
+type RetryStats struct {
+	mu    sync.Mutex
+	byURL map[string]int
+}
+
+func NewRetryStats() *RetryStats {
+	return &RetryStats{byURL: make(map[string]int)}
+}
+
+// record counts one retry of url. It is a no-op on a nil receiver.
+func (s *RetryStats) record(url string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byURL[url]++
+}
+
+// Total returns the number of retries recorded across all URLs.
+func (s *RetryStats) Total() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	total := 0
+	for _, n := range s.byURL {
+		total += n
+	}
+	return total
+}
+
+// ByURL returns a copy of the per-URL retry counts.
+func (s *RetryStats) ByURL() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	counts := make(map[string]int, len(s.byURL))
+	for u, n := range s.byURL {
+		counts[u] = n
+	}
+	return counts
+}
+
+// Summary renders the counts as "N retries across M URLs" followed by one
+// indented "count url" line per URL, most retried first.
+func (s *RetryStats) Summary() string {
+	counts := s.ByURL()
+	urls := make([]string, 0, len(counts))
+	total := 0
+	for u, n := range counts {
+		urls = append(urls, u)
+		total += n
+	}
+	sort.Slice(urls, func(i, j int) bool {
+		if counts[urls[i]] != counts[urls[j]] {
+			return counts[urls[i]] > counts[urls[j]]
+		}
+		return urls[i] < urls[j]
+	})
+	out := fmt.Sprintf("%d %s across %d %s\n", total, plural(total, "retry", "retries"), len(urls), plural(len(urls), "URL", "URLs"))
+	for _, u := range urls {
+		out += fmt.Sprintf("  %d %s\n", counts[u], u)
+	}
+	return out
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
@@ -0,0 +1,52 @@
+package registry
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// TLSPinError reports a server certificate whose public key does not match
+// Config.TLSPin. It is not retried: another attempt would see the same key.
+type TLSPinError struct {
+	Want string
+	Got  string
+}
+
+func (e *TLSPinError) Error() string {
+	return fmt.Sprintf("tls pin mismatch: server public key sha256 is %s, expected %s", e.Got, e.Want)
+}
+
+// parseTLSPin decodes a hex SHA-256 pin. Colons between byte pairs, as
+// printed by openssl, and either case are accepted.
+func parseTLSPin(pin string) ([]byte, error) {
+	cleaned := strings.ReplaceAll(strings.TrimSpace(pin), ":", "")
+	b, err := hex.DecodeString(cleaned)
+	if err != nil || len(b) != sha256.Size {
+		return nil, &ConfigError{Message: fmt.Sprintf("invalid tls pin %q: expected the hex sha256 of the server certificate's public key", pin)}
+	}
+	return b, nil
+}
+
+// verifyTLSPin returns a tls.Config.VerifyPeerCertificate callback that
+// accepts the connection only when the SHA-256 of the leaf certificate's
+// SubjectPublicKeyInfo equals want. It runs after the usual chain checks.
+func verifyTLSPin(want []byte) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return &TLSPinError{Want: hex.EncodeToString(want), Got: "none"}
+		}
+		leaf, err := x509.ParseCertificate(rawCerts[0])
+		if err != nil {
+			return fmt.Errorf("tls pin: parse server certificate: %w", err)
+		}
+		got := sha256.Sum256(leaf.RawSubjectPublicKeyInfo)
+		if !bytes.Equal(got[:], want) {
+			return &TLSPinError{Want: hex.EncodeToString(want), Got: hex.EncodeToString(got[:])}
+		}
+		return nil
+	}
+}
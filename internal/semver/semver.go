@@ -0,0 +1,101 @@
+// Package semver compares the semantic version strings published by the
+// Terraform registry.
+package semver
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Compare returns -1, 0 or +1 as a is lower than, equal to, or higher than b.
+// A leading "v" and build metadata are ignored, and a release ranks above its
+// pre-releases. Strings that are not valid versions rank below valid ones and
+// compare lexically among themselves.
+func Compare(a, b string) int {
+	va, okA := parse(a)
+	vb, okB := parse(b)
+	switch {
+	case !okA && !okB:
+		return strings.Compare(a, b)
+	case !okA:
+		return -1
+	case !okB:
+		return 1
+	}
+	for i := range va.core {
+		if c := compareInt(va.core[i], vb.core[i]); c != 0 {
+			return c
+		}
+	}
+	return comparePrerelease(va.pre, vb.pre)
+}
+
+type version struct {
+	core [3]int
+	pre  []string
+}
+
+func parse(s string) (version, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+	var v version
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		v.pre = strings.Split(s[i+1:], ".")
+		s = s[:i]
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return version{}, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return version{}, false
+		}
+		v.core[i] = n
+	}
+	return v, true
+}
+
+func comparePrerelease(a, b []string) int {
+	switch {
+	case len(a) == 0 && len(b) == 0:
+		return 0
+	case len(a) == 0:
+		return 1
+	case len(b) == 0:
+		return -1
+	}
+	for i := 0; i < len(a) && i < len(b); i++ {
+		na, errA := strconv.Atoi(a[i])
+		nb, errB := strconv.Atoi(b[i])
+		var c int
+		switch {
+		case errA == nil && errB == nil:
+			c = compareInt(na, nb)
+		case errA == nil:
+			c = -1 // numeric identifiers rank below alphanumeric ones
+		case errB == nil:
+			c = 1
+		default:
+			c = strings.Compare(a[i], b[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return compareInt(len(a), len(b))
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}